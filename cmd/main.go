@@ -1,7 +1,15 @@
 package main
 
-import "go-service-template/internal/app"
+import (
+	"os"
+
+	"go-service-template/internal/app"
+)
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		app.MigrateMain(os.Args[2:])
+		return
+	}
 	app.Main()
 }
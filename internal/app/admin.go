@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/handler"
+	"go-service-template/internal/app/infrastructure"
+	echoMiddleware "go-service-template/internal/app/infrastructure/echo"
+	"go-service-template/internal/app/service"
+)
+
+const (
+	publicServer = "public"
+	adminServer  = "admin"
+)
+
+func adminModule() Module {
+	return Module{
+		Name:      "admin",
+		DependsOn: []string{"services", "kafka-consumer", "http"},
+		Init:      initAdmin,
+		Start:     startAdmin,
+	}
+}
+
+// initAdmin - prepares admin server on a separate address. Requests must have bearer token
+func initAdmin(ctx context.Context, a *App) error {
+	if !a.Config.Admin.Enabled {
+		a.LogInfo(ctx, "admin API is disabled")
+		return nil
+	}
+	var consumer service.ConsumerController
+	if a.Consumer != nil {
+		consumer = a.Consumer
+	}
+	a.AdminHandler = handler.NewAdminHandler(service.NewAdminService(a.Config.Masked(), consumer, a.routes))
+
+	e := echo.New()
+	e.HideBanner = true
+	e.Pre(echoMiddleware.PrepareRequestID)
+	e.Pre(echoMiddleware.PrepareCorrelationID)
+	e.Pre(echoMiddleware.PrepareLogger)
+	e.Use(echoMiddleware.RequestLogger)
+	e.Use(middleware.Recover())
+	e.Use(echoMiddleware.AdminAuth(a.Config.Admin.Token))
+	e.Validator = handler.NewRequestValidator()
+	e.HTTPErrorHandler = handler.NewErrorHandler(a.Config.Errors)
+	a.AdminEcho = e
+
+	admin := e.Group("/admin")
+	prepareAdminRoutes(a, admin)
+	admin.GET("/log-level", a.AdminHandler.LogLevels)
+	admin.PUT("/log-level", a.AdminHandler.SetLogLevel)
+	admin.PUT("/log-level/:component", a.AdminHandler.SetComponentLogLevel)
+	admin.DELETE("/log-level/:component", a.AdminHandler.ResetComponentLogLevel)
+	admin.GET("/config", a.AdminHandler.Config)
+	admin.GET("/consumer", a.AdminHandler.ConsumerState)
+	admin.POST("/consumer/pause", a.AdminHandler.PauseConsumer)
+	admin.POST("/consumer/resume", a.AdminHandler.ResumeConsumer)
+	admin.GET("/routes", a.AdminHandler.Routes)
+	admin.GET("/goroutines", a.AdminHandler.Goroutines)
+	// metrics (db table sizes etc.) in expvar format
+	e.GET("/debug/vars", echo.WrapHandler(expvar.Handler()))
+
+	a.Lifecycle.Add(infrastructure.PhaseInput, "admin-http", infrastructure.CloserFunc(e.Shutdown))
+	return nil
+}
+
+func startAdmin(ctx context.Context, a *App) error {
+	if a.AdminEcho == nil {
+		return nil
+	}
+	go func() {
+		if err := a.AdminEcho.Start(a.Config.Admin.Address); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.LogFatal(ctx, "shutting down the admin server", err)
+		}
+	}()
+	return nil
+}
+
+// prepareAdminRoutes - registers routes of jobs and dependencies. They are served only by admin server,
+// so they aren't available if admin server is disabled
+func prepareAdminRoutes(a *App, admin *echo.Group) {
+	admin.GET("/jobs", a.JobHandler.List)
+	admin.GET("/jobs/dead", a.JobHandler.ListDead)
+	admin.POST("/jobs/:id/retry", a.JobHandler.Retry)
+	admin.POST("/jobs/dead/:id/retry", a.JobHandler.RetryDead)
+	admin.GET("/dependencies", a.DependencyHandler.Report)
+	admin.GET("/dependencies/:name/history", a.DependencyHandler.History)
+}
+
+// routes - returns routes of public and admin servers
+func (a *App) routes() []dto.Route {
+	var res []dto.Route
+	servers := []struct {
+		name string
+		e    *echo.Echo
+	}{{publicServer, a.Echo}, {adminServer, a.AdminEcho}}
+	for _, s := range servers {
+		if s.e == nil {
+			continue
+		}
+		for _, r := range s.e.Routes() {
+			res = append(res, dto.Route{Server: s.name, Method: r.Method, Path: r.Path})
+		}
+	}
+	return res
+}
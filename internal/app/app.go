@@ -4,41 +4,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"time"
 
-	"go-service-template/internal/app/rest"
-
 	"github.com/labstack/echo/v4"
 	cfg "go-service-template/internal/app/config"
 	"go-service-template/internal/app/handler"
 	"go-service-template/internal/app/infrastructure"
-	httpClient "go-service-template/internal/app/infrastructure/http"
+	echoMiddleware "go-service-template/internal/app/infrastructure/echo"
 	"go-service-template/internal/app/infrastructure/kafka"
 	"go-service-template/internal/app/infrastructure/postgres"
-	"go-service-template/internal/app/repository"
+	"go-service-template/internal/app/rest"
 	"go-service-template/internal/app/service"
 )
 
 var (
-	appConfig           *cfg.AppConfig
-	dbHandler           *postgres.PostgresqlHandlerTX
-	producer            *kafka.MessageProducer
-	pingDBRepository    service.PingRepository
-	pingKafkaRepository service.PingRepository
-	pingService         handler.PingService
-	pingHandler         *handler.PingHandler
-	pingClient          *rest.PingClient
-	resources           []Resource
-	e                   *echo.Echo
-	logger              *infrastructure.Logger
-
-	consumer kafka.MessageConsumer
-)
+	// ErrModuleNotFound - "module dependency isn't registered" error
+	ErrModuleNotFound = errors.New("module dependency isn't registered")
+
+	// ErrModuleCycle - "cyclic module dependencies" error
+	ErrModuleCycle = errors.New("cyclic module dependencies")
+
+	// ErrAppBuilt - "app is already built" error
+	ErrAppBuilt = errors.New("app is already built")
+
+	// ErrAppNotBuilt - "app isn't built" error
+	ErrAppNotBuilt = errors.New("app isn't built")
 
-const (
-	attemptsCount   = 10
-	attemptInterval = time.Second * 20
+	// ErrTenantClaimWithoutAuth - "tenant JWT claim requires authentication" error
+	ErrTenantClaimWithoutAuth = errors.New("tenant JWT claim requires authentication")
 )
 
 // Resource interface used for gracefully shutdown
@@ -47,116 +40,213 @@ type Resource interface {
 	Close(ctx context.Context) error
 }
 
-// initDatabase  initialize new dbHandler. If database is not available then "attemptInterval" attempts will be made
-func initDatabase(ctx context.Context) {
-	var err error
-	for ind := 0; ind < attemptsCount; ind++ {
-		select {
-		case <-ctx.Done():
-			logger.Info().Msg("database initialization aborted due to canceled context")
-			return
-		default:
-			logger.Info().Msgf("try to initialize database: attempt #%d", ind+1)
-			dataSource := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s", appConfig.Database.Username, appConfig.Database.Password, appConfig.Database.Address, appConfig.Database.Port, appConfig.Database.DB) //nolint:nosprintfhostport
-			dbHandler, err = postgres.NewPostgresqlHandlerTX(ctx, dataSource, appConfig.PgPool)
-			if err != nil {
-				logger.Error().Err(err).Msg("can't create postgres handler")
-			} else {
-
-				resources = append(resources, dbHandler)
-				logger.Info().Msg("db successfully initialized")
-				return
+// Module - subsystem of the application. Init builds components of the module and stores them into App,
+// Start (optional) runs them. Modules are initialized and started after the modules they depend on
+type Module struct {
+	Name      string
+	DependsOn []string
+	Init      func(ctx context.Context, app *App) error
+	Start     func(ctx context.Context, app *App) error
+}
+
+// App - application container. Components are built by modules and exposed as fields,
+// so several instances can be created (e.g. in tests) and any module can be replaced by Register
+type App struct {
+	infrastructure.SugarLogger
+	Config    *cfg.AppConfig
+	Lifecycle *infrastructure.Lifecycle
+	Health    *service.HealthService
+
+	// postgres
+	DB                  *postgres.PostgresqlHandlerTX
+	Listener            *postgres.Listener
+	PartitionMaintainer *postgres.PartitionMaintainer
+	JobQueue            *postgres.JobQueue
+
+	// kafka
+	Producer *kafka.MessageProducer
+	Consumer kafka.MessageConsumer
+
+	// downstream services
+	PingClient        *rest.PingClient
+	DependencyMonitor *service.DependencyMonitor
+
+	// services and handlers
+	PingService       handler.PingService
+	PingHandler       *handler.PingHandler
+	JobHandler        *handler.JobHandler
+	HealthHandler     *handler.HealthHandler
+	DependencyHandler *handler.DependencyHandler
+	AdminHandler      *handler.AdminHandler
+	JWKS              *echoMiddleware.JWKS
+	RateLimitStore    infrastructure.RateLimitStore
+	IdempotencyStore  infrastructure.IdempotencyStore
+	Echo              *echo.Echo
+	AdminEcho         *echo.Echo
+
+	modules []Module
+	order   []Module
+}
+
+// NewApp returns new App without modules. Modules are added by Register (see DefaultModules)
+func NewApp(config *cfg.AppConfig) *App {
+	var target App
+	target.Config = config
+	target.Health = service.NewHealthService(config.Health)
+	target.Lifecycle = infrastructure.NewLifecycle(config.Shutdown.DrainTimeout)
+	return &target
+}
+
+// DefaultModules - modules of the service
+func DefaultModules() []Module {
+	return []Module{
+		databaseModule(),
+		listenerModule(),
+		partitionMaintainerModule(),
+		jobQueueModule(),
+		producerModule(),
+		consumerModule(),
+		httpClientModule(),
+		dependencyMonitorModule(),
+		servicesModule(),
+		httpModule(),
+		adminModule(),
+	}
+}
+
+// Register - adds modules. Module replaces registered one with the same name
+func (a *App) Register(modules ...Module) {
+	for _, m := range modules {
+		replaced := false
+		for ind := range a.modules {
+			if a.modules[ind].Name == m.Name {
+				a.modules[ind] = m
+				replaced = true
+				break
 			}
-			time.Sleep(attemptInterval)
 		}
-	}
-	if dbHandler == nil {
-		logger.Fatal().Err(err).Msgf("can't create postgres handler after %d attempts", attemptsCount)
+		if !replaced {
+			a.modules = append(a.modules, m)
+		}
 	}
 }
 
-// PrepareApp - init app
-func PrepareApp(ctx context.Context) {
-	var err error
-	appConfig = cfg.NewConfig()
-	// 1. Configuration
-	err = appConfig.Init()
+// Build - initializes modules in dependency order
+func (a *App) Build(ctx context.Context) error {
+	if a.order != nil {
+		return ErrAppBuilt
+	}
+	order, err := resolveModules(a.modules)
 	if err != nil {
-		panic(err)
+		return err
 	}
+	for _, m := range order {
+		if m.Init == nil {
+			continue
+		}
+		if err = m.Init(ctx, a); err != nil {
+			return fmt.Errorf("module %s: %w", m.Name, err)
+		}
+		a.Log(ctx).Debug().Str("module", m.Name).Msg("module initialized")
+	}
+	a.order = order
+	return nil
+}
 
-	// 2. Logger
-	infrastructure.InitGlobalLogger(appConfig.Logger.LogLevel, appConfig.Passport.ServiceName, appConfig.Passport.ServiceInstance)
-	logger = infrastructure.GetBaseLogger(ctx)
-
-	// 3. Init db
-	initDatabase(ctx)
-	pingDBRepository = repository.NewPingRepository(dbHandler)
-	//
-
-	// 4. Init producer
-	initProducer(ctx)
-
-	// 5. Init consumer
-	initConsumer(ctx)
-
-	// 6. Init kafka repository
-	pingKafkaRepository, err = repository.NewPingKafkaRepository(producer)
-	if err != nil {
-		logger.Error().Err(err).Msg("can't create PingKafkaRepository")
+// Start - starts modules in dependency order. Startup probe succeeds after it
+func (a *App) Start(ctx context.Context) error {
+	if a.order == nil {
+		return ErrAppNotBuilt
 	}
-	//
-	// 7. Http client
-	httpClient.InitBaseHTTPClient(appConfig.HTTPClient)
-	pingClient = rest.NewPingClient("http://localhost:8080/api/v1/ping")
+	for _, m := range a.order {
+		if m.Start == nil {
+			continue
+		}
+		if err := m.Start(ctx, a); err != nil {
+			return fmt.Errorf("module %s: %w", m.Name, err)
+		}
+	}
+	a.Health.MarkStarted()
+	return nil
+}
 
-	// 8. Services
-	pingService = service.NewPingService(pingDBRepository, pingKafkaRepository, dbHandler)
-	//
+// Stop - stops processing for incoming requests and free resources. Resources are stopped by lifecycle
+// in order: inputs, background jobs, outputs, storages
+func (a *App) Stop(ctx context.Context) error {
+	// Readiness goes false, so the balancer stops sending new requests before the server starts draining
+	a.Health.MarkShuttingDown()
+	select {
+	case <-time.After(a.Health.ShutdownDelay()):
+	case <-ctx.Done():
+	}
 
-	// 9. Handler
-	pingHandler = handler.NewPingHandler(pingService, pingClient)
+	// Inputs (http, consumer, job workers) are stopped first and drain in-flight work,
+	// then background jobs, producer and database
+	return a.Lifecycle.Shutdown(ctx)
 }
 
-// StartApp - start app
-func StartApp(ctx context.Context) {
-	// 1. Start echo server
-	// Prepare
-	prepareEcho()
-	// Routes registration.
-	prepareRoutes()
-	// run listening
-	go func() {
-		if err := e.Start(appConfig.Server.Address); err != nil && errors.Is(err, http.ErrServerClosed) {
-			logger.Fatal().Msgf("shutting down the server:%v", err)
-			ctx.Done()
-		}
-	}()
-	//
+// addResource - adds resource for gracefully shutdown in the phase. If resource implements service.HealthChecker,
+// it contributes into readiness probe
+func (a *App) addResource(name string, phase infrastructure.ShutdownPhase, r Resource) {
+	a.Lifecycle.Add(phase, name, r)
+	if checker, ok := r.(service.HealthChecker); ok {
+		a.Health.Register(name, checker, 0)
+	}
+}
 
-	// 2. Run consumer.
-	// Add handler for incoming messages processing
-	prepareConsumerHandlers(ctx)
+// resolveModules - returns modules sorted by dependencies. Registration order is kept for independent modules
+func resolveModules(modules []Module) ([]Module, error) {
+	byName := make(map[string]Module, len(modules))
+	for _, m := range modules {
+		byName[m.Name] = m
+	}
 
-	go func() {
-		err := consumer.Start(ctx)
-		if err != nil {
-			logger.Error().Msg("can't start consumer service")
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(modules))
+	res := make([]Module, 0, len(modules))
+	var visit func(m Module) error
+	visit = func(m Module) error {
+		switch state[m.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrModuleCycle, m.Name)
+		}
+		state[m.Name] = visiting
+		for _, name := range m.DependsOn {
+			dep, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("%w: %s (required by %s)", ErrModuleNotFound, name, m.Name)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
 		}
-	}()
-	//
+		state[m.Name] = visited
+		res = append(res, m)
+		return nil
+	}
+	for _, m := range modules {
+		if err := visit(m); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
 
-// ShutdownApp - stops processing for incoming requests and free resources
-func ShutdownApp(ctx context.Context) {
-	// Shutdown echo server
-	if err := e.Shutdown(ctx); err != nil {
-		logger.Fatal().Err(err).Msg("can't stop echo")
+// loadConfig - reads configuration and initializes logger
+func loadConfig() *cfg.AppConfig {
+	config := cfg.NewConfig()
+	// 1. Configuration
+	err := config.Init()
+	if err != nil {
+		panic(err)
 	}
 
-	for _, r := range resources {
-		if err := r.Close(ctx); err != nil {
-			logger.Fatal().Err(err).Msg("can't free resource")
-		}
-	}
+	// 2. Logger
+	infrastructure.InitGlobalLogger(config.Logger.LogLevel, config.Passport.ServiceName, config.Passport.ServiceInstance)
+	return config
 }
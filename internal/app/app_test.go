@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cfg "go-service-template/internal/app/config"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+)
+
+func TestApp_Build(t *testing.T) {
+	var order []string
+	module := func(name string, deps ...string) Module {
+		return Module{
+			Name:      name,
+			DependsOn: deps,
+			Init: func(ctx context.Context, app *App) error {
+				order = append(order, name)
+				return nil
+			},
+		}
+	}
+	errInit := errors.New("init error")
+
+	tests := []struct {
+		name      string
+		modules   []Module
+		wantOrder []string
+		wantErr   error
+	}{
+		{
+			name:      "App. Build. Case #1. Dependencies are initialized first",
+			modules:   []Module{module("http", "services"), module("services", "postgres", "kafka-producer"), module("kafka-producer", "postgres"), module("postgres")},
+			wantOrder: []string{"postgres", "kafka-producer", "services", "http"},
+		},
+		{
+			name:      "App. Build. Case #2. Registration order of independent modules is kept",
+			modules:   []Module{module("postgres"), module("http-client"), module("job-queue", "postgres")},
+			wantOrder: []string{"postgres", "http-client", "job-queue"},
+		},
+		{
+			name:    "App. Build. Case #3. Unknown dependency",
+			modules: []Module{module("services", "postgres")},
+			wantErr: ErrModuleNotFound,
+		},
+		{
+			name:    "App. Build. Case #4. Cyclic dependencies",
+			modules: []Module{module("a", "b"), module("b", "c"), module("c", "a")},
+			wantErr: ErrModuleCycle,
+		},
+		{
+			name: "App. Build. Case #5. Init error",
+			modules: []Module{module("postgres"), {
+				Name: "services",
+				Init: func(ctx context.Context, app *App) error { return errInit },
+			}},
+			wantOrder: []string{"postgres"},
+			wantErr:   errInit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order = nil
+			app := NewApp(cfg.NewConfig())
+			app.Register(tt.modules...)
+			err := app.Build(context.Background())
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantOrder, order)
+		})
+	}
+}
+
+func TestApp_Register(t *testing.T) {
+	app := NewApp(cfg.NewConfig())
+	app.Register(DefaultModules()...)
+	replaced := false
+	app.Register(Module{
+		Name: "postgres",
+		Init: func(ctx context.Context, app *App) error {
+			replaced = true
+			return errors.New("stop build")
+		},
+	})
+	assert.Len(t, app.modules, len(DefaultModules()))
+	assert.Error(t, app.Build(context.Background()))
+	assert.True(t, replaced)
+}
+
+func TestApp_StartStop(t *testing.T) {
+	config := cfg.NewConfig()
+	started := false
+	closed := false
+	app := NewApp(config)
+	app.Register(Module{
+		Name: "worker",
+		Init: func(ctx context.Context, app *App) error {
+			app.addResource("worker", infrastructure.PhaseInput, closerResource(func() { closed = true }))
+			return nil
+		},
+		Start: func(ctx context.Context, app *App) error {
+			started = true
+			return nil
+		},
+	})
+	ctx := context.Background()
+	assert.ErrorIs(t, app.Start(ctx), ErrAppNotBuilt)
+	require.NoError(t, app.Build(ctx))
+	assert.ErrorIs(t, app.Build(ctx), ErrAppBuilt)
+	require.NoError(t, app.Start(ctx))
+	assert.True(t, started)
+	assert.Equal(t, dto.HealthStatusUp, app.Health.Startup(ctx).Status)
+
+	require.NoError(t, app.Stop(ctx))
+	assert.True(t, closed)
+	assert.Equal(t, dto.HealthStatusDown, app.Health.Ready(ctx).Status)
+}
+
+type closerResource func()
+
+func (r closerResource) Init(_ context.Context) error { return nil }
+
+func (r closerResource) Close(_ context.Context) error {
+	r()
+	return nil
+}
@@ -25,7 +25,32 @@ type AppConfig struct {
 	Server struct {
 		// Address - address for service listening
 		Address string `env:"RUN_ADDRESS" yaml:"address" validate:"required"`
+
+		// MaxInFlight - max count of API requests processed at once. Requests over limit are rejected with 503. 0 - unlimited
+		MaxInFlight int `env:"SERVER_MAX_IN_FLIGHT" yaml:"maxInFlight"`
+
+		// TrustedProxies - CIDRs of proxies allowed to set client IP in X-Forwarded-For. If empty, client IP is the peer address
+		TrustedProxies []string `env:"SERVER_TRUSTED_PROXIES" envSeparator:"," yaml:"trustedProxies" validate:"dive,cidr"`
 	} `yaml:"server"`
+	// Errors - struct for error responses params
+	Errors struct {
+		// HideTechInfo - don't return internal error text (TechInfo) in responses. It must be true in production
+		HideTechInfo bool `env:"ERRORS_HIDE_TECH_INFO" yaml:"hideTechInfo"`
+
+		// TypeBaseURL - base of problem type URI. Error code is appended to it
+		TypeBaseURL string `yaml:"typeBaseURL"` //nolint:tagliatelle
+	} `yaml:"errors"`
+	// Admin - struct for admin API params. Admin API is served on a separate address
+	Admin struct {
+		// Enabled - start admin API
+		Enabled bool `env:"ADMIN_ENABLED" yaml:"enabled"`
+
+		// Address - address for admin API listening
+		Address string `env:"ADMIN_ADDRESS" yaml:"address" validate:"required_if=Enabled true"`
+
+		// Token - bearer token for admin API requests
+		Token string `env:"ADMIN_TOKEN" yaml:"token" validate:"required_if=Enabled true" secret:"true"`
+	} `yaml:"admin"`
 	// Database  - struct for db connection params
 	Database struct {
 		// Address - database host name
@@ -41,8 +66,25 @@ type AppConfig struct {
 		Username string `env:"DB_USERNAME" yaml:"username" validate:"required"`
 
 		// Password - password for username
-		Password string `env:"DB_PASSWORD" yaml:"password" validate:"required"`
+		Password string `env:"DB_PASSWORD" yaml:"password" validate:"required" secret:"true"`
+
+		// Replicas - list of read replicas DSNs. Optional
+		Replicas []string `env:"DB_REPLICAS" envSeparator:"," yaml:"replicas" secret:"true"`
 	} `yaml:"database"`
+	// Migration - struct for db migration params
+	Migration struct {
+		// MigrateOnStart - apply migrations on service start
+		MigrateOnStart bool `env:"DB_MIGRATE_ON_START" yaml:"migrateOnStart"`
+
+		// Username - name of the schema owner. Migrations are applied on behalf of this user. If it is empty, Database.Username is used
+		Username string `env:"DB_MIGRATION_USERNAME" yaml:"username"`
+
+		// Password - password for Username
+		Password string `env:"DB_MIGRATION_PASSWORD" yaml:"password" secret:"true"`
+
+		// LockTimeout - max time for waiting for migration lock. Lock prevents concurrent migration from several instances
+		LockTimeout time.Duration `yaml:"lockTimeout"`
+	} `yaml:"migration"`
 	PgPool struct {
 		// MaxConnLifetime is the duration since creation after which a connection will be automatically closed
 		MaxConnLifetime time.Duration `yaml:"maxConnLifetime"`
@@ -67,14 +109,249 @@ type AppConfig struct {
 
 		// LogLevel - set log level for pgx. Available values are trace, debug, info, warn, error, none
 		LogLevel string `env:"PGX_LOG_LEVEL" yaml:"pgxLogLevel"`
+
+		// ReplicaHealthCheckPeriod - the duration between checks of the read replicas availability
+		ReplicaHealthCheckPeriod time.Duration `yaml:"replicaHealthCheckPeriod"`
+
+		// ReplicaHealthCheckTimeout - timeout for one replica availability check
+		ReplicaHealthCheckTimeout time.Duration `yaml:"replicaHealthCheckTimeout"`
+
+		// IDBlockSize - count of ids reserved from sequence per one round trip by GetNextID
+		IDBlockSize int32 `yaml:"idBlockSize"`
+
+		// SlowQueryThreshold - statements executed longer than threshold are logged with warn level. 0 disables slow query logging
+		SlowQueryThreshold time.Duration `env:"PG_SLOW_QUERY_THRESHOLD" yaml:"slowQueryThreshold"`
+
+		// LogArgValues - log statement args values in slow and failed statements. By default only types and lengths are logged
+		LogArgValues bool `env:"PG_LOG_ARG_VALUES" yaml:"logArgValues"`
+
+		// TraceStatements - create OpenTelemetry span for each statement
+		TraceStatements bool `env:"PG_TRACE_STATEMENTS" yaml:"traceStatements"`
+
+		// ReadTimeout - default timeout for Query and QueryRow. Can be overridden by WithStatementTimeout. 0 - no timeout
+		ReadTimeout time.Duration `env:"PG_READ_TIMEOUT" yaml:"readTimeout"`
+
+		// WriteTimeout - default timeout for Execute, ExecuteBatch and GetNextID. Can be overridden by WithStatementTimeout. 0 - no timeout
+		WriteTimeout time.Duration `env:"PG_WRITE_TIMEOUT" yaml:"writeTimeout"`
+
+		// StatementTimeout - statement_timeout session setting. It's applied by the server, so it limits statements executed without context deadline too. 0 - server default
+		StatementTimeout time.Duration `env:"PG_STATEMENT_TIMEOUT" yaml:"statementTimeout"`
+
+		// LockTimeout - lock_timeout session setting. Max time of waiting for a lock by statement. 0 - server default
+		LockTimeout time.Duration `env:"PG_LOCK_TIMEOUT" yaml:"lockTimeout"`
+
+		// CursorFetchSize - default count of rows fetched from cursor per one round trip
+		CursorFetchSize int32 `yaml:"cursorFetchSize"`
+
+		// TenantSchemas - multi-tenant mode. search_path of each acquired connection is set to the schema of the tenant from the context
+		TenantSchemas bool `env:"PG_TENANT_SCHEMAS" yaml:"tenantSchemas"`
+
+		// TenantSchemaPrefix - tenant schema name is TenantSchemaPrefix + tenant
+		TenantSchemaPrefix string `yaml:"tenantSchemaPrefix"`
+
+		// AuditContext - pass caller identity and requestID from the context into app.user and app.request_id
+		// transaction settings (SET LOCAL). They are used by audit_log triggers
+		AuditContext bool `env:"PG_AUDIT_CONTEXT" yaml:"auditContext"`
+
+		// ReadinessCheckPeriod - the duration between health checks of the primary pool. Pool isn't ready if the check fails
+		ReadinessCheckPeriod time.Duration `yaml:"readinessCheckPeriod"`
+
+		// ReconnectInterval - initial delay between health checks after the database is lost. Delay is doubled after each failed check
+		ReconnectInterval time.Duration `yaml:"reconnectInterval"`
+
+		// MaxReconnectInterval - max delay between health checks after the database is lost
+		MaxReconnectInterval time.Duration `yaml:"maxReconnectInterval"`
 	} `yaml:"pgPool"`
+	// PgListener - struct for postgres notifications listener params
+	PgListener struct {
+		// Channels - list of channels for listening
+		Channels []string `env:"PG_LISTENER_CHANNELS" envSeparator:"," yaml:"channels"`
+
+		// ReconnectInterval - initial delay between reconnection attempts. Delay is doubled after each failed attempt
+		ReconnectInterval time.Duration `yaml:"reconnectInterval"`
+
+		// MaxReconnectInterval - max delay between reconnection attempts
+		MaxReconnectInterval time.Duration `yaml:"maxReconnectInterval"`
+	} `yaml:"pgListener"`
+	// PartitionMaintenance - struct for partition maintenance params of monthly partitioned tables
+	PartitionMaintenance struct {
+		// Enabled - run partition maintenance job
+		Enabled bool `env:"PG_PARTITION_MAINTENANCE_ENABLED" yaml:"enabled"`
+
+		// Tables - list of partitioned tables
+		Tables []string `yaml:"tables"`
+
+		// CheckInterval - the duration between maintenance runs
+		CheckInterval time.Duration `yaml:"checkInterval"`
+
+		// Premake - count of monthly partitions created ahead, including the current month
+		Premake int `yaml:"premake"`
+
+		// Retention - partitions with data older than retention are dropped. 0 - partitions are kept forever
+		Retention time.Duration `env:"PG_PARTITION_RETENTION" yaml:"retention"`
+
+		// ArchiveSchema - if it is set, expired partitions are detached and moved into this schema instead of being dropped
+		ArchiveSchema string `yaml:"archiveSchema"`
+	} `yaml:"partitionMaintenance"`
+	// JobQueue - struct for background jobs workers params
+	JobQueue struct {
+		// Enabled - start job workers
+		Enabled bool `env:"JOB_QUEUE_ENABLED" yaml:"enabled"`
+
+		// Queues - list of queues processed by workers. Default is DefaultJobQueue
+		Queues []string `yaml:"queues"`
+
+		// Workers - count of concurrent workers
+		Workers int `yaml:"workers"`
+
+		// PollInterval - delay between queue polls when there are no jobs ready to run
+		PollInterval time.Duration `yaml:"pollInterval"`
+
+		// RetryInterval - delay before the first retry of failed job. Delay is doubled after each failed attempt
+		RetryInterval time.Duration `yaml:"retryInterval"`
+
+		// MaxRetryInterval - max delay between retries
+		MaxRetryInterval time.Duration `yaml:"maxRetryInterval"`
+
+		// JobTimeout - max duration of one attempt. Jobs running longer (e.g. taken by crashed instance) are returned into the queue
+		JobTimeout time.Duration `yaml:"jobTimeout"`
+	} `yaml:"jobQueue"`
 	// Kafka struct contains params for apache kafka connection
 	Kafka struct {
 		// BrokerList - list of brokers ( {"host:port"}[,"host:port"])
 		BrokerList []string `env:"BROKERS" envSeparator:"," yaml:"brokerList" validate:"required,min=1,dive,required"`
 		// LogSarama enable logging inside sarama
 		LogSarama bool `env:"LOG_SARAMA" yaml:"logSarama"`
+		// ReconnectInterval - initial delay between attempts to connect to the brokers. Delay is doubled after each failed attempt
+		ReconnectInterval time.Duration `yaml:"reconnectInterval"`
+		// MaxReconnectInterval - max delay between attempts to connect to the brokers
+		MaxReconnectInterval time.Duration `yaml:"maxReconnectInterval"`
 	} `yaml:"kafka"`
+	// Tenant - struct for tenant resolving params
+	Tenant struct {
+		// Enabled - resolve tenant of incoming HTTP requests. Tenant is taken from JWTClaim if it is set, otherwise from X-Tenant-Id header
+		Enabled bool `env:"TENANT_ENABLED" yaml:"enabled"`
+
+		// JWTClaim - name of the bearer token claim with tenant. Auth must be enabled. If it is set, X-Tenant-Id header may only repeat the claim
+		JWTClaim string `yaml:"jwtClaim"`
+
+		// Required - reject requests without tenant
+		Required bool `yaml:"required"`
+	} `yaml:"tenant"`
+	// Auth - struct for JWT authentication params of API requests
+	Auth struct {
+		// Enabled - authenticate API requests
+		Enabled bool `env:"AUTH_ENABLED" yaml:"enabled"`
+
+		// JWKSFile - path to local JWKS file. It has priority over JWKSURL
+		JWKSFile string `env:"AUTH_JWKS_FILE" yaml:"jwksFile"`
+
+		// JWKSURL - URL of identity provider JWKS
+		JWKSURL string `env:"AUTH_JWKS_URL" yaml:"jwksURL"` //nolint:tagliatelle
+
+		// CacheTTL - keys are reloaded after CacheTTL
+		CacheTTL time.Duration `yaml:"cacheTTL"` //nolint:tagliatelle
+
+		// Issuer - expected iss claim. It isn't checked if empty
+		Issuer string `env:"AUTH_ISSUER" yaml:"issuer"`
+
+		// Audience - expected aud claim. It isn't checked if empty
+		Audience string `env:"AUTH_AUDIENCE" yaml:"audience"`
+
+		// RolesClaim - claim with roles. Nested claims are separated by dot, e.g. realm_access.roles
+		RolesClaim string `yaml:"rolesClaim"`
+
+		// ScopeClaim - claim with scopes (space separated string or array)
+		ScopeClaim string `yaml:"scopeClaim"`
+	} `yaml:"auth"`
+	// RateLimit - struct for rate limiting params of API requests
+	RateLimit struct {
+		// Enabled - limit rate of API requests
+		Enabled bool `env:"RATE_LIMIT_ENABLED" yaml:"enabled"`
+
+		// KeyBy - client identification: ip, apiKey or subject
+		KeyBy string `yaml:"keyBy" validate:"omitempty,oneof=ip apiKey subject"`
+
+		// APIKeyHeader - header with API key
+		APIKeyHeader string `yaml:"apiKeyHeader"`
+
+		// Rate - requests per second of one client
+		Rate float64 `yaml:"rate"`
+
+		// Burst - max count of requests of one client at once
+		Burst int `yaml:"burst"`
+
+		// Store - storage of limits: memory or postgres
+		Store string `env:"RATE_LIMIT_STORE" yaml:"store" validate:"omitempty,oneof=memory postgres"`
+
+		// Routes - limits of routes. Routes have own buckets
+		Routes []struct {
+			// Route - "METHOD path" or "path" for all methods. Path is the route pattern, e.g. /api/v1/users/:id
+			Route string `yaml:"route"`
+
+			// Rate - requests per second of one client
+			Rate float64 `yaml:"rate"`
+
+			// Burst - max count of requests of one client at once
+			Burst int `yaml:"burst"`
+		} `yaml:"routes"`
+
+		// IPRate - requests per second of one IP before authentication. It is applied if clients are limited by subject,
+		// so floods of bad tokens don't reach token verification. 0 disables the limit
+		IPRate float64 `yaml:"ipRate"`
+
+		// IPBurst - max count of requests of one IP at once before authentication
+		IPBurst int `yaml:"ipBurst"`
+	} `yaml:"rateLimit"`
+	// Idempotency - struct for Idempotency-Key params of API requests
+	Idempotency struct {
+		// Enabled - store responses of requests with Idempotency-Key header
+		Enabled bool `env:"IDEMPOTENCY_ENABLED" yaml:"enabled"`
+
+		// TTL - stored response is returned for repeated requests during TTL
+		TTL time.Duration `yaml:"ttl"` //nolint:tagliatelle
+
+		// LockTimeout - the key of the request in progress is released after LockTimeout (e.g. if the instance crashed)
+		LockTimeout time.Duration `yaml:"lockTimeout"`
+
+		// MaxBodySize - max body size in bytes of the request with Idempotency-Key. Body is read into memory
+		// to compute the fingerprint, so requests with larger body are rejected with 413
+		MaxBodySize int64 `yaml:"maxBodySize"`
+	} `yaml:"idempotency"`
+	// Health - struct for liveness, readiness and startup probes params
+	Health struct {
+		// CheckTimeout - default timeout of one check
+		CheckTimeout time.Duration `yaml:"checkTimeout"`
+
+		// CacheTTL - check results are cached for CacheTTL, so frequent probes don't load dependencies
+		CacheTTL time.Duration `yaml:"cacheTTL"` //nolint:tagliatelle
+
+		// ShutdownDelay - delay between readiness going false and the start of shutdown.
+		// It gives the balancer time to stop sending new requests
+		ShutdownDelay time.Duration `yaml:"shutdownDelay"`
+	} `yaml:"health"`
+	// Shutdown - struct for graceful shutdown params
+	Shutdown struct {
+		// Timeout - max duration of shutdown
+		Timeout time.Duration `yaml:"timeout"`
+
+		// DrainTimeout - budget for stopping inputs (http server, kafka consumer, job workers) and finishing in-flight work
+		DrainTimeout time.Duration `yaml:"drainTimeout"`
+	} `yaml:"shutdown"`
+	// DependencyMonitor - struct for dependency monitor params
+	DependencyMonitor struct {
+		// Enabled - check dependencies periodically in background
+		Enabled bool `env:"DEPENDENCY_MONITOR_ENABLED" yaml:"enabled"`
+
+		// CheckInterval - the duration between checks
+		CheckInterval time.Duration `yaml:"checkInterval"`
+
+		// CheckTimeout - timeout of one check
+		CheckTimeout time.Duration `yaml:"checkTimeout"`
+
+		// HistorySize - count of recent results kept for each dependency
+		HistorySize int `yaml:"historySize"`
+	} `yaml:"dependencyMonitor"`
 	HTTPClient struct {
 		// RequestTimeout - request timeout for http client
 		RequestTimeout time.Duration `yaml:"requestTimeout"`
@@ -125,9 +402,47 @@ func (config *AppConfig) Init() error {
 	PrintEnv()
 	// 1. Set default values
 	config.HTTPClient.RequestTimeout = time.Second * 30
+	config.Errors.TypeBaseURL = "/problems/"
+	config.Auth.CacheTTL = time.Minute * 10
+	config.Auth.RolesClaim = "roles"
+	config.Auth.ScopeClaim = "scope"
+	config.RateLimit.KeyBy = "ip"
+	config.RateLimit.APIKeyHeader = "X-Api-Key"
+	config.RateLimit.Rate = 10
+	config.RateLimit.Burst = 20
+	config.RateLimit.Store = "memory"
+	config.RateLimit.IPRate = 50
+	config.RateLimit.IPBurst = 100
+	config.Idempotency.TTL = time.Hour * 24
+	config.Idempotency.LockTimeout = time.Minute
+	config.Idempotency.MaxBodySize = 1 << 20
 	config.PgPool.MaxConnIdleTime = time.Second * 120
 	config.PgPool.MaxConns = 5
 	config.PgPool.MinConns = 2
+	config.PgPool.ReplicaHealthCheckPeriod = time.Second * 10
+	config.PgPool.ReplicaHealthCheckTimeout = time.Second * 3
+	config.Migration.LockTimeout = time.Minute * 5
+	config.PgPool.IDBlockSize = 20
+	config.PgPool.SlowQueryThreshold = time.Millisecond * 500
+	config.PgPool.ReadTimeout = time.Second * 30
+	config.PgPool.WriteTimeout = time.Second * 30
+	config.PgPool.CursorFetchSize = 1000
+	config.PgPool.AuditContext = true
+	config.PgPool.ReadinessCheckPeriod = time.Second * 5
+	config.PgPool.ReconnectInterval = time.Second
+	config.PgPool.MaxReconnectInterval = time.Minute
+	config.Kafka.ReconnectInterval = time.Second
+	config.Kafka.MaxReconnectInterval = time.Minute
+	config.PartitionMaintenance.CheckInterval = time.Hour
+	config.PartitionMaintenance.Premake = 3
+	config.Health.CheckTimeout = time.Second * 2
+	config.Health.CacheTTL = time.Second
+	config.Health.ShutdownDelay = time.Second * 5
+	config.Shutdown.Timeout = time.Second * 60
+	config.Shutdown.DrainTimeout = time.Second * 30
+	config.DependencyMonitor.CheckInterval = time.Second * 30
+	config.DependencyMonitor.CheckTimeout = time.Second * 5
+	config.DependencyMonitor.HistorySize = 100
 	//
 
 	// 2. Application.yaml read
@@ -150,7 +465,7 @@ func (config *AppConfig) Init() error {
 		return err
 	}
 
-	printAppConfig(*config)
+	printAppConfig(config.Masked())
 
 	// 4. Validation
 
@@ -0,0 +1,43 @@
+package config
+
+import "reflect"
+
+// maskedValue - replacement for secret values
+const maskedValue = "******"
+
+// Masked - returns copy of config with secrets (fields with secret:"true" tag) replaced by maskedValue
+func (config *AppConfig) Masked() AppConfig {
+	res := *config
+	mask(reflect.ValueOf(&res).Elem())
+	return res
+}
+
+func mask(v reflect.Value) {
+	t := v.Type()
+	for ind := 0; ind < v.NumField(); ind++ {
+		field := v.Field(ind)
+		if !field.CanSet() {
+			continue
+		}
+		if field.Kind() == reflect.Struct {
+			mask(field)
+			continue
+		}
+		if t.Field(ind).Tag.Get("secret") != "true" {
+			continue
+		}
+		switch field.Kind() { //nolint:exhaustive
+		case reflect.String:
+			if field.Len() > 0 {
+				field.SetString(maskedValue)
+			}
+		case reflect.Slice:
+			// slice is shared with original config, so new one is created
+			masked := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+			for i := 0; i < field.Len(); i++ {
+				masked.Index(i).SetString(maskedValue)
+			}
+			field.Set(masked)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/infrastructure/postgres"
+)
+
+func databaseModule() Module {
+	return Module{Name: "postgres", Init: initDatabase}
+}
+
+// initDatabase  initialize new dbHandler. If database is not available, the service starts in degraded state:
+// dbHandler isn't ready and reconnects in background (see PostgresqlHandlerTX.Ready).
+// Degraded start isn't possible if migrations are applied on start (see migrateOnStart)
+func initDatabase(ctx context.Context, a *App) error {
+	var err error
+	dataSource := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s", a.Config.Database.Username, a.Config.Database.Password, a.Config.Database.Address, a.Config.Database.Port, a.Config.Database.DB) //nolint:nosprintfhostport
+	a.DB, err = postgres.NewPostgresqlHandlerTX(ctx, dataSource, a.Config.PgPool, a.Config.Database.Replicas...)
+	if err != nil {
+		return fmt.Errorf("can't create postgres handler: %w", err)
+	}
+	a.addResource("postgres", infrastructure.PhaseStorage, a.DB)
+	if a.DB.Ready() {
+		a.LogInfo(ctx, "db successfully initialized")
+	}
+	if a.Config.Migration.MigrateOnStart {
+		return migrateOnStart(ctx, a)
+	}
+	return nil
+}
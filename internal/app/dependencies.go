@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/infrastructure/kafka"
+	"go-service-template/internal/app/service"
+)
+
+func dependencyMonitorModule() Module {
+	return Module{
+		Name:      "dependency-monitor",
+		DependsOn: []string{"postgres", "http-client"},
+		Init:      initDependencyMonitor,
+		Start:     startDependencyMonitor,
+	}
+}
+
+// initDependencyMonitor - registers postgres, each kafka broker and downstream services for detailed health report
+func initDependencyMonitor(ctx context.Context, a *App) error {
+	var err error
+	a.DependencyMonitor, err = service.NewDependencyMonitor(ctx, a.Config.DependencyMonitor)
+	if err != nil {
+		return fmt.Errorf("can't create dependency monitor: %w", err)
+	}
+	a.addResource("dependency-monitor", infrastructure.PhaseBackground, a.DependencyMonitor)
+
+	a.DependencyMonitor.Register("postgres", service.DependencyPostgres, service.HealthCheckFunc(a.DB.Ping))
+	for _, broker := range kafka.NewBrokerCheckers(a.Config.Kafka) {
+		a.DependencyMonitor.Register(broker.Address(), service.DependencyKafkaBroker, broker)
+	}
+	a.DependencyMonitor.Register("ping-service", service.DependencyHTTP, a.PingClient)
+	return nil
+}
+
+func startDependencyMonitor(ctx context.Context, a *App) error {
+	if !a.Config.DependencyMonitor.Enabled {
+		a.LogInfo(ctx, "dependency monitor is disabled")
+		return nil
+	}
+
+	go func() {
+		err := a.DependencyMonitor.Start(ctx)
+		if err != nil {
+			a.LogError(ctx, "can't start dependency monitor", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,28 @@
+package dto
+
+// LogLevels - dto for global and per-component log levels
+type LogLevels struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+	Supported  []string          `json:"supported,omitempty"`
+}
+
+// LogLevelRequest - dto for log level change. Component is empty for global log level
+type LogLevelRequest struct {
+	Component string `json:"-" param:"component"`
+	Level     string `json:"level" validate:"required"`
+}
+
+// ConsumerState - dto for kafka consumer state
+type ConsumerState struct {
+	Ready  bool     `json:"ready"`
+	Paused bool     `json:"paused"`
+	Topics []string `json:"topics"`
+}
+
+// Route - dto for registered http route
+type Route struct {
+	Server string `json:"server"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditRecord - dto for one change of the entity
+type AuditRecord struct {
+	ID         int64           `json:"id"`
+	ChangeTime time.Time       `json:"changeTime"`
+	Table      string          `json:"table"`
+	EntityID   string          `json:"entityID"` //nolint:tagliatelle
+	Operation  string          `json:"operation"`
+	OldData    json.RawMessage `json:"oldData,omitempty"`
+	NewData    json.RawMessage `json:"newData,omitempty"`
+	User       string          `json:"user,omitempty"`
+	RequestID  string          `json:"requestID,omitempty"` //nolint:tagliatelle
+}
@@ -2,27 +2,66 @@ package dto
 
 import (
 	"errors"
+	"strings"
 )
 
 type (
-	// ErrorDTO represents a common  error dto
-	// from this object we can extract certain information about the error
-	ErrorDTO struct { //nolint:errname
-		Cause    error  `json:"-"`
-		Message  string `json:"message,omitempty"`
+	// ProblemDTO - error response in RFC 7807 format (application/problem+json)
+	ProblemDTO struct {
+		// Type - URI reference of the problem type. It ends with Code
+		Type string `json:"type"`
+
+		// Title - short summary of the problem type. It doesn't depend on occurrence
+		Title string `json:"title"`
+
+		// Status - http status code
+		Status int `json:"status"`
+
+		// Detail - explanation specific to this occurrence of the problem
+		Detail string `json:"detail,omitempty"`
+
+		// Instance - requestID of the request
+		Instance string `json:"instance,omitempty"`
+
+		// Code - machine-readable error code, see ErrorCodes
+		Code ErrorCode `json:"code"`
+
+		// Errors - invalid fields of the request
+		Errors []FieldError `json:"errors,omitempty"`
+
+		// TechInfo - internal error text. It is hidden in production
 		TechInfo string `json:"techInfo,omitempty"`
 	}
+
+	// FieldError - validation error of the request field
+	FieldError struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+
+	// ValidationError - validation error with the list of invalid fields. It matches ErrValidation
+	ValidationError struct {
+		Fields []FieldError
+	}
 )
 
+// NewValidationError - returns ValidationError for the fields
+func NewValidationError(fields ...FieldError) *ValidationError {
+	return &ValidationError{Fields: fields}
+}
+
 // Error implements error interface
-func (e *ErrorDTO) Error() string {
-	return e.Message
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return ErrValidation.Error() + ": " + strings.Join(msgs, "; ")
 }
 
-// Unwrap returns cause for this error
-// it implements interface for errors.Unwrap() function
-func (e *ErrorDTO) Unwrap() error {
-	return e.Cause
+// Is returns true for ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation //nolint:errorlint
 }
 
 var (
@@ -31,4 +70,7 @@ var (
 
 	// ErrEntityNotFound - сущность не найдена
 	ErrEntityNotFound = errors.New("entity not found")
+
+	// ErrUnavailable - компонент сервиса временно недоступен
+	ErrUnavailable = errors.New("component is unavailable")
 )
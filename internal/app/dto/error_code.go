@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"net/http"
+	"sort"
+)
+
+// ErrorCode - stable machine-readable error code. Codes must not be changed, clients rely on them
+type ErrorCode string
+
+const (
+	// CodeValidation - request is invalid
+	CodeValidation ErrorCode = "validation-error"
+
+	// CodeEntityNotFound - requested entity doesn't exist
+	CodeEntityNotFound ErrorCode = "entity-not-found"
+
+	// CodeEntityExists - entity with the same key already exists
+	CodeEntityExists ErrorCode = "entity-already-exists"
+
+	// CodeVersionConflict - entity was modified concurrently
+	CodeVersionConflict ErrorCode = "version-conflict"
+
+	// CodeConstraintViolation - request violates data constraint
+	CodeConstraintViolation ErrorCode = "constraint-violation"
+
+	// CodeQueryTimeout - database query timeout
+	CodeQueryTimeout ErrorCode = "query-timeout"
+
+	// CodeTemporaryError - temporary database error, request may be retried
+	CodeTemporaryError ErrorCode = "temporary-error"
+
+	// CodeUnavailable - component of the service is unavailable
+	CodeUnavailable ErrorCode = "component-unavailable"
+
+	// CodeBadRequest - request can't be processed
+	CodeBadRequest ErrorCode = "bad-request"
+
+	// CodeUnauthorized - request isn't authenticated
+	CodeUnauthorized ErrorCode = "unauthorized"
+
+	// CodeForbidden - access is denied
+	CodeForbidden ErrorCode = "forbidden"
+
+	// CodeRouteNotFound - there is no route for the request
+	CodeRouteNotFound ErrorCode = "route-not-found"
+
+	// CodeMethodNotAllowed - route doesn't support the method
+	CodeMethodNotAllowed ErrorCode = "method-not-allowed"
+
+	// CodeIdempotencyKeyInUse - request with the same Idempotency-Key is in progress
+	CodeIdempotencyKeyInUse ErrorCode = "idempotency-key-in-use"
+
+	// CodeIdempotencyKeyReused - Idempotency-Key was used by other request
+	CodeIdempotencyKeyReused ErrorCode = "idempotency-key-reused"
+
+	// CodeTooManyRequests - client exceeded rate limit
+	CodeTooManyRequests ErrorCode = "too-many-requests"
+
+	// CodeInternal - unexpected error
+	CodeInternal ErrorCode = "internal-error"
+)
+
+// ErrorCodeInfo - description of the error code
+type ErrorCodeInfo struct {
+	Code   ErrorCode `json:"code"`
+	Status int       `json:"status"`
+	Title  string    `json:"title"`
+}
+
+var errorCodes = map[ErrorCode]ErrorCodeInfo{
+	CodeValidation:           {Status: http.StatusUnprocessableEntity, Title: "Validation error"},
+	CodeEntityNotFound:       {Status: http.StatusNotFound, Title: "Entity not found"},
+	CodeEntityExists:         {Status: http.StatusConflict, Title: "Entity already exists"},
+	CodeVersionConflict:      {Status: http.StatusConflict, Title: "Entity was modified concurrently"},
+	CodeConstraintViolation:  {Status: http.StatusUnprocessableEntity, Title: "Constraint violation"},
+	CodeQueryTimeout:         {Status: http.StatusGatewayTimeout, Title: "Database query timeout"},
+	CodeTemporaryError:       {Status: http.StatusServiceUnavailable, Title: "Temporary error"},
+	CodeUnavailable:          {Status: http.StatusServiceUnavailable, Title: "Component is unavailable"},
+	CodeBadRequest:           {Status: http.StatusBadRequest, Title: "Bad request"},
+	CodeUnauthorized:         {Status: http.StatusUnauthorized, Title: "Unauthorized"},
+	CodeForbidden:            {Status: http.StatusForbidden, Title: "Forbidden"},
+	CodeRouteNotFound:        {Status: http.StatusNotFound, Title: "Route not found"},
+	CodeMethodNotAllowed:     {Status: http.StatusMethodNotAllowed, Title: "Method not allowed"},
+	CodeIdempotencyKeyInUse:  {Status: http.StatusConflict, Title: "Request with the idempotency key is in progress"},
+	CodeIdempotencyKeyReused: {Status: http.StatusConflict, Title: "Idempotency key was used by other request"},
+	CodeTooManyRequests:      {Status: http.StatusTooManyRequests, Title: "Too many requests"},
+	CodeInternal:             {Status: http.StatusInternalServerError, Title: "Internal error"},
+}
+
+// httpErrorCodes - codes of errors returned by echo and middlewares with http status only
+var httpErrorCodes = map[int]ErrorCode{
+	http.StatusBadRequest:         CodeBadRequest,
+	http.StatusUnauthorized:       CodeUnauthorized,
+	http.StatusForbidden:          CodeForbidden,
+	http.StatusNotFound:           CodeRouteNotFound,
+	http.StatusMethodNotAllowed:   CodeMethodNotAllowed,
+	http.StatusTooManyRequests:    CodeTooManyRequests,
+	http.StatusServiceUnavailable: CodeUnavailable,
+}
+
+// LookupErrorCode - returns description of the code
+func LookupErrorCode(code ErrorCode) (ErrorCodeInfo, bool) {
+	info, ok := errorCodes[code]
+	info.Code = code
+	return info, ok
+}
+
+// ErrorCodeByStatus - returns code for http status of errors without own code
+func ErrorCodeByStatus(status int) ErrorCode {
+	if code, ok := httpErrorCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// ErrorCodes - returns all registered codes ordered by code
+func ErrorCodes() []ErrorCodeInfo {
+	res := make([]ErrorCodeInfo, 0, len(errorCodes))
+	for code := range errorCodes {
+		info, _ := LookupErrorCode(code)
+		res = append(res, info)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Code < res[j].Code })
+	return res
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+const (
+	// HealthStatusUp - dependency or service is available
+	HealthStatusUp = "UP"
+
+	// HealthStatusDown - dependency or service is unavailable
+	HealthStatusDown = "DOWN"
+
+	// HealthStatusUnknown - dependency wasn't checked yet
+	HealthStatusUnknown = "UNKNOWN"
+)
+
+// Health - dto for probe result
+type Health struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck - dto for result of one dependency check
+type HealthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DependencyReport - dto for detailed report of dependencies state
+type DependencyReport struct {
+	Status       string             `json:"status"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// DependencyStatus - dto for state of one dependency
+type DependencyStatus struct {
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	LatencyMs   float64    `json:"latencyMs"`
+	LastCheck   *time.Time `json:"lastCheck,omitempty"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	LastFailure *time.Time `json:"lastFailure,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// DependencyCheck - dto for result of one dependency check in history
+type DependencyCheck struct {
+	CheckTime time.Time `json:"checkTime"`
+	Status    string    `json:"status"`
+	LatencyMs float64   `json:"latencyMs"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// DependencyHistoryRequest - request for recent check results of the dependency
+type DependencyHistoryRequest struct {
+	Name  string `param:"name" validate:"required"`
+	Limit int    `query:"limit" validate:"gte=0"`
+}
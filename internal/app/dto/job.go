@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job - dto for background job. FailedTime is set only for dead jobs
+type Job struct {
+	ID          int64           `json:"id"`
+	Queue       string          `json:"queue"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	UniqueKey   string          `json:"uniqueKey,omitempty"`
+	Status      string          `json:"status"`
+	Attempt     int32           `json:"attempt"`
+	MaxAttempts int32           `json:"maxAttempts"`
+	RunAt       *time.Time      `json:"runAt,omitempty"`
+	LastError   string          `json:"lastError,omitempty"`
+	CreateTime  time.Time       `json:"createTime"`
+	FailedTime  *time.Time      `json:"failedTime,omitempty"`
+}
+
+// JobFilter - filter for job lists. Empty fields aren't applied
+type JobFilter struct {
+	Queue  string `query:"queue"`
+	Kind   string `query:"kind"`
+	Status string `query:"status" validate:"omitempty,oneof=pending running"`
+	Limit  int    `query:"limit" validate:"gte=0"`
+}
+
+// JobRequest - request for job operations
+type JobRequest struct {
+	ID int64 `param:"id" validate:"gt=0"`
+}
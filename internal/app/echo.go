@@ -1,15 +1,70 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"go-service-template/internal/app/handler"
+	"go-service-template/internal/app/infrastructure"
 	echoMiddleware "go-service-template/internal/app/infrastructure/echo"
+	"go-service-template/internal/app/infrastructure/postgres"
 )
 
-func prepareEcho() {
-	e = echo.New()
+func httpModule() Module {
+	return Module{
+		Name:      "http",
+		DependsOn: []string{"services"},
+		Init:      initEcho,
+		Start:     startEcho,
+	}
+}
+
+// initEcho - prepares echo server and registers routes
+func initEcho(ctx context.Context, a *App) error {
+	// claims of unverified tokens aren't trusted
+	if a.Config.Tenant.Enabled && a.Config.Tenant.JWTClaim != "" && !a.Config.Auth.Enabled {
+		return ErrTenantClaimWithoutAuth
+	}
+	if a.Config.Auth.Enabled {
+		var err error
+		if a.JWKS, err = echoMiddleware.NewJWKS(ctx, a.Config.Auth); err != nil {
+			return fmt.Errorf("can't load JWKS: %w", err)
+		}
+	}
+	if a.Config.RateLimit.Enabled {
+		if a.Config.RateLimit.Store == echoMiddleware.RateLimitStorePostgres {
+			a.RateLimitStore = postgres.NewRateLimitStore(a.DB)
+		} else {
+			a.RateLimitStore = infrastructure.NewMemoryRateLimitStore()
+		}
+	}
+	if a.Config.Idempotency.Enabled {
+		a.IdempotencyStore = postgres.NewIdempotencyStore(a.DB)
+	}
+	prepareEcho(a)
+	prepareRoutes(a)
+	// echo waits for in-flight requests on shutdown
+	a.Lifecycle.Add(infrastructure.PhaseInput, "http", infrastructure.CloserFunc(a.Echo.Shutdown))
+	return nil
+}
+
+func startEcho(ctx context.Context, a *App) error {
+	go func() {
+		if err := a.Echo.Start(a.Config.Server.Address); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.LogFatal(ctx, "shutting down the server", err)
+		}
+	}()
+	return nil
+}
+
+func prepareEcho(a *App) {
+	e := echo.New()
 	e.Pre(echoMiddleware.PrepareRequestID)
 	e.Pre(echoMiddleware.PrepareCorrelationID)
 	e.Pre(echoMiddleware.PrepareLogger)
@@ -17,13 +72,109 @@ func prepareEcho() {
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 
-	e.HTTPErrorHandler = handler.ErrorHandler
+	e.IPExtractor = ipExtractor(a.Config.Server.TrustedProxies)
+	e.Validator = handler.NewRequestValidator()
+	e.HTTPErrorHandler = handler.NewErrorHandler(a.Config.Errors)
+	a.Echo = e
+}
+
+// ipExtractor - returns client IP extractor. Client IP is taken from X-Forwarded-For only if it is set by trusted proxies,
+// so clients can't bypass rate limits by the header
+func ipExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range trustedProxies {
+		// CIDRs are validated with config
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			options = append(options, echo.TrustIPRange(ipNet))
+		}
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
 }
 
-func prepareRoutes() {
-	v1 := e.Group("/api/v1")
-	v1.GET("/ping", pingHandler.PingHandler)
-	v1.GET("/pingwithdelay", pingHandler.PingWithDelayHandler)
-	v1.GET("/pingviaclient", pingHandler.PingViaClient)
+// authMiddleware - returns middlewares authenticating API requests. Probes and admin routes don't need it
+func authMiddleware(a *App) []echo.MiddlewareFunc {
+	if !a.Config.Auth.Enabled {
+		return nil
+	}
+	return []echo.MiddlewareFunc{echoMiddleware.Authenticate(a.JWKS, a.Config.Auth)}
+}
+
+// rateLimitMiddleware - returns middlewares limiting API requests of clients before and after authentication.
+// Clients are limited before authentication, so requests with bad tokens aren't verified over the limit.
+// Subject is known only after authentication, so in this case only IP limit is checked before it
+func rateLimitMiddleware(a *App) (beforeAuth, afterAuth []echo.MiddlewareFunc) {
+	if !a.Config.RateLimit.Enabled {
+		return nil, nil
+	}
+	rl := a.Config.RateLimit
+	cfg := echoMiddleware.RateLimitConfig{
+		Enabled:      rl.Enabled,
+		KeyBy:        rl.KeyBy,
+		APIKeyHeader: rl.APIKeyHeader,
+		Rate:         rl.Rate,
+		Burst:        rl.Burst,
+		Store:        rl.Store,
+		IPRate:       rl.IPRate,
+		IPBurst:      rl.IPBurst,
+	}
+	for _, r := range rl.Routes {
+		cfg.Routes = append(cfg.Routes, echoMiddleware.RouteRateLimit(r))
+	}
+	limiter := echoMiddleware.RateLimiter(a.RateLimitStore, cfg)
+	if cfg.KeyBy != echoMiddleware.RateLimitBySubject {
+		return []echo.MiddlewareFunc{limiter}, nil
+	}
+	return []echo.MiddlewareFunc{echoMiddleware.PreAuthRateLimiter(a.RateLimitStore, cfg)}, []echo.MiddlewareFunc{limiter}
+}
+
+// idempotencyMiddleware - returns middlewares replaying responses of API requests with Idempotency-Key
+func idempotencyMiddleware(a *App) []echo.MiddlewareFunc {
+	if !a.Config.Idempotency.Enabled {
+		return nil
+	}
+	return []echo.MiddlewareFunc{echoMiddleware.Idempotency(a.IdempotencyStore, a.Config.Idempotency)}
+}
+
+// tenantMiddleware - returns middlewares resolving tenant of API requests. Probes and admin routes don't need tenant
+func tenantMiddleware(a *App) []echo.MiddlewareFunc {
+	if !a.Config.Tenant.Enabled {
+		return nil
+	}
+	resolver := echoMiddleware.TenantFromHeader(infrastructure.TenantHeader)
+	// tenant of the token can't be switched by the header
+	if a.Config.Tenant.JWTClaim != "" {
+		resolver = echoMiddleware.TenantFromJWTClaim(a.Config.Tenant.JWTClaim, infrastructure.TenantHeader)
+	}
+	return []echo.MiddlewareFunc{echoMiddleware.PrepareTenant(a.Config.Tenant.Required, resolver)}
+}
+
+func prepareRoutes(a *App) {
+	e := a.Echo
+	health := e.Group("/health")
+	health.GET("/live", a.HealthHandler.Live)
+	health.GET("/ready", a.HealthHandler.Ready)
+	health.GET("/startup", a.HealthHandler.Startup)
+
+	// tenant may be taken from the token claim, so the token is validated first.
+	// Clients are limited before authentication, clients limited by subject are limited by IP before it and by subject after it.
+	// Idempotency keys are scoped by tenant and subject, so they are checked after tenant resolving
+	var v1Middleware []echo.MiddlewareFunc
+	if a.Config.Server.MaxInFlight > 0 {
+		v1Middleware = append(v1Middleware, echoMiddleware.ConcurrencyLimiter(a.Config.Server.MaxInFlight))
+	}
+	beforeAuth, afterAuth := rateLimitMiddleware(a)
+	v1Middleware = append(v1Middleware, beforeAuth...)
+	v1Middleware = append(v1Middleware, authMiddleware(a)...)
+	v1Middleware = append(v1Middleware, afterAuth...)
+	v1Middleware = append(v1Middleware, tenantMiddleware(a)...)
+	v1Middleware = append(v1Middleware, idempotencyMiddleware(a)...)
+	v1 := e.Group("/api/v1", v1Middleware...)
+	v1.GET("/ping", a.PingHandler.PingHandler)
+	v1.GET("/pingwithdelay", a.PingHandler.PingWithDelayHandler)
+	v1.GET("/pingviaclient", a.PingHandler.PingViaClient)
 	e.GET("/swagger-ui/*", echoSwagger.WrapHandler)
+	// admin routes (jobs, dependencies, metrics) are served only by the admin server, see initAdmin
 }
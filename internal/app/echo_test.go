@@ -0,0 +1,56 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		want           string
+	}{
+		{
+			name:         "ipExtractor. Case #1. Headers are ignored without trusted proxies",
+			remoteAddr:   "203.0.113.10:5000",
+			forwardedFor: "198.51.100.1",
+			want:         "203.0.113.10",
+		},
+		{
+			name:           "ipExtractor. Case #2. Client IP is set by trusted proxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.1.2.3:5000",
+			forwardedFor:   "198.51.100.1",
+			want:           "198.51.100.1",
+		},
+		{
+			name:           "ipExtractor. Case #3. Header of untrusted peer is ignored",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "203.0.113.10:5000",
+			forwardedFor:   "198.51.100.1",
+			want:           "203.0.113.10",
+		},
+		{
+			name:           "ipExtractor. Case #4. Spoofed address before trusted proxy is ignored",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.1.2.3:5000",
+			forwardedFor:   "198.51.100.1, 203.0.113.10",
+			want:           "203.0.113.10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			req.Header.Set("X-Real-Ip", tt.forwardedFor)
+			assert.Equal(t, tt.want, ipExtractor(tt.trustedProxies)(req))
+		})
+	}
+}
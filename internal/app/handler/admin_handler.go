@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// AdminService - service interface for runtime administration
+//
+//go:generate mockgen -destination=mocks/mock_admin_service.go -package=mocks . AdminService
+type AdminService interface {
+	LogLevels(ctx context.Context) dto.LogLevels
+	SetLogLevel(ctx context.Context, component, level string) error
+	ResetLogLevel(ctx context.Context, component string)
+	Config(ctx context.Context) ([]byte, error)
+	ConsumerState(ctx context.Context) dto.ConsumerState
+	PauseConsumer(ctx context.Context) error
+	ResumeConsumer(ctx context.Context) error
+	Routes(ctx context.Context) []dto.Route
+	Goroutines(ctx context.Context) ([]byte, error)
+}
+
+// AdminHandler - handler for runtime administration: log levels, config, consumer control and diagnostics
+type AdminHandler struct {
+	infrastructure.SugarLogger
+	adminService AdminService
+}
+
+// NewAdminHandler - return new AdminHandler struct
+func NewAdminHandler(service AdminService) *AdminHandler {
+	var target AdminHandler
+	target.adminService = service
+	return &target
+}
+
+// LogLevels godoc
+// @Summary log levels
+// @Description Global log level and overridden levels of components
+// @Tags admin
+// @Produce json
+// @Success 200  {object} dto.LogLevels
+// @Router /admin/log-level [get]
+func (h *AdminHandler) LogLevels(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.adminService.LogLevels(c.Request().Context()))
+}
+
+// SetLogLevel godoc
+// @Summary change global log level
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.LogLevelRequest true "new log level"
+// @Success 200  {object} dto.LogLevels
+// @Failure 422 {object} dto.ProblemDTO
+// @Router /admin/log-level [put]
+func (h *AdminHandler) SetLogLevel(c echo.Context) error {
+	return h.setLogLevel(c)
+}
+
+// SetComponentLogLevel godoc
+// @Summary change log level of the component
+// @Description Components: http, kafka-consumer, postgres-listener, job-queue
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param component path string true "component name"
+// @Param request body dto.LogLevelRequest true "new log level"
+// @Success 200  {object} dto.LogLevels
+// @Failure 422 {object} dto.ProblemDTO
+// @Router /admin/log-level/{component} [put]
+func (h *AdminHandler) SetComponentLogLevel(c echo.Context) error {
+	return h.setLogLevel(c)
+}
+
+// ResetComponentLogLevel godoc
+// @Summary reset log level of the component to the global one
+// @Tags admin
+// @Produce json
+// @Param component path string true "component name"
+// @Success 200  {object} dto.LogLevels
+// @Router /admin/log-level/{component} [delete]
+func (h *AdminHandler) ResetComponentLogLevel(c echo.Context) error {
+	ctx := c.Request().Context()
+	h.adminService.ResetLogLevel(ctx, c.Param("component"))
+	return c.JSON(http.StatusOK, h.adminService.LogLevels(ctx))
+}
+
+func (h *AdminHandler) setLogLevel(c echo.Context) error {
+	req, err := BindRequest[dto.LogLevelRequest](c)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	if err = h.adminService.SetLogLevel(ctx, req.Component, req.Level); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, h.adminService.LogLevels(ctx))
+}
+
+// Config godoc
+// @Summary effective configuration
+// @Description Secrets are masked
+// @Tags admin
+// @Produce application/yaml
+// @Success 200  {string} string
+// @Router /admin/config [get]
+func (h *AdminHandler) Config(c echo.Context) error {
+	res, err := h.adminService.Config(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, "application/yaml", res)
+}
+
+// ConsumerState godoc
+// @Summary kafka consumer state
+// @Description Readiness, pause flag and topics with registered handlers
+// @Tags admin
+// @Produce json
+// @Success 200  {object} dto.ConsumerState
+// @Router /admin/consumer [get]
+func (h *AdminHandler) ConsumerState(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.adminService.ConsumerState(c.Request().Context()))
+}
+
+// PauseConsumer godoc
+// @Summary pause kafka consumer
+// @Tags admin
+// @Produce json
+// @Success 200  {object} dto.ConsumerState
+// @Failure 503 {object} dto.ProblemDTO
+// @Router /admin/consumer/pause [post]
+func (h *AdminHandler) PauseConsumer(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := h.adminService.PauseConsumer(ctx); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, h.adminService.ConsumerState(ctx))
+}
+
+// ResumeConsumer godoc
+// @Summary resume kafka consumer
+// @Tags admin
+// @Produce json
+// @Success 200  {object} dto.ConsumerState
+// @Failure 503 {object} dto.ProblemDTO
+// @Router /admin/consumer/resume [post]
+func (h *AdminHandler) ResumeConsumer(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := h.adminService.ResumeConsumer(ctx); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, h.adminService.ConsumerState(ctx))
+}
+
+// Routes godoc
+// @Summary registered http routes
+// @Description Routes of the public and admin servers
+// @Tags admin
+// @Produce json
+// @Success 200  {array} dto.Route
+// @Router /admin/routes [get]
+func (h *AdminHandler) Routes(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.adminService.Routes(c.Request().Context()))
+}
+
+// Goroutines godoc
+// @Summary goroutine stacks dump
+// @Tags admin
+// @Produce plain
+// @Success 200  {string} string
+// @Router /admin/goroutines [get]
+func (h *AdminHandler) Goroutines(c echo.Context) error {
+	res, err := h.adminService.Goroutines(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, res)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/handler/mocks"
+)
+
+func TestAdminHandler(t *testing.T) {
+	levels := dto.LogLevels{Level: "info", Components: map[string]string{"http": "debug"}}
+	type args struct {
+		method string
+		target string
+		body   string
+	}
+	type wants struct {
+		responseCode int
+		contentType  string
+		body         string
+		json         string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		prepare func(m *mocks.MockAdminService)
+		wants   wants
+	}{
+		{
+			name: "AdminHandler.LogLevels Case#1 Positive",
+			args: args{method: http.MethodGet, target: "/admin/log-level"},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().LogLevels(gomock.Any()).Return(levels)
+			},
+			wants: wants{responseCode: http.StatusOK, json: `{"level":"info","components":{"http":"debug"}}`},
+		},
+		{
+			name: "AdminHandler.SetLogLevel Case#2 Positive",
+			args: args{method: http.MethodPut, target: "/admin/log-level", body: `{"level":"info"}`},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().SetLogLevel(gomock.Any(), "", "info").Return(nil)
+				m.EXPECT().LogLevels(gomock.Any()).Return(levels)
+			},
+			wants: wants{responseCode: http.StatusOK, json: `{"level":"info","components":{"http":"debug"}}`},
+		},
+		{
+			name: "AdminHandler.SetComponentLogLevel Case#3 Bad level",
+			args: args{method: http.MethodPut, target: "/admin/log-level/http", body: `{"level":"loud"}`},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().SetLogLevel(gomock.Any(), "http", "loud").
+					Return(fmt.Errorf("%w: unknown log level loud", dto.ErrValidation))
+			},
+			wants: wants{responseCode: http.StatusUnprocessableEntity},
+		},
+		{
+			name:    "AdminHandler.SetComponentLogLevel Case#4 Bad body",
+			args:    args{method: http.MethodPut, target: "/admin/log-level/http", body: `{"level":`},
+			prepare: func(m *mocks.MockAdminService) {},
+			wants:   wants{responseCode: http.StatusUnprocessableEntity},
+		},
+		{
+			name: "AdminHandler.ResetComponentLogLevel Case#5 Positive",
+			args: args{method: http.MethodDelete, target: "/admin/log-level/http"},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().ResetLogLevel(gomock.Any(), "http")
+				m.EXPECT().LogLevels(gomock.Any()).Return(dto.LogLevels{Level: "info", Components: map[string]string{}})
+			},
+			wants: wants{responseCode: http.StatusOK, json: `{"level":"info","components":{}}`},
+		},
+		{
+			name: "AdminHandler.Config Case#6 Positive",
+			args: args{method: http.MethodGet, target: "/admin/config"},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().Config(gomock.Any()).Return([]byte("database:\n  password: '******'\n"), nil)
+			},
+			wants: wants{responseCode: http.StatusOK, contentType: "application/yaml", body: "database:\n  password: '******'\n"},
+		},
+		{
+			name: "AdminHandler.PauseConsumer Case#7 Positive",
+			args: args{method: http.MethodPost, target: "/admin/consumer/pause"},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().PauseConsumer(gomock.Any()).Return(nil)
+				m.EXPECT().ConsumerState(gomock.Any()).Return(dto.ConsumerState{Ready: true, Paused: true, Topics: []string{"ping"}})
+			},
+			wants: wants{responseCode: http.StatusOK, json: `{"ready":true,"paused":true,"topics":["ping"]}`},
+		},
+		{
+			name: "AdminHandler.ResumeConsumer Case#8 Consumer isn't running",
+			args: args{method: http.MethodPost, target: "/admin/consumer/resume"},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().ResumeConsumer(gomock.Any()).Return(fmt.Errorf("%w: consumer isn't ready", dto.ErrUnavailable))
+			},
+			wants: wants{responseCode: http.StatusServiceUnavailable},
+		},
+		{
+			name: "AdminHandler.Routes Case#9 Positive",
+			args: args{method: http.MethodGet, target: "/admin/routes"},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().Routes(gomock.Any()).Return([]dto.Route{{Server: "public", Method: http.MethodGet, Path: "/api/v1/ping"}})
+			},
+			wants: wants{responseCode: http.StatusOK, json: `[{"server":"public","method":"GET","path":"/api/v1/ping"}]`},
+		},
+		{
+			name: "AdminHandler.Goroutines Case#10 Positive",
+			args: args{method: http.MethodGet, target: "/admin/goroutines"},
+			prepare: func(m *mocks.MockAdminService) {
+				m.EXPECT().Goroutines(gomock.Any()).Return([]byte("goroutine 1 [running]:"), nil)
+			},
+			wants: wants{responseCode: http.StatusOK, contentType: echo.MIMETextPlainCharsetUTF8, body: "goroutine 1 [running]:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			service := mocks.NewMockAdminService(mockCtrl)
+			tt.prepare(service)
+			target := NewAdminHandler(service)
+			e := echo.New()
+			e.HTTPErrorHandler = ErrorHandler
+			e.GET("/admin/log-level", target.LogLevels)
+			e.PUT("/admin/log-level", target.SetLogLevel)
+			e.PUT("/admin/log-level/:component", target.SetComponentLogLevel)
+			e.DELETE("/admin/log-level/:component", target.ResetComponentLogLevel)
+			e.GET("/admin/config", target.Config)
+			e.GET("/admin/consumer", target.ConsumerState)
+			e.POST("/admin/consumer/pause", target.PauseConsumer)
+			e.POST("/admin/consumer/resume", target.ResumeConsumer)
+			e.GET("/admin/routes", target.Routes)
+			e.GET("/admin/goroutines", target.Goroutines)
+
+			req := httptest.NewRequest(tt.args.method, tt.args.target, strings.NewReader(tt.args.body))
+			if tt.args.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wants.responseCode, rec.Code)
+			if tt.wants.contentType != "" {
+				assert.Equal(t, tt.wants.contentType, rec.Header().Get(echo.HeaderContentType))
+			}
+			if tt.wants.body != "" {
+				assert.Equal(t, tt.wants.body, rec.Body.String())
+			}
+			if tt.wants.json != "" {
+				assert.JSONEq(t, tt.wants.json, rec.Body.String())
+			}
+		})
+	}
+}
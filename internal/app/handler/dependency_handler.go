@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// DependencyMonitor - service interface for detailed dependencies report
+//
+//go:generate mockgen -destination=mocks/mock_dependency_monitor.go -package=mocks . DependencyMonitor
+type DependencyMonitor interface {
+	Report(ctx context.Context) dto.DependencyReport
+	History(ctx context.Context, name string, limit int) ([]dto.DependencyCheck, error)
+}
+
+// DependencyHandler - admin handler for dependencies state
+type DependencyHandler struct {
+	infrastructure.SugarLogger
+	monitor DependencyMonitor
+}
+
+// NewDependencyHandler - return new DependencyHandler struct
+func NewDependencyHandler(monitor DependencyMonitor) *DependencyHandler {
+	var target DependencyHandler
+	target.monitor = monitor
+	return &target
+}
+
+// Report godoc
+// @Summary state of dependencies
+// @Description Status, latency, last success and failure times of postgres, kafka brokers and downstream services
+// @Tags admin
+// @Produce json
+// @Success 200  {object} dto.DependencyReport
+// @Router /admin/dependencies [get]
+func (h *DependencyHandler) Report(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.monitor.Report(c.Request().Context()))
+}
+
+// History godoc
+// @Summary recent check results of the dependency
+// @Description Results are ordered from the newest to the oldest
+// @Tags admin
+// @Produce json
+// @Param name path string true "dependency name"
+// @Param limit query int false "max count of results (all kept results by default)"
+// @Success 200  {array} dto.DependencyCheck
+// @Failure 404 {object} dto.ProblemDTO
+// @Failure 422 {object} dto.ProblemDTO
+// @Router /admin/dependencies/{name}/history [get]
+func (h *DependencyHandler) History(c echo.Context) error {
+	req, err := BindRequest[dto.DependencyHistoryRequest](c)
+	if err != nil {
+		return err
+	}
+	res, err := h.monitor.History(c.Request().Context(), req.Name, req.Limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
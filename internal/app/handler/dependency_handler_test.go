@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/handler/mocks"
+)
+
+func TestDependencyHandler(t *testing.T) {
+	checkTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	type wants struct {
+		responseCode int
+		json         string
+	}
+	tests := []struct {
+		name    string
+		target  string
+		prepare func(m *mocks.MockDependencyMonitor)
+		wants   wants
+	}{
+		{
+			name:   "DependencyHandler.Report Case#1 Positive",
+			target: "/admin/dependencies",
+			prepare: func(m *mocks.MockDependencyMonitor) {
+				m.EXPECT().Report(gomock.Any()).Return(dto.DependencyReport{
+					Status: dto.HealthStatusDown,
+					Dependencies: []dto.DependencyStatus{{
+						Name: "localhost:9092", Type: "kafka-broker", Status: dto.HealthStatusDown, LatencyMs: 1.5,
+						LastCheck: &checkTime, LastFailure: &checkTime, Error: "connection refused",
+					}},
+				})
+			},
+			wants: wants{
+				responseCode: http.StatusOK,
+				json: `{"status":"DOWN","dependencies":[{"name":"localhost:9092","type":"kafka-broker","status":"DOWN",
+					"latencyMs":1.5,"lastCheck":"2023-01-01T00:00:00Z","lastFailure":"2023-01-01T00:00:00Z","error":"connection refused"}]}`,
+			},
+		},
+		{
+			name:   "DependencyHandler.History Case#2 Positive",
+			target: "/admin/dependencies/postgres/history?limit=1",
+			prepare: func(m *mocks.MockDependencyMonitor) {
+				m.EXPECT().History(gomock.Any(), "postgres", 1).
+					Return([]dto.DependencyCheck{{CheckTime: checkTime, Status: dto.HealthStatusUp, LatencyMs: 0.7}}, nil)
+			},
+			wants: wants{
+				responseCode: http.StatusOK,
+				json:         `[{"checkTime":"2023-01-01T00:00:00Z","status":"UP","latencyMs":0.7}]`,
+			},
+		},
+		{
+			name:   "DependencyHandler.History Case#3 Unknown dependency",
+			target: "/admin/dependencies/redis/history",
+			prepare: func(m *mocks.MockDependencyMonitor) {
+				m.EXPECT().History(gomock.Any(), "redis", 0).Return(nil, dto.ErrEntityNotFound)
+			},
+			wants: wants{responseCode: http.StatusNotFound},
+		},
+		{
+			name:    "DependencyHandler.History Case#4 Bad limit",
+			target:  "/admin/dependencies/postgres/history?limit=-1",
+			prepare: func(m *mocks.MockDependencyMonitor) {},
+			wants:   wants{responseCode: http.StatusUnprocessableEntity},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			monitor := mocks.NewMockDependencyMonitor(mockCtrl)
+			tt.prepare(monitor)
+			target := NewDependencyHandler(monitor)
+
+			e := echo.New()
+			e.HTTPErrorHandler = ErrorHandler
+			e.GET("/admin/dependencies", target.Report)
+			e.GET("/admin/dependencies/:name/history", target.History)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wants.responseCode, rec.Code)
+			if tt.wants.json != "" {
+				assert.JSONEq(t, tt.wants.json, rec.Body.String())
+			}
+		})
+	}
+}
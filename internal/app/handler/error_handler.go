@@ -1,65 +1,154 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"go-service-template/internal/app/dto"
 	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
 )
 
-// ErrorHandler creates new error handler. It handles errors in custom way
-func ErrorHandler(incomingError error, c echo.Context) {
-	if c.Response().Committed || incomingError == nil {
-		return
-	}
+// MIMEApplicationProblemJSON - content type of error responses (RFC 7807)
+const MIMEApplicationProblemJSON = "application/problem+json"
 
-	log := infrastructure.GetBaseLogger(c.Request().Context())
-	var (
-		errorDTO *dto.ErrorDTO
-		status   int
-		cause    error
-		err      error
-	)
+const defaultProblemTypeBaseURL = "/problems/"
 
-	//nolint:errorlint
-	if he, ok := incomingError.(*echo.HTTPError); ok {
-		status = he.Code
-		cause = incomingError
-	} else {
-		switch {
-		case errors.Is(incomingError, dto.ErrEntityNotFound):
-			status = http.StatusNotFound
-			cause = dto.ErrEntityNotFound
-		default:
-			status = http.StatusInternalServerError
-			cause = incomingError
-		}
+// ErrorHandlerConfig - struct for error responses params
+type ErrorHandlerConfig struct {
+	// HideTechInfo - don't return internal error text (TechInfo) in responses. It must be true in production
+	HideTechInfo bool `env:"ERRORS_HIDE_TECH_INFO" yaml:"hideTechInfo"`
+
+	// TypeBaseURL - base of problem type URI. Error code is appended to it
+	TypeBaseURL string `yaml:"typeBaseURL"` //nolint:tagliatelle
+}
+
+// errorMapping - error code of the known error. If constraint is true, name of the violated constraint
+// is added to the detail
+type errorMapping struct {
+	target     error
+	code       dto.ErrorCode
+	constraint bool
+}
+
+// errorMappings - known errors. Order matters: the first matched error is used
+var errorMappings = []errorMapping{
+	{target: dto.ErrEntityNotFound, code: dto.CodeEntityNotFound},
+	{target: dto.ErrUnavailable, code: dto.CodeUnavailable},
+	{target: dto.ErrValidation, code: dto.CodeValidation},
+	{target: basedbhandler.ErrQueryTimeout, code: dto.CodeQueryTimeout},
+	{target: basedbhandler.ErrNotFound, code: dto.CodeEntityNotFound},
+	{target: basedbhandler.ErrVersionConflict, code: dto.CodeVersionConflict},
+	{target: basedbhandler.ErrConflict, code: dto.CodeEntityExists, constraint: true},
+	{target: basedbhandler.ErrConstraintViolation, code: dto.CodeConstraintViolation, constraint: true},
+	{target: basedbhandler.ErrRetryable, code: dto.CodeTemporaryError},
+	{target: infrastructure.ErrIdempotencyKeyInUse, code: dto.CodeIdempotencyKeyInUse},
+	{target: infrastructure.ErrIdempotencyKeyReused, code: dto.CodeIdempotencyKeyReused},
+}
+
+var defaultErrorHandler = NewErrorHandler(ErrorHandlerConfig{})
+
+// ErrorHandler - error handler with default params. TechInfo is returned
+func ErrorHandler(incomingError error, c echo.Context) {
+	defaultErrorHandler(incomingError, c)
+}
+
+// NewErrorHandler creates new error handler. Errors are returned in RFC 7807 format (application/problem+json)
+func NewErrorHandler(cfg ErrorHandlerConfig) echo.HTTPErrorHandler {
+	if cfg.TypeBaseURL == "" {
+		cfg.TypeBaseURL = defaultProblemTypeBaseURL
 	}
-	if status != 0 {
-		if !errors.As(incomingError, &errorDTO) {
-			errorDTO = &dto.ErrorDTO{
-				Cause:    cause,
-				Message:  cause.Error(),
-				TechInfo: incomingError.Error(),
-			}
+	return func(incomingError error, c echo.Context) {
+		if c.Response().Committed || incomingError == nil {
+			return
 		}
+
+		log := infrastructure.GetBaseLogger(c.Request().Context())
+		problem := NewProblem(c.Request().Context(), incomingError, cfg)
+
+		var err error
 		if c.Request().Method == http.MethodHead {
-			err = c.NoContent(status)
+			err = c.NoContent(problem.Status)
 		} else {
-			c.Response().Header().Set("content-type", echo.MIMEApplicationJSON)
-			err = c.JSON(status, errorDTO)
+			c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+			err = c.JSON(problem.Status, problem)
 		}
 		if err != nil {
 			log.Warn().Err(incomingError).Msg("Unable to create JSON response for an incoming error")
 		}
 		l := log.
 			With().
-			Int("status", status).
+			Int("status", problem.Status).
+			Str("code", string(problem.Code)).
 			Str("method", c.Request().Method).
 			Str("URI", c.Request().RequestURI).
 			Logger()
-		l.Info().Msg(errorDTO.Message)
+		l.Info().Err(incomingError).Msg(problem.Title)
+	}
+}
+
+// NewProblem - returns RFC 7807 problem for the error. RequestID from ctx is used as instance
+func NewProblem(ctx context.Context, incomingError error, cfg ErrorHandlerConfig) dto.ProblemDTO {
+	if cfg.TypeBaseURL == "" {
+		cfg.TypeBaseURL = defaultProblemTypeBaseURL
+	}
+	code := dto.CodeInternal
+	var detail string
+
+	//nolint:errorlint
+	if he, ok := incomingError.(*echo.HTTPError); ok {
+		code = dto.ErrorCodeByStatus(he.Code)
+		detail = fmt.Sprint(he.Message)
+	} else {
+		for _, m := range errorMappings {
+			if !errors.Is(incomingError, m.target) {
+				continue
+			}
+			code = m.code
+			detail = m.target.Error()
+			if m.constraint {
+				detail = dbErrorCause(incomingError, m.target).Error()
+			}
+			break
+		}
+	}
+
+	info, _ := dto.LookupErrorCode(code)
+	problem := dto.ProblemDTO{
+		Type:   cfg.TypeBaseURL + string(code),
+		Title:  info.Title,
+		Status: info.Status,
+		Detail: detail,
+		Code:   code,
+	}
+	//nolint:errorlint
+	if he, ok := incomingError.(*echo.HTTPError); ok && he.Code != info.Status {
+		// status without own code
+		problem.Status = he.Code
+		problem.Title = http.StatusText(he.Code)
+	}
+	if id, ok := ctx.Value(infrastructure.CtxKeyRequestID{}).(string); ok {
+		problem.Instance = id
+	}
+	var validationErr *dto.ValidationError
+	if errors.As(incomingError, &validationErr) {
+		problem.Errors = validationErr.Fields
+	}
+	if !cfg.HideTechInfo {
+		problem.TechInfo = incomingError.Error()
+	}
+	return problem
+}
+
+// dbErrorCause - returns kind of db error with the name of violated constraint (if any).
+// SQL error text isn't included, it's available in TechInfo
+func dbErrorCause(err error, kind error) error {
+	var dbErr *basedbhandler.DBError
+	if errors.As(err, &dbErr) && dbErr.Constraint != "" {
+		return fmt.Errorf("%w: %s", kind, dbErr.Constraint)
 	}
+	return kind
 }
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -8,11 +11,15 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
 )
 
 func TestErrorHandler(t *testing.T) {
 	type args struct {
 		incomingError error
+		cfg           ErrorHandlerConfig
+		requestID     string
 	}
 	type wants struct {
 		responseCode int
@@ -38,8 +45,8 @@ func TestErrorHandler(t *testing.T) {
 			args: args{incomingError: dto.ErrEntityNotFound},
 			wants: wants{
 				responseCode: http.StatusNotFound,
-				contentType:  echo.MIMEApplicationJSON,
-				json:         `{"message":"entity not found","techInfo":"entity not found"}`,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/entity-not-found","title":"Entity not found","status":404,"detail":"entity not found","code":"entity-not-found","techInfo":"entity not found"}`,
 			},
 		},
 		{
@@ -47,8 +54,133 @@ func TestErrorHandler(t *testing.T) {
 			args: args{incomingError: &echo.HTTPError{Code: 500, Message: "some http error"}},
 			wants: wants{
 				responseCode: http.StatusInternalServerError,
-				contentType:  echo.MIMEApplicationJSON,
-				json:         `{"message":"code=500, message=some http error", "techInfo":"code=500, message=some http error"}`,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/internal-error","title":"Internal error","status":500,"detail":"some http error","code":"internal-error","techInfo":"code=500, message=some http error"}`,
+			},
+		},
+		{
+			name: "Case 4. Query timeout",
+			args: args{incomingError: fmt.Errorf("can't get entity: %w", basedbhandler.ErrQueryTimeout)},
+			wants: wants{
+				responseCode: http.StatusGatewayTimeout,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/query-timeout","title":"Database query timeout","status":504,"detail":"database query timeout","code":"query-timeout","techInfo":"can't get entity: database query timeout"}`,
+			},
+		},
+		{
+			name: "Case 5. Validation error",
+			args: args{incomingError: fmt.Errorf("%w: name is empty", dto.ErrValidation)},
+			wants: wants{
+				responseCode: http.StatusUnprocessableEntity,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/validation-error","title":"Validation error","status":422,"detail":"validation error","code":"validation-error","techInfo":"validation error: name is empty"}`,
+			},
+		},
+		{
+			name: "Case 6. DB not found",
+			args: args{incomingError: &basedbhandler.DBError{Kind: basedbhandler.ErrNotFound, Err: errors.New("no rows in result set")}},
+			wants: wants{
+				responseCode: http.StatusNotFound,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/entity-not-found","title":"Entity not found","status":404,"detail":"entity not found","code":"entity-not-found","techInfo":"entity not found: no rows in result set"}`,
+			},
+		},
+		{
+			name: "Case 7. DB conflict",
+			args: args{incomingError: &basedbhandler.DBError{Kind: basedbhandler.ErrConflict, Constraint: "users_pk", Err: errors.New("duplicate key")}},
+			wants: wants{
+				responseCode: http.StatusConflict,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/entity-already-exists","title":"Entity already exists","status":409,"detail":"entity already exists: users_pk","code":"entity-already-exists","techInfo":"entity already exists (users_pk): duplicate key"}`,
+			},
+		},
+		{
+			name: "Case 8. Version conflict",
+			args: args{incomingError: fmt.Errorf("can't update order: %w", basedbhandler.ErrVersionConflict)},
+			wants: wants{
+				responseCode: http.StatusConflict,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/version-conflict","title":"Entity was modified concurrently","status":409,"detail":"entity was modified concurrently","code":"version-conflict","techInfo":"can't update order: entity was modified concurrently"}`,
+			},
+		},
+		{
+			name: "Case 9. DB constraint violation",
+			args: args{incomingError: &basedbhandler.DBError{Kind: basedbhandler.ErrConstraintViolation, Constraint: "orders_user_fk", Err: errors.New("fk violation")}},
+			wants: wants{
+				responseCode: http.StatusUnprocessableEntity,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/constraint-violation","title":"Constraint violation","status":422,"detail":"constraint violation: orders_user_fk","code":"constraint-violation","techInfo":"constraint violation (orders_user_fk): fk violation"}`,
+			},
+		},
+		{
+			name: "Case 10. DB retryable error",
+			args: args{incomingError: &basedbhandler.DBError{Kind: basedbhandler.ErrRetryable, Err: errors.New("deadlock detected")}},
+			wants: wants{
+				responseCode: http.StatusServiceUnavailable,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/temporary-error","title":"Temporary error","status":503,"detail":"temporary database error","code":"temporary-error","techInfo":"temporary database error: deadlock detected"}`,
+			},
+		},
+		{
+			name: "Case 11. Component is unavailable",
+			args: args{incomingError: fmt.Errorf("%w: kafka consumer isn't connected", dto.ErrUnavailable)},
+			wants: wants{
+				responseCode: http.StatusServiceUnavailable,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/component-unavailable","title":"Component is unavailable","status":503,"detail":"component is unavailable","code":"component-unavailable","techInfo":"component is unavailable: kafka consumer isn't connected"}`,
+			},
+		},
+		{
+			name: "Case 12. Validation error with fields",
+			args: args{
+				incomingError: dto.NewValidationError(dto.FieldError{Field: "name", Message: "is required"}),
+				requestID:     "req-1",
+			},
+			wants: wants{
+				responseCode: http.StatusUnprocessableEntity,
+				contentType:  MIMEApplicationProblemJSON,
+				json: `{"type":"/problems/validation-error","title":"Validation error","status":422,"detail":"validation error",
+					"instance":"req-1","code":"validation-error","errors":[{"field":"name","message":"is required"}],
+					"techInfo":"validation error: name: is required"}`,
+			},
+		},
+		{
+			name: "Case 13. TechInfo is hidden",
+			args: args{
+				incomingError: errors.New("dial tcp 10.0.0.1:5432: connection refused"),
+				cfg:           ErrorHandlerConfig{HideTechInfo: true, TypeBaseURL: "https://errors.example.com/"},
+			},
+			wants: wants{
+				responseCode: http.StatusInternalServerError,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"https://errors.example.com/internal-error","title":"Internal error","status":500,"code":"internal-error"}`,
+			},
+		},
+		{
+			name: "Case 14. HTTPError with status without own code",
+			args: args{incomingError: echo.NewHTTPError(http.StatusRequestEntityTooLarge, "body is too large"), cfg: ErrorHandlerConfig{HideTechInfo: true}},
+			wants: wants{
+				responseCode: http.StatusRequestEntityTooLarge,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/bad-request","title":"Request Entity Too Large","status":413,"detail":"body is too large","code":"bad-request"}`,
+			},
+		},
+		{
+			name: "Case 15. Rate limit exceeded",
+			args: args{incomingError: echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded"), cfg: ErrorHandlerConfig{HideTechInfo: true}},
+			wants: wants{
+				responseCode: http.StatusTooManyRequests,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/too-many-requests","title":"Too many requests","status":429,"detail":"rate limit exceeded","code":"too-many-requests"}`,
+			},
+		},
+		{
+			name: "Case 16. Idempotency key is used by other request",
+			args: args{incomingError: infrastructure.ErrIdempotencyKeyReused, cfg: ErrorHandlerConfig{HideTechInfo: true}},
+			wants: wants{
+				responseCode: http.StatusConflict,
+				contentType:  MIMEApplicationProblemJSON,
+				json:         `{"type":"/problems/idempotency-key-reused","title":"Idempotency key was used by other request","status":409,"detail":"idempotency key was used by other request","code":"idempotency-key-reused"}`,
 			},
 		},
 	}
@@ -58,10 +190,13 @@ func TestErrorHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.args.requestID != "" {
+				req = req.WithContext(context.WithValue(req.Context(), infrastructure.CtxKeyRequestID{}, tt.args.requestID))
+			}
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 			c.Response().Header().Set("content-type", echo.MIMEApplicationXML)
-			ErrorHandler(tt.args.incomingError, c)
+			NewErrorHandler(tt.args.cfg)(tt.args.incomingError, c)
 			assert.Equal(t, tt.wants.responseCode, rec.Code, "Expected response is  %d, got %d", tt.wants.responseCode, rec.Code)
 			contentType := rec.Header().Get("content-type")
 			assert.Equal(t, tt.wants.contentType, contentType, "Expected status %d, got %d", tt.wants.contentType, contentType)
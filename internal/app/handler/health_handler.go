@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// HealthService - service interface for probes
+//
+//go:generate mockgen -destination=mocks/mock_health_service.go -package=mocks . HealthService
+type HealthService interface {
+	Live(ctx context.Context) dto.Health
+	Ready(ctx context.Context) dto.Health
+	Startup(ctx context.Context) dto.Health
+}
+
+// HealthHandler - handler for kubernetes liveness, readiness and startup probes
+type HealthHandler struct {
+	infrastructure.SugarLogger
+	healthService HealthService
+}
+
+// NewHealthHandler - return new HealthHandler struct
+func NewHealthHandler(service HealthService) *HealthHandler {
+	var target HealthHandler
+	target.healthService = service
+	return &target
+}
+
+// Live godoc
+// @Summary liveness probe
+// @Description Service is alive while it can answer
+// @Tags health
+// @Produce json
+// @Success 200  {object} dto.Health
+// @Failure 503 {object} dto.Health
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c echo.Context) error {
+	return healthResponse(c, h.healthService.Live(c.Request().Context()))
+}
+
+// Ready godoc
+// @Summary readiness probe
+// @Description Service is ready if it is started, isn't shutting down and all dependencies are available
+// @Tags health
+// @Produce json
+// @Success 200  {object} dto.Health
+// @Failure 503 {object} dto.Health
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c echo.Context) error {
+	return healthResponse(c, h.healthService.Ready(c.Request().Context()))
+}
+
+// Startup godoc
+// @Summary startup probe
+// @Description Succeeds when the service is started
+// @Tags health
+// @Produce json
+// @Success 200  {object} dto.Health
+// @Failure 503 {object} dto.Health
+// @Router /health/startup [get]
+func (h *HealthHandler) Startup(c echo.Context) error {
+	return healthResponse(c, h.healthService.Startup(c.Request().Context()))
+}
+
+func healthResponse(c echo.Context, health dto.Health) error {
+	if health.Status != dto.HealthStatusUp {
+		return c.JSON(http.StatusServiceUnavailable, health)
+	}
+	return c.JSON(http.StatusOK, health)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/handler/mocks"
+)
+
+func TestHealthHandler(t *testing.T) {
+	type wants struct {
+		responseCode int
+		json         string
+	}
+	tests := []struct {
+		name    string
+		target  string
+		prepare func(s *mocks.MockHealthService)
+		wants   wants
+	}{
+		{
+			name:   "HealthHandler.Live Case#1 Positive",
+			target: "/health/live",
+			prepare: func(s *mocks.MockHealthService) {
+				s.EXPECT().Live(gomock.Any()).Return(dto.Health{Status: dto.HealthStatusUp})
+			},
+			wants: wants{responseCode: http.StatusOK, json: `{"status":"UP"}`},
+		},
+		{
+			name:   "HealthHandler.Ready Case#2 Positive",
+			target: "/health/ready",
+			prepare: func(s *mocks.MockHealthService) {
+				s.EXPECT().Ready(gomock.Any()).Return(dto.Health{
+					Status: dto.HealthStatusUp,
+					Checks: map[string]dto.HealthCheck{"postgres": {Status: dto.HealthStatusUp}},
+				})
+			},
+			wants: wants{responseCode: http.StatusOK, json: `{"status":"UP","checks":{"postgres":{"status":"UP"}}}`},
+		},
+		{
+			name:   "HealthHandler.Ready Case#3 Dependency is down",
+			target: "/health/ready",
+			prepare: func(s *mocks.MockHealthService) {
+				s.EXPECT().Ready(gomock.Any()).Return(dto.Health{
+					Status: dto.HealthStatusDown,
+					Checks: map[string]dto.HealthCheck{"postgres": {Status: dto.HealthStatusDown, Error: "database connection isn't ready"}},
+				})
+			},
+			wants: wants{
+				responseCode: http.StatusServiceUnavailable,
+				json:         `{"status":"DOWN","checks":{"postgres":{"status":"DOWN","error":"database connection isn't ready"}}}`,
+			},
+		},
+		{
+			name:   "HealthHandler.Startup Case#4 Not started",
+			target: "/health/startup",
+			prepare: func(s *mocks.MockHealthService) {
+				s.EXPECT().Startup(gomock.Any()).Return(dto.Health{Status: dto.HealthStatusDown})
+			},
+			wants: wants{responseCode: http.StatusServiceUnavailable, json: `{"status":"DOWN"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			healthService := mocks.NewMockHealthService(mockCtrl)
+			tt.prepare(healthService)
+			target := NewHealthHandler(healthService)
+
+			e := echo.New()
+			e.HTTPErrorHandler = ErrorHandler
+			e.GET("/health/live", target.Live)
+			e.GET("/health/ready", target.Ready)
+			e.GET("/health/startup", target.Startup)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wants.responseCode, rec.Code)
+			assert.JSONEq(t, tt.wants.json, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// JobService - service interface for background jobs administration
+//
+//go:generate mockgen -destination=mocks/mock_job_service.go -package=mocks . JobService
+type JobService interface {
+	List(ctx context.Context, filter dto.JobFilter) ([]dto.Job, error)
+	ListDead(ctx context.Context, filter dto.JobFilter) ([]dto.Job, error)
+	Retry(ctx context.Context, id int64) error
+	RetryDead(ctx context.Context, id int64) error
+}
+
+// JobHandler - admin handler for background jobs
+type JobHandler struct {
+	infrastructure.SugarLogger
+	jobService JobService
+}
+
+// NewJobHandler - return new JobHandler struct
+func NewJobHandler(service JobService) *JobHandler {
+	var target JobHandler
+	target.jobService = service
+	return &target
+}
+
+// List godoc
+// @Summary list of background jobs waiting in the queue or running
+// @Description Method for jobs administration
+// @Tags admin
+// @Produce json
+// @Param queue query string false "queue name"
+// @Param kind query string false "job kind"
+// @Param status query string false "pending or running"
+// @Param limit query int false "max count of jobs (100 by default, if it is 0)"
+// @Success 200  {array} dto.Job
+// @Failure 422 {object} dto.ProblemDTO
+// @Router /admin/jobs [get]
+func (h *JobHandler) List(c echo.Context) error {
+	filter, err := BindRequest[dto.JobFilter](c)
+	if err != nil {
+		return err
+	}
+	res, err := h.jobService.List(c.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListDead godoc
+// @Summary list of background jobs failed after all attempts
+// @Description Method for jobs administration
+// @Tags admin
+// @Produce json
+// @Param queue query string false "queue name"
+// @Param kind query string false "job kind"
+// @Param limit query int false "max count of jobs (100 by default, if it is 0)"
+// @Success 200  {array} dto.Job
+// @Failure 422 {object} dto.ProblemDTO
+// @Router /admin/jobs/dead [get]
+func (h *JobHandler) ListDead(c echo.Context) error {
+	filter, err := BindRequest[dto.JobFilter](c)
+	if err != nil {
+		return err
+	}
+	res, err := h.jobService.ListDead(c.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Retry godoc
+// @Summary run pending job immediately
+// @Description Method for jobs administration
+// @Tags admin
+// @Param id path int true "job id"
+// @Success 204
+// @Failure 404 {object} dto.ProblemDTO
+// @Failure 422 {object} dto.ProblemDTO
+// @Router /admin/jobs/{id}/retry [post]
+func (h *JobHandler) Retry(c echo.Context) error {
+	req, err := BindRequest[dto.JobRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.jobService.Retry(c.Request().Context(), req.ID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RetryDead godoc
+// @Summary return dead job into the queue
+// @Description Method for jobs administration
+// @Tags admin
+// @Param id path int true "job id"
+// @Success 204
+// @Failure 404 {object} dto.ProblemDTO
+// @Failure 409 {object} dto.ProblemDTO
+// @Failure 422 {object} dto.ProblemDTO
+// @Router /admin/jobs/dead/{id}/retry [post]
+func (h *JobHandler) RetryDead(c echo.Context) error {
+	req, err := BindRequest[dto.JobRequest](c)
+	if err != nil {
+		return err
+	}
+	if err = h.jobService.RetryDead(c.Request().Context(), req.ID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/handler/mocks"
+	"go-service-template/internal/app/repository/basedbhandler"
+	"go-service-template/internal/app/service"
+	serviceMocks "go-service-template/internal/app/service/mocks"
+)
+
+func TestJobHandler(t *testing.T) {
+	type wants struct {
+		responseCode int
+		json         string
+	}
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		prepare func(s *mocks.MockJobService)
+		wants   wants
+	}{
+		{
+			name:   "JobHandler.List Case#1 Positive",
+			method: http.MethodGet,
+			target: "/admin/jobs?queue=default&limit=10",
+			prepare: func(s *mocks.MockJobService) {
+				s.EXPECT().List(gomock.Any(), dto.JobFilter{Queue: "default", Limit: 10}).
+					Return([]dto.Job{{ID: 1, Queue: "default", Kind: "send_email", Payload: []byte(`{}`), Status: "pending"}}, nil)
+			},
+			wants: wants{
+				responseCode: http.StatusOK,
+				json: `[{"id":1,"queue":"default","kind":"send_email","payload":{},"status":"pending","attempt":0,
+					"maxAttempts":0,"createTime":"0001-01-01T00:00:00Z"}]`,
+			},
+		},
+		{
+			name:    "JobHandler.List Case#2 Bad limit",
+			method:  http.MethodGet,
+			target:  "/admin/jobs?limit=abc",
+			prepare: func(s *mocks.MockJobService) {},
+			wants:   wants{responseCode: http.StatusUnprocessableEntity},
+		},
+		{
+			name:   "JobHandler.ListDead Case#3 Positive",
+			method: http.MethodGet,
+			target: "/admin/jobs/dead",
+			prepare: func(s *mocks.MockJobService) {
+				s.EXPECT().ListDead(gomock.Any(), dto.JobFilter{}).Return([]dto.Job{}, nil)
+			},
+			wants: wants{responseCode: http.StatusOK, json: `[]`},
+		},
+		{
+			name:   "JobHandler.Retry Case#4 Positive",
+			method: http.MethodPost,
+			target: "/admin/jobs/5/retry",
+			prepare: func(s *mocks.MockJobService) {
+				s.EXPECT().Retry(gomock.Any(), int64(5)).Return(nil)
+			},
+			wants: wants{responseCode: http.StatusNoContent},
+		},
+		{
+			name:   "JobHandler.Retry Case#5 Not found",
+			method: http.MethodPost,
+			target: "/admin/jobs/5/retry",
+			prepare: func(s *mocks.MockJobService) {
+				s.EXPECT().Retry(gomock.Any(), int64(5)).Return(basedbhandler.ErrNotFound)
+			},
+			wants: wants{responseCode: http.StatusNotFound},
+		},
+		{
+			name:    "JobHandler.Retry Case#6 Bad id",
+			method:  http.MethodPost,
+			target:  "/admin/jobs/abc/retry",
+			prepare: func(s *mocks.MockJobService) {},
+			wants:   wants{responseCode: http.StatusUnprocessableEntity},
+		},
+		{
+			name:   "JobHandler.RetryDead Case#7 Conflict",
+			method: http.MethodPost,
+			target: "/admin/jobs/dead/7/retry",
+			prepare: func(s *mocks.MockJobService) {
+				s.EXPECT().RetryDead(gomock.Any(), int64(7)).Return(basedbhandler.ErrConflict)
+			},
+			wants: wants{responseCode: http.StatusConflict},
+		},
+		{
+			name:    "JobHandler.List Case#8 Bad status",
+			method:  http.MethodGet,
+			target:  "/admin/jobs?status=dead",
+			prepare: func(s *mocks.MockJobService) {},
+			wants: wants{
+				responseCode: http.StatusUnprocessableEntity,
+				json: `{"type":"/problems/validation-error","title":"Validation error","status":422,"detail":"validation error",
+					"code":"validation-error","errors":[{"field":"status","message":"must be one of: pending, running"}],
+					"techInfo":"validation error: status: must be one of: pending, running"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			jobService := mocks.NewMockJobService(mockCtrl)
+			tt.prepare(jobService)
+			target := NewJobHandler(jobService)
+
+			e := echo.New()
+			e.HTTPErrorHandler = ErrorHandler
+			e.GET("/admin/jobs", target.List)
+			e.GET("/admin/jobs/dead", target.ListDead)
+			e.POST("/admin/jobs/:id/retry", target.Retry)
+			e.POST("/admin/jobs/dead/:id/retry", target.RetryDead)
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wants.responseCode, rec.Code)
+			if tt.wants.json != "" {
+				assert.JSONEq(t, tt.wants.json, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestJobHandler_RetryMissingJob(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	jobRepository := serviceMocks.NewMockJobRepository(mockCtrl)
+	txHelper := serviceMocks.NewMockTxHelper(mockCtrl)
+	// transaction is rolled back and the error of the function is returned
+	txHelper.EXPECT().WithTx(gomock.Any()).Return(service.TxFunc(func(ctx context.Context, f service.InFunc) error {
+		return f(ctx)
+	})).Times(2)
+	jobRepository.EXPECT().Retry(gomock.Any(), int64(5)).Return(basedbhandler.ErrNotFound)
+	jobRepository.EXPECT().RetryDead(gomock.Any(), int64(7)).Return(basedbhandler.ErrNotFound)
+	target := NewJobHandler(service.NewJobService(jobRepository, txHelper))
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler
+	e.POST("/admin/jobs/:id/retry", target.Retry)
+	e.POST("/admin/jobs/dead/:id/retry", target.RetryDead)
+
+	for _, path := range []string{"/admin/jobs/5/retry", "/admin/jobs/dead/7/retry"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, "JobHandler. Missing job %s", path)
+	}
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgconn"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// DefaultNotificationHandler is trivial postgres notification handler. Just writes channel and payload to the log.
+func DefaultNotificationHandler(ctx context.Context, notification pgconn.Notification) error {
+	l := infrastructure.GetBaseLogger(ctx).
+		With().
+		Str("channel", notification.Channel).
+		Str("payload", notification.Payload).
+		Logger()
+	l.Info().Msg("Processed")
+	return nil
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/dto"
+)
+
+// RequestValidator - echo validator of request DTOs based on go-playground validator.
+// Field names of errors are taken from json, query or param tags
+type RequestValidator struct {
+	validate *validator.Validate
+}
+
+// NewRequestValidator - returns new RequestValidator
+func NewRequestValidator() *RequestValidator {
+	var target RequestValidator
+	target.validate = validator.New()
+	target.validate.RegisterTagNameFunc(requestFieldName)
+	return &target
+}
+
+var defaultRequestValidator = NewRequestValidator()
+
+// Validate - implementation of echo.Validator. Failures are returned as dto.ValidationError
+func (v *RequestValidator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	if err == nil {
+		return nil
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return fmt.Errorf("%w: %s", dto.ErrValidation, err)
+	}
+	fields := make([]dto.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, dto.FieldError{Field: fe.Field(), Message: fieldErrorMessage(fe)})
+	}
+	return dto.NewValidationError(fields...)
+}
+
+// BindRequest - binds path params, query params (GET, DELETE) and body into the request DTO and validates it.
+// Echo validator is used if it's set, otherwise the default RequestValidator
+func BindRequest[T any](c echo.Context) (T, error) {
+	var req T
+	if err := c.Bind(&req); err != nil {
+		return req, bindError(err)
+	}
+	var err error
+	if c.Echo().Validator != nil {
+		err = c.Validate(&req)
+	} else {
+		err = defaultRequestValidator.Validate(&req)
+	}
+	return req, err
+}
+
+// bindError - converts bad request error of echo binder into dto.ValidationError
+func bindError(err error) error {
+	var he *echo.HTTPError
+	if !errors.As(err, &he) || he.Code != http.StatusBadRequest {
+		return err
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(he.Internal, &typeErr) && typeErr.Field != "" {
+		return dto.NewValidationError(dto.FieldError{Field: typeErr.Field, Message: "must be " + typeErr.Type.String()})
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(he.Internal, &syntaxErr) {
+		return dto.NewValidationError(dto.FieldError{Field: "body", Message: "malformed json"})
+	}
+	return dto.NewValidationError(dto.FieldError{Field: "request", Message: fmt.Sprint(he.Message)})
+}
+
+// requestFieldName - returns name of the field in request: json, query or param tag
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "param"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// fieldErrorMessage - returns human-readable message of the failed validation rule
+func fieldErrorMessage(fe validator.FieldError) string {
+	kind := fe.Kind()
+	isString := kind == reflect.String
+	isList := kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+	switch fe.Tag() {
+	case "required", "required_if", "required_with", "required_without":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "min", "gte":
+		if isString {
+			return "must be at least " + fe.Param() + " characters long"
+		}
+		if isList {
+			return "must contain at least " + fe.Param() + " items"
+		}
+		return "must be greater than or equal to " + fe.Param()
+	case "max", "lte":
+		if isString {
+			return "must be at most " + fe.Param() + " characters long"
+		}
+		if isList {
+			return "must contain at most " + fe.Param() + " items"
+		}
+		return "must be less than or equal to " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "len":
+		return "must be " + fe.Param() + " characters long"
+	case "email":
+		return "must be a valid email"
+	case "uuid", "uuid4":
+		return "must be a valid uuid"
+	default:
+		return "failed on the '" + fe.Tag() + "' rule"
+	}
+}
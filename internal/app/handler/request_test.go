@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequest struct {
+	ID    int64    `param:"id" validate:"gt=0"`
+	Sort  string   `query:"sort" validate:"omitempty,oneof=asc desc"`
+	Name  string   `json:"name" validate:"required,max=5"`
+	Age   int      `json:"age" validate:"gte=18"`
+	Email string   `json:"email" validate:"omitempty,email"`
+	Tags  []string `json:"tags" validate:"max=2"`
+}
+
+func TestBindRequest(t *testing.T) {
+	type args struct {
+		method string
+		target string
+		body   string
+	}
+	type wants struct {
+		responseCode int
+		json         string
+	}
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name:  "BindRequest Case#1 Positive",
+			args:  args{method: http.MethodPut, target: "/items/5", body: `{"name":"bob","age":18,"tags":["a"]}`},
+			wants: wants{responseCode: http.StatusOK, json: `{"ID":5,"Sort":"","name":"bob","age":18,"email":"","tags":["a"]}`},
+		},
+		{
+			name:  "BindRequest Case#2 Query params of GET request",
+			args:  args{method: http.MethodGet, target: "/items/5?sort=desc", body: `{"name":"bob","age":20}`},
+			wants: wants{responseCode: http.StatusOK, json: `{"ID":5,"Sort":"desc","name":"bob","age":20,"email":"","tags":null}`},
+		},
+		{
+			name: "BindRequest Case#3 Validation failed",
+			args: args{method: http.MethodGet, target: "/items/0?sort=up", body: `{"name":"robert","age":17,"email":"bob","tags":["a","b","c"]}`},
+			wants: wants{responseCode: http.StatusUnprocessableEntity, json: `[
+				{"field":"id","message":"must be greater than 0"},
+				{"field":"sort","message":"must be one of: asc, desc"},
+				{"field":"name","message":"must be at most 5 characters long"},
+				{"field":"age","message":"must be greater than or equal to 18"},
+				{"field":"email","message":"must be a valid email"},
+				{"field":"tags","message":"must contain at most 2 items"}]`},
+		},
+		{
+			name:  "BindRequest Case#4 Required field",
+			args:  args{method: http.MethodPut, target: "/items/5", body: `{"age":18}`},
+			wants: wants{responseCode: http.StatusUnprocessableEntity, json: `[{"field":"name","message":"is required"}]`},
+		},
+		{
+			name:  "BindRequest Case#5 Wrong type of body field",
+			args:  args{method: http.MethodPut, target: "/items/5", body: `{"name":"bob","age":"old"}`},
+			wants: wants{responseCode: http.StatusUnprocessableEntity, json: `[{"field":"age","message":"must be int"}]`},
+		},
+		{
+			name:  "BindRequest Case#6 Malformed body",
+			args:  args{method: http.MethodPut, target: "/items/5", body: `{"name":`},
+			wants: wants{responseCode: http.StatusUnprocessableEntity},
+		},
+		{
+			name:  "BindRequest Case#7 Bad path param",
+			args:  args{method: http.MethodPut, target: "/items/abc", body: `{"name":"bob","age":18}`},
+			wants: wants{responseCode: http.StatusUnprocessableEntity},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, validator := range []echo.Validator{nil, NewRequestValidator()} {
+				e := echo.New()
+				e.Validator = validator
+				e.HTTPErrorHandler = ErrorHandler
+				h := func(c echo.Context) error {
+					req, err := BindRequest[testRequest](c)
+					if err != nil {
+						return err
+					}
+					return c.JSON(http.StatusOK, req)
+				}
+				e.GET("/items/:id", h)
+				e.PUT("/items/:id", h)
+
+				req := httptest.NewRequest(tt.args.method, tt.args.target, strings.NewReader(tt.args.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				assert.Equal(t, tt.wants.responseCode, rec.Code)
+				if tt.wants.json == "" {
+					continue
+				}
+				if rec.Code == http.StatusOK {
+					assert.JSONEq(t, tt.wants.json, rec.Body.String())
+					continue
+				}
+				var problem struct {
+					Errors interface{} `json:"errors"`
+				}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				got, _ := json.Marshal(problem.Errors)
+				assert.JSONEq(t, tt.wants.json, string(got))
+			}
+		})
+	}
+}
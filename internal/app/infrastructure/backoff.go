@@ -0,0 +1,62 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultBackoffInitial = time.Second
+	defaultBackoffMax     = time.Minute
+)
+
+// Backoff - exponential delay between reconnection attempts. Delay is doubled after each attempt up to Max.
+// Zero values of Initial and Max are replaced by defaults (1s and 1m). Backoff isn't safe for concurrent use
+type Backoff struct {
+	// Initial - delay before the first attempt
+	Initial time.Duration
+
+	// Max - max delay between attempts
+	Max time.Duration
+
+	current time.Duration
+}
+
+// Next - returns delay before the next attempt
+func (b *Backoff) Next() time.Duration {
+	if b.Initial <= 0 {
+		b.Initial = defaultBackoffInitial
+	}
+	if b.Max < b.Initial {
+		b.Max = defaultBackoffMax
+		if b.Max < b.Initial {
+			b.Max = b.Initial
+		}
+	}
+	if b.current == 0 {
+		b.current = b.Initial
+		return b.current
+	}
+	b.current *= 2
+	if b.current > b.Max {
+		b.current = b.Max
+	}
+	return b.current
+}
+
+// Reset - starts delays from Initial again. Is called after successful attempt
+func (b *Backoff) Reset() {
+	b.current = 0
+}
+
+// Wait - sleeps for the next delay. Returns false if ctx is canceled before the delay expires
+func (b *Backoff) Wait(ctx context.Context) bool {
+	timer := time.NewTimer(b.Next())
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff Backoff
+		want    []time.Duration
+	}{
+		{
+			name:    "Backoff. Next. Case #1. Delay is doubled up to Max",
+			backoff: Backoff{Initial: time.Second, Max: time.Second * 5},
+			want:    []time.Duration{time.Second, time.Second * 2, time.Second * 4, time.Second * 5, time.Second * 5},
+		},
+		{
+			name:    "Backoff. Next. Case #2. Defaults",
+			backoff: Backoff{},
+			want:    []time.Duration{time.Second, time.Second * 2, time.Second * 4},
+		},
+		{
+			name:    "Backoff. Next. Case #3. Max less than Initial",
+			backoff: Backoff{Initial: time.Minute * 2, Max: time.Second},
+			want:    []time.Duration{time.Minute * 2, time.Minute * 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]time.Duration, len(tt.want))
+			for ind := range got {
+				got[ind] = tt.backoff.Next()
+			}
+			assert.Equal(t, tt.want, got)
+			tt.backoff.Reset()
+			assert.Equal(t, tt.want[0], tt.backoff.Next())
+		})
+	}
+}
+
+func TestBackoff_Wait(t *testing.T) {
+	b := Backoff{Initial: time.Millisecond}
+	assert.True(t, b.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b = Backoff{Initial: time.Minute}
+	assert.False(t, b.Wait(ctx))
+}
@@ -13,6 +13,21 @@ type (
 
 	// CtxKeyCorrelationID  - key for  correlationID context param
 	CtxKeyCorrelationID struct{}
+
+	// CtxKeyPrimary - key for context param that forces read queries to the primary database
+	CtxKeyPrimary struct{}
+
+	// CtxKeyStatementTimeout - key for context param that overrides default statement timeout
+	CtxKeyStatementTimeout struct{}
+
+	// CtxKeyTenant - key for tenant context param
+	CtxKeyTenant struct{}
+
+	// CtxKeyUser - key for caller identity context param
+	CtxKeyUser struct{}
+
+	// CtxKeyPrincipal - key for authenticated caller context param
+	CtxKeyPrincipal struct{}
 )
 
 const (
@@ -27,4 +42,16 @@ const (
 
 	// CorrelationIDField - correlationID field name. Used for logger
 	CorrelationIDField = "correlationID"
+
+	// TenantHeader - name of the header for tenant storing. Used for HTTP requests and kafka messages
+	TenantHeader = "X-Tenant-Id"
+
+	// TenantField - tenant field name. Used for logger
+	TenantField = "tenant"
+
+	// UserField - caller identity field name. Used for logger
+	UserField = "user"
+
+	// SystemUser - caller identity for actions without user (background jobs, messages without identity)
+	SystemUser = "system"
 )
@@ -0,0 +1,61 @@
+package mymiddleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:          "AdminAuth. Case #1. Valid token",
+			authorization: "Bearer secret",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "AdminAuth. Case #2. Invalid token",
+			authorization: "Bearer secret2",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:       "AdminAuth. Case #3. Token is absent",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "AdminAuth. Case #4. Wrong scheme",
+			authorization: "Basic secret",
+			wantStatus:    http.StatusUnauthorized,
+		},
+	}
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := AdminAuth("secret")(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			err := h(c)
+			status := rec.Code
+			var he *echo.HTTPError
+			if errors.As(err, &he) {
+				status = he.Code
+			}
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
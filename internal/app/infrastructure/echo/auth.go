@@ -0,0 +1,182 @@
+package mymiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	defaultRolesClaim = "roles"
+	defaultScopeClaim = "scope"
+)
+
+// signingMethods - allowed token signature algorithms
+var signingMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}
+
+// AuthConfig - struct for JWT authentication params
+type AuthConfig struct {
+	// Enabled - authenticate API requests
+	Enabled bool `env:"AUTH_ENABLED" yaml:"enabled"`
+
+	// JWKSFile - path to local JWKS file. It has priority over JWKSURL
+	JWKSFile string `env:"AUTH_JWKS_FILE" yaml:"jwksFile"`
+
+	// JWKSURL - URL of identity provider JWKS
+	JWKSURL string `env:"AUTH_JWKS_URL" yaml:"jwksURL"` //nolint:tagliatelle
+
+	// CacheTTL - keys are reloaded after CacheTTL
+	CacheTTL time.Duration `yaml:"cacheTTL"` //nolint:tagliatelle
+
+	// Issuer - expected iss claim. It isn't checked if empty
+	Issuer string `env:"AUTH_ISSUER" yaml:"issuer"`
+
+	// Audience - expected aud claim. It isn't checked if empty
+	Audience string `env:"AUTH_AUDIENCE" yaml:"audience"`
+
+	// RolesClaim - claim with roles. Nested claims are separated by dot, e.g. realm_access.roles
+	RolesClaim string `yaml:"rolesClaim"`
+
+	// ScopeClaim - claim with scopes (space separated string or array)
+	ScopeClaim string `yaml:"scopeClaim"`
+}
+
+// Authenticate - middleware which validates bearer token (RS256 or ES256) and adds the principal into context.
+// Subject is added to the logger. Request without valid token is rejected with 401
+func Authenticate(keys KeyProvider, cfg AuthConfig) echo.MiddlewareFunc {
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = defaultRolesClaim
+	}
+	if cfg.ScopeClaim == "" {
+		cfg.ScopeClaim = defaultScopeClaim
+	}
+	parser := &jwt.Parser{ValidMethods: signingMethods}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			raw := c.Request().Header.Get(echo.HeaderAuthorization)
+			if len(raw) <= len("Bearer ") || !strings.EqualFold(raw[:len("Bearer ")], "Bearer ") {
+				return unauthorized(c, "bearer token is required")
+			}
+			claims := jwt.MapClaims{}
+			_, err := parser.ParseWithClaims(raw[len("Bearer "):], claims, func(token *jwt.Token) (interface{}, error) {
+				kid, _ := token.Header["kid"].(string)
+				return keys.Key(ctx, kid)
+			})
+			if err == nil {
+				err = verifyClaims(claims, cfg)
+			}
+			if err != nil {
+				infrastructure.GetBaseLogger(ctx).Debug().Err(err).Msg("invalid bearer token")
+				return unauthorized(c, "invalid bearer token")
+			}
+
+			principal := &infrastructure.Principal{
+				Subject: claims["sub"].(string),
+				Roles:   claimStrings(claims, cfg.RolesClaim),
+				Scopes:  claimStrings(claims, cfg.ScopeClaim),
+				Claims:  claims,
+			}
+			ctx = infrastructure.WithPrincipal(ctx, principal)
+			l := infrastructure.GetBaseLogger(ctx).With().Str(infrastructure.UserField, principal.Subject).Logger()
+			ctx = context.WithValue(ctx, infrastructure.CtxKeyLogger{}, &l)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// RequireRoles - middleware which allows requests of callers having any of the roles.
+// It must be used after Authenticate. Request of the caller without roles is rejected with 403
+func RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return requirePrincipal(func(p *infrastructure.Principal) bool {
+		for _, role := range roles {
+			if p.HasRole(role) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RequireScopes - middleware which allows requests of callers granted all the scopes.
+// It must be used after Authenticate. Request of the caller without scopes is rejected with 403
+func RequireScopes(scopes ...string) echo.MiddlewareFunc {
+	return requirePrincipal(func(p *infrastructure.Principal) bool {
+		for _, scope := range scopes {
+			if !p.HasScope(scope) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func requirePrincipal(allowed func(p *infrastructure.Principal) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := infrastructure.PrincipalFromContext(c.Request().Context())
+			if !ok {
+				return unauthorized(c, "request isn't authenticated")
+			}
+			if !allowed(principal) {
+				return echo.NewHTTPError(http.StatusForbidden, "access denied")
+			}
+			return next(c)
+		}
+	}
+}
+
+func unauthorized(c echo.Context, message string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+	return echo.NewHTTPError(http.StatusUnauthorized, message)
+}
+
+// verifyClaims - checks sub, exp, iss and aud claims. Parser checks time claims only if they are present,
+// so tokens without exp are rejected here
+func verifyClaims(claims jwt.MapClaims, cfg AuthConfig) error {
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return errors.New("sub claim is empty")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return errors.New("exp claim is absent or expired")
+	}
+	if cfg.Issuer != "" && !claims.VerifyIssuer(cfg.Issuer, true) {
+		return errors.New("bad issuer")
+	}
+	if cfg.Audience != "" && !claims.VerifyAudience(cfg.Audience, true) {
+		return errors.New("bad audience")
+	}
+	return nil
+}
+
+// claimStrings - returns values of the claim. Claim may be space separated string or array of strings
+func claimStrings(claims jwt.MapClaims, path string) []string {
+	var value interface{} = map[string]interface{}(claims)
+	for _, name := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = obj[name]
+	}
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
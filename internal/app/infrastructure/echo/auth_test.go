@@ -0,0 +1,201 @@
+package mymiddleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/infrastructure"
+)
+
+type testKeys map[string]crypto.PublicKey
+
+func (k testKeys) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := k[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keys := testKeys{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey}
+	cfg := AuthConfig{Issuer: "https://idp.example.com", Audience: "go-service-template", RolesClaim: "realm_access.roles"}
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+		base := jwt.MapClaims{
+			"sub": "alice",
+			"iss": "https://idp.example.com",
+			"aud": []string{"go-service-template"},
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}
+		for k, v := range claims {
+			if v == nil {
+				delete(base, k)
+				continue
+			}
+			base[k] = v
+		}
+		token := jwt.NewWithClaims(method, base)
+		token.Header["kid"] = kid
+		res, err := token.SignedString(key)
+		require.NoError(t, err)
+		return res
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		middlewares   []echo.MiddlewareFunc
+		wantStatus    int
+		wantPrincipal *infrastructure.Principal
+	}{
+		{
+			name: "Authenticate. Case #1. RS256 token",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{
+				"realm_access": map[string]interface{}{"roles": []string{"admin", "user"}},
+				"scope":        "ping:read ping:write",
+			}),
+			wantStatus: http.StatusOK,
+			wantPrincipal: &infrastructure.Principal{
+				Subject: "alice", Roles: []string{"admin", "user"}, Scopes: []string{"ping:read", "ping:write"},
+			},
+		},
+		{
+			name:          "Authenticate. Case #2. ES256 token",
+			authorization: "Bearer " + sign(jwt.SigningMethodES256, "ec", ecKey, nil),
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &infrastructure.Principal{Subject: "alice"},
+		},
+		{
+			name:       "Authenticate. Case #3. Token is absent",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "Authenticate. Case #4. Expired token",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Authenticate. Case #5. Bad issuer",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"iss": "https://evil.example.com"}),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Authenticate. Case #6. Bad audience",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"aud": "other-service"}),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Authenticate. Case #7. Unknown key",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "other", rsaKey, nil),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Authenticate. Case #8. Signed by other key",
+			authorization: "Bearer " + sign(jwt.SigningMethodES256, "rsa", ecKey, nil),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Authenticate. Case #9. HS256 isn't allowed",
+			authorization: "Bearer " + sign(jwt.SigningMethodHS256, "rsa", []byte("secret"), nil),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Authenticate. Case #10. Subject is absent",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"sub": nil}),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Authenticate. Case #11. Expiration is absent",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"exp": nil}),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name: "RequireRoles. Case #12. Caller has role",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{
+				"realm_access": map[string]interface{}{"roles": []string{"user"}},
+			}),
+			middlewares:   []echo.MiddlewareFunc{RequireRoles("admin", "user")},
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &infrastructure.Principal{Subject: "alice", Roles: []string{"user"}},
+		},
+		{
+			name:          "RequireRoles. Case #13. Caller hasn't role",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, nil),
+			middlewares:   []echo.MiddlewareFunc{RequireRoles("admin")},
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "RequireScopes. Case #14. Scope isn't granted",
+			authorization: "Bearer " + sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"scope": "ping:read"}),
+			middlewares:   []echo.MiddlewareFunc{RequireScopes("ping:read", "ping:write")},
+			wantStatus:    http.StatusForbidden,
+		},
+	}
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var (
+				gotPrincipal *infrastructure.Principal
+				gotUser      string
+			)
+			h := func(c echo.Context) error {
+				gotPrincipal, _ = infrastructure.PrincipalFromContext(c.Request().Context())
+				gotUser = infrastructure.UserFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}
+			for i := len(tt.middlewares) - 1; i >= 0; i-- {
+				h = tt.middlewares[i](h)
+			}
+			err := Authenticate(keys, cfg)(h)(c)
+			status := rec.Code
+			var he *echo.HTTPError
+			if errors.As(err, &he) {
+				status = he.Code
+			}
+			assert.Equal(t, tt.wantStatus, status)
+			if tt.wantPrincipal == nil {
+				assert.Nil(t, gotPrincipal)
+				return
+			}
+			require.NotNil(t, gotPrincipal)
+			assert.Equal(t, tt.wantPrincipal.Subject, gotPrincipal.Subject)
+			assert.Equal(t, tt.wantPrincipal.Roles, gotPrincipal.Roles)
+			assert.Equal(t, tt.wantPrincipal.Scopes, gotPrincipal.Scopes)
+			assert.Equal(t, tt.wantPrincipal.Subject, gotUser)
+		})
+	}
+}
+
+func TestRequireRoles_NotAuthenticated(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	err := RequireRoles("admin")(func(c echo.Context) error { return c.NoContent(http.StatusOK) })(c)
+	var he *echo.HTTPError
+	require.True(t, errors.As(err, &he))
+	assert.Equal(t, http.StatusUnauthorized, he.Code)
+}
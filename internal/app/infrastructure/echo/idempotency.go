@@ -0,0 +1,176 @@
+package mymiddleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	// HeaderIdempotencyKey - header with client generated key of the request
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// HeaderIdempotentReplayed - header of the stored response returned for the repeated request
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// defaultIdempotencyMaxBodySize - max body size of the request with Idempotency-Key if it isn't configured
+	defaultIdempotencyMaxBodySize = 1 << 20
+
+	// idempotencyStoreTimeout - timeout of storing the response. The request context may be already canceled by the client
+	idempotencyStoreTimeout = time.Second * 5
+)
+
+// IdempotencyConfig - struct for Idempotency-Key params
+type IdempotencyConfig struct {
+	// Enabled - store responses of requests with Idempotency-Key header
+	Enabled bool `env:"IDEMPOTENCY_ENABLED" yaml:"enabled"`
+
+	// TTL - stored response is returned for repeated requests during TTL
+	TTL time.Duration `yaml:"ttl"` //nolint:tagliatelle
+
+	// LockTimeout - the key of the request in progress is released after LockTimeout (e.g. if the instance crashed)
+	LockTimeout time.Duration `yaml:"lockTimeout"`
+
+	// MaxBodySize - max body size in bytes of the request with Idempotency-Key. Body is read into memory
+	// to compute the fingerprint, so requests with larger body are rejected with 413
+	MaxBodySize int64 `yaml:"maxBodySize"`
+}
+
+// responseRecorder - copies response body
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes the data to the connection and to the buffer
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Idempotency - middleware which makes POST, PUT, PATCH and DELETE requests with Idempotency-Key header idempotent.
+// Response of the first request is stored and returned for repeated requests with the same key and fingerprint
+// (method, URI and body). Concurrent requests with the key and requests with other fingerprint are rejected
+// with ErrIdempotencyKeyInUse and ErrIdempotencyKeyReused. Keys of requests failed with 5xx are released,
+// so they may be retried. Keys are scoped by tenant and subject of the request
+func Idempotency(store infrastructure.IdempotencyStore, cfg IdempotencyConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			key := req.Header.Get(HeaderIdempotencyKey)
+			if key == "" || !unsafeMethod(req.Method) {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, "Idempotency-Key is too long")
+			}
+			key = idempotencyStoreKey(req.Context(), key)
+
+			maxBodySize := cfg.MaxBodySize
+			if maxBodySize <= 0 {
+				maxBodySize = defaultIdempotencyMaxBodySize
+			}
+			body, err := io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, maxBodySize))
+			if err != nil {
+				// MaxBytesReader returns the error after maxBodySize bytes are read
+				if int64(len(body)) == maxBodySize {
+					return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "request body is too large").SetInternal(err)
+				}
+				return echo.NewHTTPError(http.StatusBadRequest, "can't read request body").SetInternal(err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			// lockID identifies this request, so its response isn't stored if the lock expired and was taken by a retry
+			lockID := infrastructure.GenerateID()
+			stored, err := store.Lock(req.Context(), key, lockID, fingerprint(req, body), cfg.LockTimeout)
+			if err != nil {
+				return err
+			}
+			if stored != nil {
+				return replay(c, stored)
+			}
+			return record(c, next, store, key, lockID, cfg.TTL)
+		}
+	}
+}
+
+// idempotencyStoreKey - returns the key of the request in the store. Keys of different tenants and subjects
+// aren't shared. Subject length isn't limited, so the scoped key is hashed into fixed length
+func idempotencyStoreKey(ctx context.Context, key string) string {
+	h := sha256.New()
+	if tenant, ok := infrastructure.TenantFromContext(ctx); ok {
+		fmt.Fprintf(h, "tenant:%d:%s|", len(tenant), tenant)
+	}
+	if principal, ok := infrastructure.PrincipalFromContext(ctx); ok {
+		fmt.Fprintf(h, "sub:%d:%s|", len(principal.Subject), principal.Subject)
+	}
+	h.Write([]byte(key))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// record - calls the handler and stores its response. The key is released if the response isn't stored
+func record(c echo.Context, next echo.HandlerFunc, store infrastructure.IdempotencyStore, key, lockID string, ttl time.Duration) error {
+	resp := c.Response()
+	recorder := &responseRecorder{ResponseWriter: resp.Writer}
+	resp.Writer = recorder
+	// the error is rendered here, so the response is stored as the client gets it
+	if err := next(c); err != nil {
+		c.Error(err)
+	}
+	resp.Writer = recorder.ResponseWriter
+
+	log := infrastructure.GetBaseLogger(c.Request().Context())
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), infrastructure.CtxKeyLogger{}, log), idempotencyStoreTimeout)
+	defer cancel()
+	if !resp.Committed || resp.Status >= http.StatusInternalServerError {
+		if err := store.Unlock(ctx, key, lockID); err != nil {
+			log.Warn().Err(err).Msg("can't release idempotency key")
+		}
+		return nil
+	}
+	response := infrastructure.IdempotentResponse{Status: resp.Status, Header: resp.Header().Clone(), Body: recorder.body.Bytes()}
+	if err := store.Complete(ctx, key, lockID, response, ttl); err != nil {
+		log.Warn().Err(err).Msg("can't store response of the request with idempotency key")
+	}
+	return nil
+}
+
+// replay - writes the stored response. Headers set by previous middlewares (e.g. request id) aren't replaced
+func replay(c echo.Context, stored *infrastructure.IdempotentResponse) error {
+	header := c.Response().Header()
+	for name, values := range stored.Header {
+		if _, ok := header[name]; !ok {
+			header[name] = values
+		}
+	}
+	header.Set(HeaderIdempotentReplayed, "true")
+	c.Response().WriteHeader(stored.Status)
+	_, err := c.Response().Write(stored.Body)
+	return err
+}
+
+// fingerprint - returns hash of method, URI and body of the request
+func fingerprint(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func unsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package mymiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/infrastructure"
+)
+
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	prints    map[string]string
+	locks     map[string]string
+	responses map[string]infrastructure.IdempotentResponse
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{
+		prints:    map[string]string{},
+		locks:     map[string]string{},
+		responses: map[string]infrastructure.IdempotentResponse{},
+	}
+}
+
+func (s *memoryIdempotencyStore) Lock(_ context.Context, key, lockID, fingerprint string, _ time.Duration) (*infrastructure.IdempotentResponse, error) {
+	// the same limit as idempotency_keys.key column
+	if len(key) > 512 {
+		return nil, errors.New("key is too long")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.prints[key]
+	switch {
+	case !ok:
+		s.prints[key] = fingerprint
+		s.locks[key] = lockID
+		return nil, nil
+	case stored != fingerprint:
+		return nil, infrastructure.ErrIdempotencyKeyReused
+	}
+	res, ok := s.responses[key]
+	if !ok {
+		return nil, infrastructure.ErrIdempotencyKeyInUse
+	}
+	return &res, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key, lockID string, response infrastructure.IdempotentResponse, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locks[key] != lockID {
+		return nil
+	}
+	s.responses[key] = response
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Unlock(_ context.Context, key, lockID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locks[key] != lockID {
+		return nil
+	}
+	delete(s.prints, key)
+	delete(s.locks, key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		method  string
+		key     string
+		body    string
+		subject string
+		tenant  string
+	}
+	type want struct {
+		status   int
+		body     string
+		replayed bool
+		err      error
+	}
+	tests := []struct {
+		name      string
+		requests  []request
+		fail      int
+		wantCalls int
+		want      []want
+	}{
+		{
+			name:      "Idempotency. Case #1. Response is replayed",
+			requests:  []request{{key: "k1", body: "a"}, {key: "k1", body: "a"}},
+			wantCalls: 1,
+			want:      []want{{status: http.StatusCreated, body: "1"}, {status: http.StatusCreated, body: "1", replayed: true}},
+		},
+		{
+			name:      "Idempotency. Case #2. Key is used by other request",
+			requests:  []request{{key: "k1", body: "a"}, {key: "k1", body: "b"}},
+			wantCalls: 1,
+			want:      []want{{status: http.StatusCreated, body: "1"}, {err: infrastructure.ErrIdempotencyKeyReused}},
+		},
+		{
+			name:      "Idempotency. Case #3. Requests without key and safe requests aren't stored",
+			requests:  []request{{body: "a"}, {body: "a"}, {method: http.MethodGet, key: "k1"}, {method: http.MethodGet, key: "k1"}},
+			wantCalls: 4,
+			want: []want{
+				{status: http.StatusCreated, body: "1"}, {status: http.StatusCreated, body: "2"},
+				{status: http.StatusCreated, body: "3"}, {status: http.StatusCreated, body: "4"},
+			},
+		},
+		{
+			name:      "Idempotency. Case #4. Failed request may be retried",
+			requests:  []request{{key: "k1", body: "a"}, {key: "k1", body: "a"}, {key: "k1", body: "a"}},
+			fail:      1,
+			wantCalls: 2,
+			want: []want{
+				{status: http.StatusInternalServerError}, {status: http.StatusCreated, body: "2"},
+				{status: http.StatusCreated, body: "2", replayed: true},
+			},
+		},
+		{
+			name:      "Idempotency. Case #5. Keys are scoped by subject",
+			requests:  []request{{key: "k1", body: "a", subject: "alice"}, {key: "k1", body: "a", subject: "bob"}},
+			wantCalls: 2,
+			want:      []want{{status: http.StatusCreated, body: "1"}, {status: http.StatusCreated, body: "2"}},
+		},
+		{
+			name:     "Idempotency. Case #6. Key is too long",
+			requests: []request{{key: strings.Repeat("k", 256), body: "a"}},
+			want:     []want{{err: echo.NewHTTPError(http.StatusBadRequest)}},
+		},
+		{
+			name:      "Idempotency. Case #7. Body is too large",
+			requests:  []request{{key: "k1", body: strings.Repeat("a", 17)}, {key: "k1", body: strings.Repeat("a", 16)}},
+			wantCalls: 1,
+			want:      []want{{err: echo.NewHTTPError(http.StatusRequestEntityTooLarge)}, {status: http.StatusCreated, body: "1"}},
+		},
+		{
+			name: "Idempotency. Case #8. Keys are scoped by tenant",
+			requests: []request{
+				{key: "k1", body: "a", subject: "alice", tenant: "t1"}, {key: "k1", body: "a", subject: "alice", tenant: "t2"},
+				{key: "k1", body: "a", subject: "alice", tenant: "t1"},
+			},
+			wantCalls: 2,
+			want: []want{
+				{status: http.StatusCreated, body: "1"}, {status: http.StatusCreated, body: "2"},
+				{status: http.StatusCreated, body: "1", replayed: true},
+			},
+		},
+		{
+			name: "Idempotency. Case #9. Long subject",
+			requests: []request{
+				{key: "k1", body: "a", subject: strings.Repeat("s", 1000)}, {key: "k1", body: "a", subject: strings.Repeat("s", 1000)},
+				{key: "s|k1", body: "a", subject: strings.Repeat("s", 999)},
+			},
+			wantCalls: 2,
+			want: []want{
+				{status: http.StatusCreated, body: "1"}, {status: http.StatusCreated, body: "1", replayed: true},
+				{status: http.StatusCreated, body: "2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			calls := 0
+			h := Idempotency(newMemoryIdempotencyStore(), IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute, MaxBodySize: 16})(
+				func(c echo.Context) error {
+					calls++
+					if calls <= tt.fail {
+						return errors.New("db is down")
+					}
+					c.Response().Header().Set("Location", "/items/1")
+					return c.String(http.StatusCreated, string(rune('0'+calls)))
+				})
+
+			for i, r := range tt.requests {
+				if r.method == "" {
+					r.method = http.MethodPost
+				}
+				req := httptest.NewRequest(r.method, "/items", strings.NewReader(r.body))
+				if r.key != "" {
+					req.Header.Set(HeaderIdempotencyKey, r.key)
+				}
+				if r.subject != "" {
+					req = req.WithContext(infrastructure.WithPrincipal(req.Context(), &infrastructure.Principal{Subject: r.subject}))
+				}
+				if r.tenant != "" {
+					req = req.WithContext(infrastructure.WithTenant(req.Context(), r.tenant))
+				}
+				rec := httptest.NewRecorder()
+				err := h(e.NewContext(req, rec))
+
+				w := tt.want[i]
+				var he *echo.HTTPError
+				switch {
+				case errors.As(w.err, &he):
+					var got *echo.HTTPError
+					if assert.ErrorAs(t, err, &got, "request #%d", i+1) {
+						assert.Equal(t, he.Code, got.Code)
+					}
+					continue
+				case w.err != nil:
+					assert.ErrorIs(t, err, w.err, "request #%d", i+1)
+					continue
+				}
+				assert.NoError(t, err, "request #%d", i+1)
+				assert.Equal(t, w.status, rec.Code, "request #%d", i+1)
+				if w.body != "" {
+					assert.Equal(t, w.body, rec.Body.String(), "request #%d", i+1)
+					assert.Equal(t, "/items/1", rec.Header().Get("Location"), "request #%d", i+1)
+				}
+				assert.Equal(t, w.replayed, rec.Header().Get(HeaderIdempotentReplayed) == "true", "request #%d", i+1)
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
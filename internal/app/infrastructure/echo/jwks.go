@@ -0,0 +1,301 @@
+package mymiddleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	defaultJWKSCacheTTL = time.Minute * 10
+
+	// jwksMinRefreshInterval - keys aren't reloaded more often on unknown key id, so tokens with random kid
+	// don't flood the identity provider
+	jwksMinRefreshInterval = time.Second * 10
+
+	jwksRequestTimeout = time.Second * 5
+)
+
+var (
+	// ErrNoJWKSSource - "JWKS file or URL isn't set" error
+	ErrNoJWKSSource = errors.New("JWKS file or URL isn't set")
+
+	// ErrBadJWKS - "bad JWKS" error
+	ErrBadJWKS = errors.New("bad JWKS")
+
+	// ErrUnknownKey - "signing key isn't found" error
+	ErrUnknownKey = errors.New("signing key isn't found")
+)
+
+// KeyProvider - source of keys for token signature verification
+type KeyProvider interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// JWKS - keys from local JWKS file or URL. Keys are cached for CacheTTL and reloaded earlier
+// if a token is signed by an unknown key (keys rotation). Keys are loaded without lock:
+// requests read the current key set, concurrent reloads are merged into one
+type JWKS struct {
+	infrastructure.SugarLogger
+	file   string
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	// set - current *jwkSet
+	set atomic.Value
+
+	mu      sync.Mutex
+	loading chan struct{}
+}
+
+// jwkSet - loaded keys. loadTime is the time of the last load attempt, keys are kept if it failed
+type jwkSet struct {
+	keys     map[string]crypto.PublicKey
+	loadTime time.Time
+}
+
+// NewJWKS returns new JWKS. Keys from file must be valid on start. Keys from URL are loaded on start
+// if the identity provider is available, otherwise on the first request
+func NewJWKS(ctx context.Context, cfg AuthConfig) (*JWKS, error) {
+	var target JWKS
+	target.file = cfg.JWKSFile
+	target.url = cfg.JWKSURL
+	target.ttl = cfg.CacheTTL
+
+	if err := target.Init(ctx); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Init - func for initialisation
+func (j *JWKS) Init(ctx context.Context) error {
+	if j.file == "" && j.url == "" {
+		return ErrNoJWKSSource
+	}
+	if j.ttl <= 0 {
+		j.ttl = defaultJWKSCacheTTL
+	}
+	j.client = &http.Client{Timeout: jwksRequestTimeout}
+	j.set.Store(&jwkSet{})
+
+	err := j.load(ctx)
+	if err != nil && j.file != "" {
+		return err
+	}
+	if err != nil {
+		j.Log(ctx).Warn().Err(err).Str("url", j.url).Msg("JWKS isn't loaded, it will be loaded on the first request")
+	}
+	return nil
+}
+
+// Key - returns the key by key id. If kid is empty and JWKS contains the only key, it is returned.
+// Expired keys are returned while they are reloaded in background. Request with unknown kid waits for reload
+func (j *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	set := j.current()
+	key, found := set.lookup(kid)
+	since := time.Since(set.loadTime)
+	switch {
+	case found && since > j.ttl:
+		j.reload(ctx)
+	case !found && since > jwksMinRefreshInterval:
+		select {
+		case <-j.reload(ctx):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		key, found = j.current().lookup(kid)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+func (j *JWKS) current() *jwkSet {
+	return j.set.Load().(*jwkSet)
+}
+
+func (s *jwkSet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// reload - starts loading of keys in background if it isn't in progress.
+// Returned channel is closed when keys are loaded
+func (j *JWKS) reload(ctx context.Context) <-chan struct{} {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.loading != nil {
+		return j.loading
+	}
+	done := make(chan struct{})
+	// keys were reloaded while the caller checked them
+	if time.Since(j.current().loadTime) <= jwksMinRefreshInterval {
+		close(done)
+		return done
+	}
+	j.loading = done
+	l := j.Log(ctx)
+	go func() {
+		// the load is shared by requests, so it isn't canceled with the request
+		ctx := context.WithValue(context.Background(), infrastructure.CtxKeyLogger{}, l)
+		// stale keys are used if the source is unavailable
+		if err := j.load(ctx); err != nil {
+			l.Warn().Err(err).Msg("can't reload JWKS")
+		}
+		j.mu.Lock()
+		j.loading = nil
+		j.mu.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// load - reads keys from file or URL and replaces the current key set
+func (j *JWKS) load(ctx context.Context) error {
+	set := &jwkSet{keys: j.current().keys, loadTime: time.Now()}
+	defer j.set.Store(set)
+	var (
+		data []byte
+		err  error
+	)
+	if j.file != "" {
+		data, err = os.ReadFile(j.file)
+	} else {
+		data, err = j.fetch(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("can't read JWKS: %w", err)
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+	set.keys = keys
+	j.Log(ctx).Debug().Int("keys", len(keys)).Msg("JWKS loaded")
+	return nil
+}
+
+func (j *JWKS) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS - parses RSA and EC signature keys of JWK set. Keys of other types and encryption keys are skipped
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadJWKS, err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = rsaKey(k)
+		case "EC":
+			key, err = ecKey(k)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %s", ErrBadJWKS, k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: there are no signature keys", ErrBadJWKS)
+	}
+	return keys, nil
+}
+
+func rsaKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("bad modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("bad exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
+
+func ecKey(k jwk) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("bad x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("bad y: %w", err)
+	}
+	key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("point isn't on the curve")
+	}
+	return key, nil
+}
@@ -0,0 +1,168 @@
+package mymiddleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJWKS - returns JWKS with public parts of the keys
+func testJWKS(t *testing.T, keys map[string]interface{}) []byte {
+	t.Helper()
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+				"n": encode(k.N.Bytes()), "e": encode(big.NewInt(int64(k.E)).Bytes()),
+			})
+		case *ecdsa.PrivateKey:
+			size := (k.Curve.Params().BitSize + 7) / 8
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "EC", "kid": kid, "use": "sig", "alg": "ES256", "crv": k.Curve.Params().Name,
+				"x": encode(k.X.FillBytes(make([]byte, size))), "y": encode(k.Y.FillBytes(make([]byte, size))),
+			})
+		}
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return data
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keys, err := ParseJWKS(testJWKS(t, map[string]interface{}{"rsa": rsaKey, "ec": ecKey}))
+	require.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, keys["rsa"])
+	assert.True(t, ecKey.PublicKey.Equal(keys["ec"]))
+
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"1","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	assert.ErrorIs(t, err, ErrBadJWKS)
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"oct","kid":"1","k":"c2VjcmV0"}]}`))
+	assert.ErrorIs(t, err, ErrBadJWKS)
+}
+
+func TestJWKS_Key(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	t.Run("JWKS.Key Case#1 Keys from file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(file, testJWKS(t, map[string]interface{}{"old": oldKey}), 0o600))
+		jwks, err := NewJWKS(ctx, AuthConfig{JWKSFile: file})
+		require.NoError(t, err)
+
+		key, err := jwks.Key(ctx, "old")
+		assert.NoError(t, err)
+		assert.Equal(t, &oldKey.PublicKey, key)
+		key, err = jwks.Key(ctx, "")
+		assert.NoError(t, err)
+		assert.Equal(t, &oldKey.PublicKey, key)
+		_, err = jwks.Key(ctx, "new")
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+	t.Run("JWKS.Key Case#2 Bad file", func(t *testing.T) {
+		_, err := NewJWKS(ctx, AuthConfig{JWKSFile: filepath.Join(t.TempDir(), "absent.json")})
+		assert.Error(t, err)
+		_, err = NewJWKS(ctx, AuthConfig{})
+		assert.ErrorIs(t, err, ErrNoJWKSSource)
+	})
+	t.Run("JWKS.Key Case#3 Keys from URL are cached and reloaded on rotation", func(t *testing.T) {
+		var requests int32
+		var body atomic.Value
+		body.Store(testJWKS(t, map[string]interface{}{"old": oldKey}))
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			_, _ = w.Write(body.Load().([]byte))
+		}))
+		defer srv.Close()
+		jwks, err := NewJWKS(ctx, AuthConfig{JWKSURL: srv.URL})
+		require.NoError(t, err)
+
+		_, err = jwks.Key(ctx, "old")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+		// unknown key doesn't cause reload until min refresh interval passes
+		body.Store(testJWKS(t, map[string]interface{}{"old": oldKey, "new": newKey}))
+		_, err = jwks.Key(ctx, "new")
+		assert.ErrorIs(t, err, ErrUnknownKey)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+		expireJWKS(jwks, jwksMinRefreshInterval)
+		key, err := jwks.Key(ctx, "new")
+		assert.NoError(t, err)
+		assert.Equal(t, &newKey.PublicKey, key)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+	t.Run("JWKS.Key Case#4 Expired keys are returned while reloaded", func(t *testing.T) {
+		var requests int32
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) > 1 {
+				<-release
+			}
+			_, _ = w.Write(testJWKS(t, map[string]interface{}{"old": oldKey}))
+		}))
+		defer srv.Close()
+		defer close(release)
+		jwks, err := NewJWKS(ctx, AuthConfig{JWKSURL: srv.URL})
+		require.NoError(t, err)
+
+		expireJWKS(jwks, defaultJWKSCacheTTL)
+		for i := 0; i < 3; i++ {
+			key, err := jwks.Key(ctx, "old")
+			assert.NoError(t, err)
+			assert.Equal(t, &oldKey.PublicKey, key)
+		}
+		// unknown kid waits for the reload in progress, it isn't loaded again
+		waitCtx, cancel := context.WithTimeout(ctx, time.Millisecond*50)
+		defer cancel()
+		_, err = jwks.Key(waitCtx, "random")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+	t.Run("JWKS.Key Case#5 Identity provider is unavailable on start", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+		jwks, err := NewJWKS(ctx, AuthConfig{JWKSURL: srv.URL})
+		require.NoError(t, err)
+		_, err = jwks.Key(ctx, "old")
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
+
+// expireJWKS - moves load time of the keys back
+func expireJWKS(j *JWKS, d time.Duration) {
+	set := *j.current()
+	set.loadTime = set.loadTime.Add(-d - time.Millisecond)
+	j.set.Store(&set)
+}
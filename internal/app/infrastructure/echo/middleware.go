@@ -40,7 +40,7 @@ func RequestLogger(next echo.HandlerFunc) echo.HandlerFunc {
 // PrepareLogger - middleware for logger configuration. Adds requestID, correlationID fields
 func PrepareLogger(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		lc := infrastructure.GetBaseLogger(context.Background()).With()
+		lc := infrastructure.GetComponentLogger(infrastructure.ComponentHTTP).With()
 		requestID, ok := c.Request().Context().Value(infrastructure.CtxKeyRequestID{}).(string)
 		if !ok {
 			requestID = ""
@@ -0,0 +1,207 @@
+package mymiddleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	// RateLimitByIP - requests are limited by client IP
+	RateLimitByIP = "ip"
+
+	// RateLimitByAPIKey - requests are limited by API key header. Requests without key are limited by IP
+	RateLimitByAPIKey = "apiKey"
+
+	// RateLimitBySubject - requests are limited by JWT subject (see Authenticate). Anonymous requests are limited by IP
+	RateLimitBySubject = "subject"
+
+	// RateLimitStoreMemory - limits of the instance
+	RateLimitStoreMemory = "memory"
+
+	// RateLimitStorePostgres - limits shared by all instances
+	RateLimitStorePostgres = "postgres"
+
+	defaultAPIKeyHeader = "X-Api-Key"
+
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+)
+
+// RateLimitConfig - struct for rate limiting params
+type RateLimitConfig struct {
+	// Enabled - limit rate of API requests
+	Enabled bool `env:"RATE_LIMIT_ENABLED" yaml:"enabled"`
+
+	// KeyBy - client identification: ip, apiKey or subject
+	KeyBy string `yaml:"keyBy" validate:"omitempty,oneof=ip apiKey subject"`
+
+	// APIKeyHeader - header with API key
+	APIKeyHeader string `yaml:"apiKeyHeader"`
+
+	// Rate - requests per second of one client
+	Rate float64 `yaml:"rate"`
+
+	// Burst - max count of requests of one client at once
+	Burst int `yaml:"burst"`
+
+	// Store - storage of limits: memory or postgres
+	Store string `env:"RATE_LIMIT_STORE" yaml:"store" validate:"omitempty,oneof=memory postgres"`
+
+	// Routes - limits of routes. Routes have own buckets
+	Routes []RouteRateLimit `yaml:"routes"`
+
+	// IPRate - requests per second of one IP before authentication. It is applied if clients are limited by subject,
+	// so floods of bad tokens don't reach token verification. 0 disables the limit
+	IPRate float64 `yaml:"ipRate"`
+
+	// IPBurst - max count of requests of one IP at once before authentication
+	IPBurst int `yaml:"ipBurst"`
+}
+
+// RouteRateLimit - limit of the route
+type RouteRateLimit struct {
+	// Route - "METHOD path" or "path" for all methods. Path is the route pattern, e.g. /api/v1/users/:id
+	Route string `yaml:"route"`
+
+	// Rate - requests per second of one client
+	Rate float64 `yaml:"rate"`
+
+	// Burst - max count of requests of one client at once
+	Burst int `yaml:"burst"`
+}
+
+// RateLimiter - middleware which limits requests of clients by token buckets. RateLimit-* headers are added
+// to responses. Request over limit is rejected with 429 and Retry-After header. If the store is unavailable,
+// requests are allowed
+func RateLimiter(store infrastructure.RateLimitStore, cfg RateLimitConfig) echo.MiddlewareFunc {
+	if cfg.APIKeyHeader == "" {
+		cfg.APIKeyHeader = defaultAPIKeyHeader
+	}
+	defaultLimit := infrastructure.RateLimit{Rate: cfg.Rate, Burst: cfg.Burst}
+	routes := make(map[string]infrastructure.RateLimit, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		routes[r.Route] = infrastructure.RateLimit{Rate: r.Rate, Burst: r.Burst}
+	}
+	clientKey := rateLimitKey(cfg)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			limit, route := defaultLimit, "*"
+			for _, name := range []string{c.Request().Method + " " + c.Path(), c.Path()} {
+				if l, ok := routes[name]; ok {
+					limit, route = l, name
+					break
+				}
+			}
+			if limit.Rate <= 0 || limit.Burst <= 0 {
+				return next(c)
+			}
+
+			if err := limitRequest(c, store, clientKey(c)+"|"+route, limit); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// PreAuthRateLimiter - middleware which limits requests of IP by IPRate and IPBurst. It is called before
+// Authenticate, so requests with bad tokens are limited too. Buckets aren't shared with RateLimiter.
+// If IPRate or IPBurst <= 0, requests aren't limited
+func PreAuthRateLimiter(store infrastructure.RateLimitStore, cfg RateLimitConfig) echo.MiddlewareFunc {
+	limit := infrastructure.RateLimit{Rate: cfg.IPRate, Burst: cfg.IPBurst}
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := limitRequest(c, store, "auth:ip:"+c.RealIP(), limit); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// limitRequest - takes a token from the bucket of the key and sets RateLimit-* headers. Error with 429 is returned
+// if the bucket is empty. If the store is unavailable, the request is allowed
+func limitRequest(c echo.Context, store infrastructure.RateLimitStore, key string, limit infrastructure.RateLimit) error {
+	ctx := c.Request().Context()
+	res, err := store.Take(ctx, key, limit)
+	if err != nil {
+		infrastructure.GetBaseLogger(ctx).Warn().Err(err).Msg("can't check rate limit, request is allowed")
+		return nil
+	}
+	header := c.Response().Header()
+	header.Set(headerRateLimitLimit, strconv.Itoa(limit.Burst))
+	header.Set(headerRateLimitRemaining, strconv.Itoa(res.Remaining))
+	header.Set(headerRateLimitReset, seconds(res.Reset))
+	if !res.Allowed {
+		header.Set(echo.HeaderRetryAfter, seconds(res.RetryAfter))
+		return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+	}
+	return nil
+}
+
+// ConcurrencyLimiter - middleware which limits count of requests processed at once.
+// Request over limit is rejected with 503 and Retry-After header. If maxInFlight <= 0, requests aren't limited
+func ConcurrencyLimiter(maxInFlight int) echo.MiddlewareFunc {
+	if maxInFlight <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+	// echo applies group middlewares on each request, so the semaphore is shared by all wrapped handlers
+	slots := make(chan struct{}, maxInFlight)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			select {
+			case slots <- struct{}{}:
+			default:
+				c.Response().Header().Set(echo.HeaderRetryAfter, "1")
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "too many requests in progress")
+			}
+			defer func() { <-slots }()
+			return next(c)
+		}
+	}
+}
+
+// rateLimitKey - returns func which identifies the client. API keys are hashed, so they aren't stored
+func rateLimitKey(cfg RateLimitConfig) func(c echo.Context) string {
+	byIP := func(c echo.Context) string {
+		return "ip:" + c.RealIP()
+	}
+	switch cfg.KeyBy {
+	case RateLimitByAPIKey:
+		return func(c echo.Context) string {
+			key := c.Request().Header.Get(cfg.APIKeyHeader)
+			if key == "" {
+				return byIP(c)
+			}
+			hash := sha256.Sum256([]byte(key))
+			return "key:" + hex.EncodeToString(hash[:16])
+		}
+	case RateLimitBySubject:
+		return func(c echo.Context) string {
+			if principal, ok := infrastructure.PrincipalFromContext(c.Request().Context()); ok {
+				return "sub:" + strings.ToLower(principal.Subject)
+			}
+			return byIP(c)
+		}
+	default:
+		return byIP
+	}
+}
+
+// seconds - returns duration in whole seconds rounded up
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,235 @@
+package mymiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/infrastructure"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, infrastructure.RateLimit) (infrastructure.RateLimitResult, error) {
+	return infrastructure.RateLimitResult{}, errors.New("connection refused")
+}
+
+func TestRateLimiter(t *testing.T) {
+	type request struct {
+		method  string
+		path    string
+		ip      string
+		apiKey  string
+		subject string
+	}
+	tests := []struct {
+		name       string
+		cfg        RateLimitConfig
+		store      infrastructure.RateLimitStore
+		requests   []request
+		wantStatus []int
+		wantRetry  string
+	}{
+		{
+			name:       "RateLimiter. Case #1. Burst is exceeded",
+			cfg:        RateLimitConfig{Rate: 0.001, Burst: 2},
+			requests:   []request{{ip: "10.0.0.1"}, {ip: "10.0.0.1"}, {ip: "10.0.0.1"}},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantRetry:  "1000",
+		},
+		{
+			name:       "RateLimiter. Case #2. Clients have own buckets",
+			cfg:        RateLimitConfig{Rate: 0.001, Burst: 1},
+			requests:   []request{{ip: "10.0.0.1"}, {ip: "10.0.0.2"}, {ip: "10.0.0.1"}},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:       "RateLimiter. Case #3. Limit by API key",
+			cfg:        RateLimitConfig{KeyBy: RateLimitByAPIKey, Rate: 0.001, Burst: 1},
+			requests:   []request{{ip: "10.0.0.1", apiKey: "a"}, {ip: "10.0.0.1", apiKey: "b"}, {ip: "10.0.0.2", apiKey: "a"}},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:       "RateLimiter. Case #4. Limit by subject",
+			cfg:        RateLimitConfig{KeyBy: RateLimitBySubject, Rate: 0.001, Burst: 1},
+			requests:   []request{{ip: "10.0.0.1", subject: "alice"}, {ip: "10.0.0.1", subject: "bob"}, {ip: "10.0.0.2", subject: "alice"}},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "RateLimiter. Case #5. Route has own limit and bucket",
+			cfg: RateLimitConfig{Rate: 0.001, Burst: 1, Routes: []RouteRateLimit{
+				{Route: "POST /items", Rate: 0.001, Burst: 2},
+				{Route: "/items/:id", Rate: 0, Burst: 0},
+			}},
+			requests: []request{
+				{method: http.MethodGet, path: "/items"},
+				{method: http.MethodPost, path: "/items"},
+				{method: http.MethodPost, path: "/items"},
+				{method: http.MethodPost, path: "/items"},
+				{method: http.MethodGet, path: "/items/1"},
+				{method: http.MethodGet, path: "/items/1"},
+			},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:       "RateLimiter. Case #6. Store is unavailable",
+			cfg:        RateLimitConfig{Rate: 0.001, Burst: 1},
+			store:      failingRateLimitStore{},
+			requests:   []request{{ip: "10.0.0.1"}, {ip: "10.0.0.1"}},
+			wantStatus: []int{http.StatusOK, http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := tt.store
+			if store == nil {
+				store = infrastructure.NewMemoryRateLimitStore()
+			}
+			e := echo.New()
+			e.Use(RateLimiter(store, tt.cfg))
+			ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+			e.GET("/items", ok)
+			e.POST("/items", ok)
+			e.GET("/items/:id", ok)
+
+			for i, r := range tt.requests {
+				if r.method == "" {
+					r.method, r.path = http.MethodGet, "/items"
+				}
+				req := httptest.NewRequest(r.method, r.path, nil)
+				if r.ip != "" {
+					req.Header.Set(echo.HeaderXRealIP, r.ip)
+				}
+				if r.apiKey != "" {
+					req.Header.Set(defaultAPIKeyHeader, r.apiKey)
+				}
+				if r.subject != "" {
+					req = req.WithContext(infrastructure.WithPrincipal(req.Context(), &infrastructure.Principal{Subject: r.subject}))
+				}
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				assert.Equal(t, tt.wantStatus[i], rec.Code, "request #%d", i+1)
+				if rec.Code == http.StatusTooManyRequests {
+					assert.Equal(t, "0", rec.Header().Get(headerRateLimitRemaining))
+					if tt.wantRetry != "" {
+						assert.Equal(t, tt.wantRetry, rec.Header().Get(echo.HeaderRetryAfter))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPreAuthRateLimiter(t *testing.T) {
+	cfg := RateLimitConfig{KeyBy: RateLimitBySubject, Rate: 0.001, Burst: 1, IPRate: 0.001, IPBurst: 2}
+	store := infrastructure.NewMemoryRateLimitStore()
+	e := echo.New()
+	e.Use(PreAuthRateLimiter(store, cfg))
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(echo.HeaderAuthorization) != "Bearer good" {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+			req := c.Request()
+			c.SetRequest(req.WithContext(infrastructure.WithPrincipal(req.Context(), &infrastructure.Principal{Subject: "user"})))
+			return next(c)
+		}
+	})
+	e.Use(RateLimiter(store, cfg))
+	e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	serve := func(ip, token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set(echo.HeaderXRealIP, ip)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("10.0.0.1", "good"), "PreAuthRateLimiter. Case #1. Request is allowed")
+	assert.Equal(t, http.StatusUnauthorized, serve("10.0.0.1", "bad"), "PreAuthRateLimiter. Case #2. Bad token is verified")
+	assert.Equal(t, http.StatusTooManyRequests, serve("10.0.0.1", "bad"),
+		"PreAuthRateLimiter. Case #3. Bad tokens are limited before authentication")
+	assert.Equal(t, http.StatusTooManyRequests, serve("10.0.0.2", "good"),
+		"PreAuthRateLimiter. Case #4. Subject is limited after authentication")
+	assert.Equal(t, http.StatusUnauthorized, serve("10.0.0.3", "bad"), "PreAuthRateLimiter. Case #5. IPs have own buckets")
+
+	disabled := PreAuthRateLimiter(store, RateLimitConfig{KeyBy: RateLimitBySubject})(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, disabled(e.NewContext(httptest.NewRequest(http.MethodGet, "/items", nil), httptest.NewRecorder())),
+			"PreAuthRateLimiter. Case #6. Requests aren't limited without IP limit")
+	}
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	e := echo.New()
+	started, release := make(chan struct{}), make(chan struct{})
+	h := ConcurrencyLimiter(1)(func(c echo.Context) error {
+		close(started)
+		<-release
+		return c.NoContent(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		assert.NoError(t, h(c))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	err := h(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec))
+	var he *echo.HTTPError
+	if assert.True(t, errors.As(err, &he), "ConcurrencyLimiter. Request over limit is rejected") {
+		assert.Equal(t, http.StatusServiceUnavailable, he.Code)
+		assert.Equal(t, "1", rec.Header().Get(echo.HeaderRetryAfter))
+	}
+	close(release)
+	wg.Wait()
+
+	unlimited := ConcurrencyLimiter(0)(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	assert.NoError(t, unlimited(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())),
+		"ConcurrencyLimiter. Requests aren't limited")
+}
+
+func TestConcurrencyLimiter_Group(t *testing.T) {
+	e := echo.New()
+	started, release := make(chan struct{}), make(chan struct{})
+	g := e.Group("/api", ConcurrencyLimiter(1))
+	g.GET("/slow", func(c echo.Context) error {
+		close(started)
+		<-release
+		return c.NoContent(http.StatusOK)
+	})
+	g.GET("/fast", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	slow := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.ServeHTTP(slow, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/fast", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "ConcurrencyLimiter. Group routes share the limit")
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusOK, slow.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/fast", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "ConcurrencyLimiter. Slot is released")
+}
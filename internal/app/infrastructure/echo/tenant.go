@@ -0,0 +1,76 @@
+package mymiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// ErrTenantConflict - "tenant of the header differs from tenant of the token" error
+var ErrTenantConflict = errors.New("tenant of the header differs from tenant of the token")
+
+// TenantResolver - func type for resolving tenant from request. Empty string is returned if request doesn't contain tenant.
+// Error is returned if the request contains tenant the client isn't allowed to use
+type TenantResolver func(c echo.Context) (string, error)
+
+// TenantFromHeader - resolves tenant from request header
+func TenantFromHeader(header string) TenantResolver {
+	return func(c echo.Context) (string, error) {
+		return c.Request().Header.Get(header), nil
+	}
+}
+
+// TenantFromJWTClaim - resolves tenant from the claim of the principal, so the resolver must be used after
+// authentication middleware (see Authenticate). Claims of unauthenticated requests aren't trusted.
+// The header can't override tenant of the token: ErrTenantConflict is returned if it differs from the claim
+func TenantFromJWTClaim(claim, header string) TenantResolver {
+	return func(c echo.Context) (string, error) {
+		var tenant string
+		if principal, ok := infrastructure.PrincipalFromContext(c.Request().Context()); ok {
+			tenant, _ = principal.Claims[claim].(string)
+		}
+		if h := c.Request().Header.Get(header); h != "" && h != tenant {
+			return "", ErrTenantConflict
+		}
+		return tenant, nil
+	}
+}
+
+// PrepareTenant - middleware which adds tenant into context. Resolvers are applied in the given order, the first
+// non-empty value is used. If tenant isn't found and required is true, request is rejected with 400.
+// If resolver returns error, request is rejected with 403
+func PrepareTenant(required bool, resolvers ...TenantResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var (
+				tenant string
+				err    error
+			)
+			for _, resolve := range resolvers {
+				if tenant, err = resolve(c); err != nil {
+					return echo.NewHTTPError(http.StatusForbidden, err.Error())
+				}
+				if tenant != "" {
+					break
+				}
+			}
+			if tenant == "" {
+				if required {
+					return echo.NewHTTPError(http.StatusBadRequest, "tenant is not set")
+				}
+				return next(c)
+			}
+			if err := infrastructure.ValidateTenant(tenant); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			ctx := infrastructure.WithTenant(c.Request().Context(), tenant)
+			l := infrastructure.GetBaseLogger(ctx).With().Str(infrastructure.TenantField, tenant).Logger()
+			ctx = context.WithValue(ctx, infrastructure.CtxKeyLogger{}, &l)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package mymiddleware
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/infrastructure"
+)
+
+func TestPrepareTenant(t *testing.T) {
+	unsigned := "header." + base64.RawURLEncoding.EncodeToString([]byte(`{"tenant":"t2"}`)) + ".signature"
+	principal := &infrastructure.Principal{Subject: "alice", Claims: map[string]interface{}{"tenant": "t2"}}
+	tests := []struct {
+		name       string
+		required   bool
+		jwtClaim   string
+		principal  *infrastructure.Principal
+		headers    map[string]string
+		wantStatus int
+		wantTenant string
+	}{
+		{
+			name:       "PrepareTenant. Case #1. Tenant from header",
+			headers:    map[string]string{infrastructure.TenantHeader: "t1"},
+			wantStatus: http.StatusOK,
+			wantTenant: "t1",
+		},
+		{
+			name:       "PrepareTenant. Case #2. Tenant from JWT claim",
+			jwtClaim:   "tenant",
+			principal:  principal,
+			wantStatus: http.StatusOK,
+			wantTenant: "t2",
+		},
+		{
+			name:       "PrepareTenant. Case #3. Header can't override JWT claim",
+			jwtClaim:   "tenant",
+			principal:  principal,
+			headers:    map[string]string{infrastructure.TenantHeader: "t1"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "PrepareTenant. Case #4. Header repeats JWT claim",
+			jwtClaim:   "tenant",
+			principal:  principal,
+			headers:    map[string]string{infrastructure.TenantHeader: "t2"},
+			wantStatus: http.StatusOK,
+			wantTenant: "t2",
+		},
+		{
+			name:       "PrepareTenant. Case #5. Header is rejected if token has no claim",
+			jwtClaim:   "tenant",
+			headers:    map[string]string{infrastructure.TenantHeader: "t1"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "PrepareTenant. Case #6. Claim of unverified token is ignored",
+			jwtClaim:   "tenant",
+			required:   true,
+			headers:    map[string]string{echo.HeaderAuthorization: "Bearer " + unsigned},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "PrepareTenant. Case #7. Tenant isn't required",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "PrepareTenant. Case #8. Tenant is required",
+			required:   true,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "PrepareTenant. Case #9. Bad tenant",
+			headers:    map[string]string{infrastructure.TenantHeader: "t1;drop"},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if tt.principal != nil {
+				req = req.WithContext(infrastructure.WithPrincipal(req.Context(), tt.principal))
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var gotTenant string
+			resolver := TenantFromHeader(infrastructure.TenantHeader)
+			if tt.jwtClaim != "" {
+				resolver = TenantFromJWTClaim(tt.jwtClaim, infrastructure.TenantHeader)
+			}
+			h := PrepareTenant(tt.required, resolver)(func(c echo.Context) error {
+				gotTenant, _ = infrastructure.TenantFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+			err := h(c)
+			status := rec.Code
+			var he *echo.HTTPError
+			if errors.As(err, &he) {
+				status = he.Code
+			}
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantTenant, gotTenant)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyInUse - request with the same idempotency key is in progress
+	ErrIdempotencyKeyInUse = errors.New("request with the idempotency key is in progress")
+
+	// ErrIdempotencyKeyReused - idempotency key was used by other request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used by other request")
+)
+
+// IdempotentResponse - stored response of the request with idempotency key
+type IdempotentResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore - storage of requests with idempotency keys
+type IdempotencyStore interface {
+	// Lock - reserves the key for the request lockID with the fingerprint for lockTimeout.
+	// It returns stored response if the request was completed, ErrIdempotencyKeyInUse if it is in progress
+	// and ErrIdempotencyKeyReused if the key was used by the request with other fingerprint
+	Lock(ctx context.Context, key, lockID, fingerprint string, lockTimeout time.Duration) (*IdempotentResponse, error)
+
+	// Complete - stores response of the request. It is returned for the key for ttl.
+	// Nothing is stored if the key is held by other request (e.g. the lock of lockID expired)
+	Complete(ctx context.Context, key, lockID string, response IdempotentResponse, ttl time.Duration) error
+
+	// Unlock - releases the key held by the request lockID, so the request may be retried
+	Unlock(ctx context.Context, key, lockID string) error
+}
@@ -0,0 +1,11 @@
+package infrastructure
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	InitGlobalLogger("default", "go-service-template", "")
+	os.Exit(m.Run())
+}
@@ -0,0 +1,57 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+const defaultBrokerDialTimeout = time.Second * 5
+
+// BrokerChecker - checks availability of one kafka broker by opening a new connection to it
+type BrokerChecker struct {
+	address string
+}
+
+// NewBrokerCheckers - returns checkers for each broker from the config
+func NewBrokerCheckers(kafkaConfig KafkaConfig) []*BrokerChecker {
+	res := make([]*BrokerChecker, 0, len(kafkaConfig.BrokerList))
+	for _, address := range kafkaConfig.BrokerList {
+		res = append(res, &BrokerChecker{address: address})
+	}
+	return res
+}
+
+// Address - returns address of the broker
+func (c *BrokerChecker) Address() string {
+	return c.address
+}
+
+// HealthCheck - connects to the broker. Dial timeout is taken from ctx deadline
+func (c *BrokerChecker) HealthCheck(ctx context.Context) error {
+	config := sarama.NewConfig()
+	config.Net.DialTimeout = defaultBrokerDialTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		config.Net.DialTimeout = time.Until(deadline)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	broker := sarama.NewBroker(c.address)
+	if err := broker.Open(config); err != nil {
+		return err
+	}
+	defer func() {
+		_ = broker.Close()
+	}()
+	connected, err := broker.Connected()
+	if err != nil {
+		return err
+	}
+	if !connected {
+		return sarama.ErrNotConnected
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokerChecker_HealthCheck(t *testing.T) {
+	checkers := NewBrokerCheckers(KafkaConfig{BrokerList: []string{"127.0.0.1:1"}})
+	require.Len(t, checkers, 1)
+	assert.Equal(t, "127.0.0.1:1", checkers[0].Address())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Error(t, checkers[0].HealthCheck(ctx))
+}
@@ -6,14 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"go-service-template/internal/app/infrastructure"
 )
 
-// ErrBadParam - "bad param occurred" error
-var ErrBadParam = errors.New("bad param occurred")
+var (
+	// ErrBadParam - "bad param occurred" error
+	ErrBadParam = errors.New("bad param occurred")
+
+	// ErrConsumerNotReady - "kafka consumer isn't connected" error
+	ErrConsumerNotReady = errors.New("kafka consumer isn't connected")
+
+	// ErrConsumerStarted - "kafka consumer is already started" error
+	ErrConsumerStarted = errors.New("kafka consumer is already started")
+)
 
 const (
 	consumptionStopped           bool = false
@@ -49,8 +58,11 @@ type MessageConsumer interface {
 	Resume(ctx context.Context) error
 
 	AddHandler(ctx context.Context, topic string, h MessageHandleFunc) error
+	Topics() []string
 	Use(h MiddlewareFunc)
 	Ready() bool
+	Paused() bool
+	HealthCheck(ctx context.Context) error
 	Init(ctx context.Context) error
 	Close(ctx context.Context) error
 }
@@ -73,6 +85,19 @@ type consumer struct {
 	mCh              chan bool
 	cg               sarama.ConsumerGroup
 	db               db
+	backoff          infrastructure.Backoff
+	connected        int32
+	running          int32
+	paused           int32
+	// flowMu - serializes pause and resume of the group with pause of new claims
+	flowMu sync.Mutex
+
+	mu        sync.Mutex
+	started   bool
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
 }
 
 func NewConsumer(ctx context.Context, serviceName string, kafkaConfig KafkaConfig, db db) (MessageConsumer, error) {
@@ -83,6 +108,7 @@ func NewConsumer(ctx context.Context, serviceName string, kafkaConfig KafkaConfi
 	target.brokers = kafkaConfig.BrokerList
 	target.db = db
 	target.groupName = serviceName
+	target.backoff = infrastructure.Backoff{Initial: kafkaConfig.ReconnectInterval, Max: kafkaConfig.MaxReconnectInterval}
 
 	if err := target.Init(ctx); err != nil {
 		return nil, err
@@ -96,6 +122,7 @@ func (s *consumer) Init(ctx context.Context) error {
 	s.consumptionState = consumptionStopped
 	s.handlers = make(map[string]MessageHandleFunc)
 	s.mCh = make(chan bool)
+	s.done = make(chan struct{})
 
 	s.config = sarama.NewConfig()
 
@@ -103,29 +130,73 @@ func (s *consumer) Init(ctx context.Context) error {
 	s.config.Consumer.Offsets.Initial = sarama.OffsetNewest
 
 	s.Use(prepareLoggerMiddleware)
+	s.Use(prepareTenantMiddleware)
 	s.Use(logIncomingMessageMiddleware)
 
 	s.cg, err = sarama.NewConsumerGroup(s.brokers, s.groupName, s.config)
 	if err != nil {
-		s.LogError(ctx, "Error creating consumer group client", err)
-		return err
+		var cfgErr sarama.ConfigurationError
+		if errors.As(err, &cfgErr) {
+			s.LogError(ctx, "Error creating consumer group client", err)
+			return err
+		}
+		// brokers are unavailable. Consumer group is created by Start
+		s.Log(ctx).Warn().Err(err).Msg("kafka brokers are unavailable. consumer starts in degraded state")
+		return nil
 	}
+	atomic.StoreInt32(&s.connected, 1)
 	s.LogDebug(ctx, "consumer group created")
 	return nil
 }
 
+// connect - creates consumer group if it wasn't created by Init. Tries until success or ctx is canceled
+func (s *consumer) connect(ctx context.Context) bool {
+	backoff := s.backoff
+	for s.cg == nil {
+		if !backoff.Wait(ctx) {
+			return false
+		}
+		cg, err := sarama.NewConsumerGroup(s.brokers, s.groupName, s.config)
+		if err != nil {
+			s.Log(ctx).Debug().Err(err).Msg("can't create consumer group")
+			continue
+		}
+		s.cg = cg
+		atomic.StoreInt32(&s.connected, 1)
+		s.LogInfo(ctx, "consumer group created")
+	}
+	return true
+}
+
+// Start - joins consumer group and consumes messages. Blocks until ctx is canceled or Close is called
 func (s *consumer) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return ErrConsumerStarted
+	}
+	s.started = true
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.mu.Unlock()
+	defer close(s.done)
+
+	if !s.connect(ctx) {
+		s.LogInfo(ctx, "Sarama consumer terminating: context canceled")
+		return nil
+	}
 	handler := consumerHandler{
 		ready:      make(chan bool),
 		handlers:   s.handlers,
 		middleware: s.middleware,
 		db:         s.db,
+		pauseClaim: s.pauseClaim,
 	}
 
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
+		backoff := s.backoff
 		for {
 			s.LogDebug(ctx, "try to join to consumer group")
 			// `Consume` should be called inside an infinite loop, when a
@@ -134,7 +205,9 @@ func (s *consumer) Start(ctx context.Context) error {
 
 			if err := s.cg.Consume(ctx, s.topics, &handler); err != nil {
 				s.LogError(ctx, "can't join to consumer group", err)
-				time.Sleep(time.Second * 5)
+				backoff.Wait(ctx)
+			} else {
+				backoff.Reset()
 			}
 			// check if context was canceled, signaling that the consumer should stop
 			if ctx.Err() != nil {
@@ -144,21 +217,29 @@ func (s *consumer) Start(ctx context.Context) error {
 		}
 	}()
 
-	<-handler.ready
-	s.consumptionState = consumptionStarted
-	s.LogInfo(ctx, "Sarama consumer up and running")
+	select {
+	case <-handler.ready:
+		s.consumptionState = consumptionStarted
+		s.LogInfo(ctx, "Sarama consumer up and running")
+	case <-ctx.Done():
+		// consumer didn't join the group before termination
+		s.LogInfo(ctx, "Sarama consumer terminating: context canceled")
+		s.keepRunning = false
+	}
 
+	atomic.StoreInt32(&s.running, 1)
 	for s.keepRunning {
 		select {
 		case <-ctx.Done():
 			s.LogInfo(ctx, "Sarama consumer terminating: context canceled")
 			s.keepRunning = false
-		case <-s.mCh:
-			s.toggleConsumptionFlow(ctx, s.cg)
+		case state := <-s.mCh:
+			s.setConsumptionFlow(ctx, s.cg, state)
 		}
 	}
+	atomic.StoreInt32(&s.running, 0)
+	// wait for in-flight messages
 	wg.Wait()
-	_ = s.Close(ctx)
 	return nil
 }
 
@@ -166,6 +247,14 @@ func (s *consumer) Ready() bool {
 	return s.consumptionState
 }
 
+// HealthCheck - returns ErrConsumerNotReady if consumer group isn't created (brokers were unavailable)
+func (s *consumer) HealthCheck(_ context.Context) error {
+	if atomic.LoadInt32(&s.connected) == 0 {
+		return ErrConsumerNotReady
+	}
+	return nil
+}
+
 func (s *consumer) AddHandler(ctx context.Context, topic string, h MessageHandleFunc) error {
 	if topic == "" {
 		s.LogError(ctx, "topic name is empty", ErrBadParam)
@@ -180,36 +269,97 @@ func (s *consumer) AddHandler(ctx context.Context, topic string, h MessageHandle
 	return nil
 }
 
-func (s *consumer) Pause(_ context.Context) error {
-	s.mCh <- true
-	return nil
+// Topics - returns topics with registered handlers
+func (s *consumer) Topics() []string {
+	res := make([]string, len(s.topics))
+	copy(res, s.topics)
+	return res
 }
 
-func (s *consumer) Resume(_ context.Context) error {
-	s.mCh <- true
-	return nil
+// Pause - pauses consumption of all claimed partitions. Consumer stays in the group.
+// ErrConsumerNotReady is returned if consumer hasn't joined the group
+func (s *consumer) Pause(ctx context.Context) error {
+	return s.changeConsumptionFlow(ctx, consumptionStopped)
+}
+
+// Resume - resumes consumption paused by Pause
+func (s *consumer) Resume(ctx context.Context) error {
+	return s.changeConsumptionFlow(ctx, consumptionStarted)
+}
+
+// Paused - returns true if consumption is paused by Pause
+func (s *consumer) Paused() bool {
+	return atomic.LoadInt32(&s.paused) == 1
+}
+
+func (s *consumer) changeConsumptionFlow(ctx context.Context, state bool) error {
+	if atomic.LoadInt32(&s.running) == 0 {
+		return ErrConsumerNotReady
+	}
+	select {
+	case s.mCh <- state:
+		return nil
+	case <-s.done:
+		return ErrConsumerNotReady
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (s *consumer) Use(h MiddlewareFunc) {
 	s.middleware = append(s.middleware, h)
 }
 
+// Close - stops consumption, waits for in-flight messages and closes consumer group.
+// It's safe to call Close several times
 func (s *consumer) Close(ctx context.Context) error {
-	if err := s.cg.Close(); err != nil {
-		s.LogPanic(ctx, "Error closing consumer group", err)
+	s.mu.Lock()
+	started := s.started
+	cancel := s.cancel
+	s.mu.Unlock()
+	if started {
+		cancel()
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			s.LogWarn(ctx, "kafka consumer wasn't stopped in time")
+			return ctx.Err()
+		}
 	}
-	return nil
+
+	s.closeOnce.Do(func() {
+		if s.cg == nil {
+			return
+		}
+		if s.closeErr = s.cg.Close(); s.closeErr != nil {
+			s.LogError(ctx, "Error closing consumer group", s.closeErr)
+		}
+	})
+	return s.closeErr
 }
 
-func (s *consumer) toggleConsumptionFlow(ctx context.Context, cg sarama.ConsumerGroup) {
-	if s.consumptionState {
+func (s *consumer) setConsumptionFlow(ctx context.Context, cg sarama.ConsumerGroup, state bool) {
+	s.flowMu.Lock()
+	defer s.flowMu.Unlock()
+	if state == consumptionStarted {
 		cg.ResumeAll()
+		atomic.StoreInt32(&s.paused, 0)
 		s.LogInfo(ctx, "Resuming consumption")
 	} else {
 		cg.PauseAll()
+		atomic.StoreInt32(&s.paused, 1)
 		s.LogInfo(ctx, "Pausing consumption")
 	}
-	s.consumptionState = !s.consumptionState
+}
+
+// pauseClaim - pauses the partition claimed after rebalance if consumption is paused by Pause.
+// PauseAll affects only partitions consumed at the moment, new session starts consuming its claims
+func (s *consumer) pauseClaim(topic string, partition int32) {
+	s.flowMu.Lock()
+	defer s.flowMu.Unlock()
+	if s.Paused() {
+		s.cg.Pause(map[string][]int32{topic: {partition}})
+	}
 }
 
 //-----------------------------------------------------------//
@@ -220,6 +370,9 @@ type consumerHandler struct {
 	middleware []MiddlewareFunc
 	ready      chan bool
 	db         db
+	// pauseClaim - keeps pause of the consumer after rebalance. Partition consumers of the session
+	// are created after Setup, so the claim is paused in ConsumeClaim
+	pauseClaim func(topic string, partition int32)
 }
 
 // Setup is run at the beginning of a new session, before ConsumeClaim.
@@ -244,6 +397,9 @@ func (h *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, clai
 	// https://github.com/Shopify/sarama/blob/main/consumer_group.go#L27-L29
 	log := infrastructure.GetBaseLogger(session.Context())
 	log.Info().Msg(fmt.Sprintf("Consumer claim started(topic, partition,initial offset): %s, %d,%d", claim.Topic(), claim.Partition(), claim.InitialOffset()))
+	if h.pauseClaim != nil {
+		h.pauseClaim(claim.Topic(), claim.Partition())
+	}
 	for {
 		select {
 		case message := <-claim.Messages():
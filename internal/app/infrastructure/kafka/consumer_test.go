@@ -68,6 +68,9 @@ func TestIntegrationConsumerMessage_ProduceAndConsume(t *testing.T) {
 			consumeRes = make(chan sarama.ConsumerMessage)
 			consumer, err := NewConsumer(ctx, serviceName, kafkaConfig, postgresqlHandlerTX)
 			assert.NoError(t, err)
+			defer func() {
+				_ = consumer.Close(context.Background())
+			}()
 
 			err = consumer.AddHandler(ctx, tt.args.topic, tt.handler)
 			assert.NoError(t, err)
@@ -104,7 +107,7 @@ func TestIntegrationConsumerMessage_ProduceAndConsume(t *testing.T) {
 				assert.Equal(t, tt.args.message, incomingMsg.Value)
 
 				for k, v := range tt.args.headers {
-					assert.Contains(t, incomingMsg.Headers, &sarama.RecordHeader{[]byte(k), []byte(v)}, "received headers doesnt contains value  header(%s, %s)", k, string(v))
+					assert.Contains(t, incomingMsg.Headers, &sarama.RecordHeader{Key: []byte(k), Value: []byte(v)}, "received headers doesnt contains value  header(%s, %s)", k, string(v))
 				}
 
 			}
@@ -166,6 +169,9 @@ func TestIntegrationConsumerMessage_Context(t *testing.T) {
 			consumeRes = make(chan sarama.ConsumerMessage)
 			consumer, err := NewConsumer(ctx, serviceName, kafkaConfig, postgresqlHandlerTX)
 			assert.NoError(t, err)
+			defer func() {
+				_ = consumer.Close(context.Background())
+			}()
 
 			err = consumer.AddHandler(ctx, "territory.all.health-check", tt.handler)
 			assert.NoError(t, err)
@@ -388,3 +394,65 @@ func TestIntegrationConsumerMessage_headersToJSON(t *testing.T) {
 		})
 	}
 }
+
+type fakeConsumerGroup struct {
+	sarama.ConsumerGroup
+	paused map[string][]int32
+}
+
+func (g *fakeConsumerGroup) Pause(partitions map[string][]int32) {
+	for topic, p := range partitions {
+		g.paused[topic] = append(g.paused[topic], p...)
+	}
+}
+
+func (g *fakeConsumerGroup) PauseAll() {}
+
+func (g *fakeConsumerGroup) ResumeAll() {
+	g.paused = map[string][]int32{}
+}
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx context.Context
+}
+
+func (s *fakeSession) Context() context.Context {
+	return s.ctx
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	topic     string
+	partition int32
+}
+
+func (c *fakeClaim) Topic() string                            { return c.topic }
+func (c *fakeClaim) Partition() int32                         { return c.partition }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return nil }
+
+func TestConsumer_PauseAfterRebalance(t *testing.T) {
+	ctx := context.Background()
+	cg := &fakeConsumerGroup{paused: map[string][]int32{}}
+	c := &consumer{cg: cg}
+
+	// new session after rebalance, session is finished at once
+	claim := func(partition int32) {
+		h := consumerHandler{ready: make(chan bool), pauseClaim: c.pauseClaim}
+		assert.NoError(t, h.Setup(&fakeSession{}))
+		sessionCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.NoError(t, h.ConsumeClaim(&fakeSession{ctx: sessionCtx}, &fakeClaim{topic: "topic", partition: partition}))
+	}
+
+	c.setConsumptionFlow(ctx, cg, consumptionStopped)
+	assert.True(t, c.Paused())
+	claim(1)
+	assert.Equal(t, map[string][]int32{"topic": {1}}, cg.paused, "consumer. Pause. Case #1. Claim of new session is paused")
+
+	c.setConsumptionFlow(ctx, cg, consumptionStarted)
+	assert.False(t, c.Paused())
+	claim(2)
+	assert.Empty(t, cg.paused, "consumer. Pause. Case #2. Claim of resumed consumer isn't paused")
+}
@@ -1,9 +1,15 @@
 package kafka
 
+import "time"
+
 // KafkaConfig struct contains params for apache kafka connection
 type KafkaConfig struct { //nolint:revive
 	// BrokerList - list of brokers ( {"host:port"}[,"host:port"])
 	BrokerList []string `env:"BROKERS" envSeparator:"," yaml:"brokerList" validate:"required,min=1,dive,required"`
 	// LogSarama enable logging inside sarama
 	LogSarama bool `env:"LOG_SARAMA" yaml:"logSarama"`
+	// ReconnectInterval - initial delay between attempts to connect to the brokers. Delay is doubled after each failed attempt
+	ReconnectInterval time.Duration `yaml:"reconnectInterval"`
+	// MaxReconnectInterval - max delay between attempts to connect to the brokers
+	MaxReconnectInterval time.Duration `yaml:"maxReconnectInterval"`
 }
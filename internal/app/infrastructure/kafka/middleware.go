@@ -45,7 +45,7 @@ func prepareLoggerMiddleware(next MessageHandleFunc) MessageHandleFunc {
 			requestID     string
 			correlationID string
 		)
-		lc := infrastructure.GetBaseLogger(context.Background()).With() //nolint:contextcheck
+		lc := infrastructure.GetComponentLogger(infrastructure.ComponentKafkaConsumer).With()
 
 		for _, m := range message.Headers {
 			if string(m.Key) == infrastructure.RequestIDHeader {
@@ -74,3 +74,26 @@ func prepareLoggerMiddleware(next MessageHandleFunc) MessageHandleFunc {
 		return res
 	}
 }
+
+// prepareTenantMiddleware - adds tenant from message header into context. Message with invalid tenant isn't processed
+func prepareTenantMiddleware(next MessageHandleFunc) MessageHandleFunc {
+	return func(ctx context.Context, message sarama.ConsumerMessage) error {
+		var tenant string
+		for _, m := range message.Headers {
+			if string(m.Key) == infrastructure.TenantHeader {
+				tenant = string(m.Value)
+			}
+		}
+		if tenant == "" {
+			return next(ctx, message)
+		}
+		if err := infrastructure.ValidateTenant(tenant); err != nil {
+			infrastructure.GetBaseLogger(ctx).Error().Err(err).Str(infrastructure.TenantField, tenant).Msg("bad tenant in message header")
+			return err
+		}
+		newCtx := infrastructure.WithTenant(ctx, tenant)
+		l := infrastructure.GetBaseLogger(ctx).With().Str(infrastructure.TenantField, tenant).Logger()
+		newCtx = context.WithValue(newCtx, infrastructure.CtxKeyLogger{}, &l)
+		return next(newCtx, message)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/Shopify/sarama"
@@ -29,16 +30,28 @@ const (
 	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`
 )
 
-// ErrNoKafkaBrokers - "can't get kafka broker" error
-var ErrNoKafkaBrokers = errors.New("can't get kafka broker")
+var (
+	// ErrNoKafkaBrokers - "can't get kafka broker" error
+	ErrNoKafkaBrokers = errors.New("can't get kafka broker")
 
-// MessageProducer struct for interactions with kafka cluster
+	// ErrProducerNotReady - "kafka producer isn't connected" error
+	ErrProducerNotReady = errors.New("kafka producer isn't connected")
+)
+
+// MessageProducer struct for interactions with kafka cluster.
+// If brokers are unavailable on start, producer is connected in background. Until then messages are saved
+// into kafka_out_error_messages as failed ones
 type MessageProducer struct {
 	infrastructure.SugarLogger
-	brokers  []string
-	config   *sarama.Config
-	producer sarama.SyncProducer
-	db       db
+	brokers []string
+	config  *sarama.Config
+	db      db
+	backoff infrastructure.Backoff
+
+	mu              sync.RWMutex
+	producer        sarama.SyncProducer
+	reconnectCancel context.CancelFunc
+	reconnectDone   chan struct{}
 }
 
 // NewMessageProducer return new MessageProducer
@@ -50,6 +63,7 @@ func NewMessageProducer(ctx context.Context, kafkaConfig KafkaConfig, db db) (*M
 
 	target.brokers = kafkaConfig.BrokerList
 	target.db = db
+	target.backoff = infrastructure.Backoff{Initial: kafkaConfig.ReconnectInterval, Max: kafkaConfig.MaxReconnectInterval}
 
 	if err := target.Init(ctx); err != nil {
 		return nil, err
@@ -58,7 +72,8 @@ func NewMessageProducer(ctx context.Context, kafkaConfig KafkaConfig, db db) (*M
 	return &target, nil
 }
 
-// Init - func for initialisation MessageProducer
+// Init - func for initialisation MessageProducer. Only configuration errors are returned.
+// If brokers are unavailable, producer starts in degraded state and reconnects in background
 func (h *MessageProducer) Init(ctx context.Context) error {
 	h.config = sarama.NewConfig()
 	h.config.Producer.Partitioner = sarama.NewRandomPartitioner
@@ -66,14 +81,61 @@ func (h *MessageProducer) Init(ctx context.Context) error {
 	h.config.Producer.Return.Successes = true
 	producer, err := sarama.NewSyncProducer(h.brokers, h.config)
 	if err != nil {
-		h.LogError(ctx, "Can't get new sync messageProducer", err)
-		return err
+		var cfgErr sarama.ConfigurationError
+		if errors.As(err, &cfgErr) {
+			h.LogError(ctx, "Can't get new sync messageProducer", err)
+			return err
+		}
+		h.Log(ctx).Warn().Err(err).Msg("kafka brokers are unavailable. producer starts in degraded state")
+		h.startReconnect()
+		return nil
 	}
 	h.producer = producer
 
 	return nil
 }
 
+// Ready - returns true if producer is connected to the brokers
+func (h *MessageProducer) Ready() bool {
+	return h.syncProducer() != nil
+}
+
+// HealthCheck - returns ErrProducerNotReady if producer isn't connected to the brokers
+func (h *MessageProducer) HealthCheck(_ context.Context) error {
+	if !h.Ready() {
+		return ErrProducerNotReady
+	}
+	return nil
+}
+
+func (h *MessageProducer) syncProducer() sarama.SyncProducer {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.producer
+}
+
+// startReconnect - starts background connection to the brokers with backoff
+func (h *MessageProducer) startReconnect() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.reconnectCancel = cancel
+	h.reconnectDone = make(chan struct{})
+	go func() {
+		defer close(h.reconnectDone)
+		for h.backoff.Wait(ctx) {
+			producer, err := sarama.NewSyncProducer(h.brokers, h.config)
+			if err != nil {
+				h.Log(ctx).Debug().Err(err).Msg("can't connect kafka producer")
+				continue
+			}
+			h.mu.Lock()
+			h.producer = producer
+			h.mu.Unlock()
+			h.LogInfo(ctx, "kafka producer connected")
+			return
+		}
+	}()
+}
+
 // SendMessage - send message into the kafka topic
 func (h *MessageProducer) SendMessage(ctx context.Context, topic string, key string, headers map[string][]byte, message []byte) error {
 	saramaRecordHeaders := make([]sarama.RecordHeader, 0)
@@ -93,6 +155,12 @@ func (h *MessageProducer) SendMessage(ctx context.Context, topic string, key str
 		}
 	}
 
+	if _, ok := headers[infrastructure.TenantHeader]; !ok {
+		if tenant, ok := infrastructure.TenantFromContext(ctx); ok {
+			headers[infrastructure.TenantHeader] = []byte(tenant)
+		}
+	}
+
 	for headerKey, headerValue := range headers {
 		saramaRecordHeaders = append(saramaRecordHeaders, sarama.RecordHeader{Key: []byte(headerKey), Value: headerValue})
 	}
@@ -103,7 +171,14 @@ func (h *MessageProducer) SendMessage(ctx context.Context, topic string, key str
 		Value:   sarama.ByteEncoder(message),
 		Headers: saramaRecordHeaders,
 	}
-	partition, offset, err := h.producer.SendMessage(producerMessage)
+	var (
+		partition int32
+		offset    int64
+		err       = ErrProducerNotReady
+	)
+	if producer := h.syncProducer(); producer != nil {
+		partition, offset, err = producer.SendMessage(producerMessage)
+	}
 	if err != nil {
 		h.LogError(ctx, "Can' send message to kafka topic", err)
 		// errorHandler must be started with new context!
@@ -127,7 +202,15 @@ func (h *MessageProducer) SendMessage(ctx context.Context, topic string, key str
 
 // Close - close allocated resources
 func (h *MessageProducer) Close(ctx context.Context) error {
-	err := h.producer.Close()
+	if h.reconnectCancel != nil {
+		h.reconnectCancel()
+		<-h.reconnectDone
+	}
+	producer := h.syncProducer()
+	if producer == nil {
+		return nil
+	}
+	err := producer.Close()
 	if err != nil {
 		h.LogError(ctx, "Can't close messageProducer", err)
 	}
@@ -216,3 +216,29 @@ func TestIntegrationMessageProducer_errorHandler(t *testing.T) {
 		})
 	}
 }
+
+type fakeDB struct {
+	statements []string
+}
+
+func (f *fakeDB) Execute(_ context.Context, statement string, _ ...interface{}) error {
+	f.statements = append(f.statements, statement)
+	return nil
+}
+
+func (f *fakeDB) GetNextID(_ context.Context, _ string) (int64, error) {
+	return 1, nil
+}
+
+func TestMessageProducer_DegradedStart(t *testing.T) {
+	ctx := context.Background()
+	db := &fakeDB{}
+	producer, err := NewMessageProducer(ctx, KafkaConfig{BrokerList: []string{"127.0.0.1:1"}, ReconnectInterval: time.Hour}, db)
+	require.NoError(t, err)
+	defer producer.Close(ctx)
+
+	assert.False(t, producer.Ready())
+	// message is saved as failed one
+	require.NoError(t, producer.SendMessage(ctx, "test_topic", "test_key", map[string][]byte{}, []byte("test message")))
+	assert.Equal(t, []string{addKafkaOutErrorMessage}, db.statements)
+}
@@ -0,0 +1,204 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ShutdownPhase - group of resources stopped together. Phases are stopped in ascending order
+type ShutdownPhase int
+
+const (
+	// PhaseInput - sources of work: http server, kafka consumer, job workers, notification listeners.
+	// Resources of the phase are stopped concurrently and drain in-flight work within DrainTimeout
+	PhaseInput ShutdownPhase = iota
+
+	// PhaseBackground - background jobs which don't process external work (maintenance, monitoring)
+	PhaseBackground
+
+	// PhaseOutput - outgoing channels (kafka producer). Buffered messages are flushed on close
+	PhaseOutput
+
+	// PhaseStorage - databases. They are closed last, because all other resources may use them
+	PhaseStorage
+)
+
+var phaseNames = map[ShutdownPhase]string{
+	PhaseInput:      "input",
+	PhaseBackground: "background",
+	PhaseOutput:     "output",
+	PhaseStorage:    "storage",
+}
+
+// String - implementation of fmt.Stringer
+func (p ShutdownPhase) String() string {
+	if name, ok := phaseNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("phase-%d", int(p))
+}
+
+// ErrLifecycleClosed - "lifecycle is already shut down" error
+var ErrLifecycleClosed = errors.New("lifecycle is already shut down")
+
+// Closer - interface for resources stopped by Lifecycle
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// CloserFunc - adapter for using ordinary functions as Closer
+type CloserFunc func(ctx context.Context) error
+
+// Close - implementation of Closer
+func (f CloserFunc) Close(ctx context.Context) error {
+	return f(ctx)
+}
+
+// ShutdownError - errors of all resources failed to stop
+type ShutdownError struct {
+	Errors []error
+}
+
+// Error - implementation of error interface
+func (e *ShutdownError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return "shutdown failed: " + strings.Join(msgs, "; ")
+}
+
+// Is - returns true if any of the errors matches target
+func (e *ShutdownError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type lifecycleResource struct {
+	name   string
+	phase  ShutdownPhase
+	closer Closer
+}
+
+// Lifecycle - stops resources in order: inputs first (with drain of in-flight work), then background jobs,
+// outputs and storages. Resources of one phase are stopped in reverse registration order
+type Lifecycle struct {
+	SugarLogger
+	drainTimeout time.Duration
+
+	mu        sync.Mutex
+	resources []lifecycleResource
+	closed    bool
+}
+
+// NewLifecycle returns new Lifecycle. drainTimeout - budget for PhaseInput. If it is <= 0,
+// inputs are drained until the shutdown context is done
+func NewLifecycle(drainTimeout time.Duration) *Lifecycle {
+	var target Lifecycle
+	target.drainTimeout = drainTimeout
+	return &target
+}
+
+// Add - registers resource for shutdown
+func (l *Lifecycle) Add(phase ShutdownPhase, name string, closer Closer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.resources = append(l.resources, lifecycleResource{name: name, phase: phase, closer: closer})
+}
+
+// Shutdown - stops all resources phase by phase. Errors of all resources are collected into ShutdownError.
+// Resources which aren't stopped before ctx is done are reported with ctx error
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrLifecycleClosed
+	}
+	l.closed = true
+	resources := l.resources
+	l.mu.Unlock()
+
+	var errs []error
+	for _, phase := range []ShutdownPhase{PhaseInput, PhaseBackground, PhaseOutput, PhaseStorage} {
+		var phaseResources []lifecycleResource
+		for ind := len(resources) - 1; ind >= 0; ind-- {
+			if resources[ind].phase == phase {
+				phaseResources = append(phaseResources, resources[ind])
+			}
+		}
+		if len(phaseResources) == 0 {
+			continue
+		}
+		l.Log(ctx).Info().Stringer("phase", phase).Int("resources", len(phaseResources)).Msg("shutdown phase started")
+		if phase == PhaseInput {
+			errs = append(errs, l.drain(ctx, phaseResources)...)
+			continue
+		}
+		for _, r := range phaseResources {
+			if err := l.close(ctx, r); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return &ShutdownError{Errors: errs}
+	}
+	l.LogInfo(ctx, "all resources are stopped")
+	return nil
+}
+
+// drain - stops inputs concurrently within drainTimeout
+func (l *Lifecycle) drain(ctx context.Context, resources []lifecycleResource) []error {
+	if l.drainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.drainTimeout)
+		defer cancel()
+	}
+	errs := make([]error, len(resources))
+	wg := &sync.WaitGroup{}
+	for ind, r := range resources {
+		wg.Add(1)
+		go func(ind int, r lifecycleResource) {
+			defer wg.Done()
+			errs[ind] = l.close(ctx, r)
+		}(ind, r)
+	}
+	wg.Wait()
+
+	var res []error
+	for _, err := range errs {
+		if err != nil {
+			res = append(res, err)
+		}
+	}
+	return res
+}
+
+// close - stops one resource. It doesn't wait for the resource after ctx is done
+func (l *Lifecycle) close(ctx context.Context, r lifecycleResource) error {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- r.closer.Close(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		l.Log(ctx).Error().Err(err).Str("resource", r.name).Stringer("phase", r.phase).Msg("can't stop resource")
+		return fmt.Errorf("%s: %w", r.name, err)
+	}
+	l.Log(ctx).Info().Str("resource", r.name).Dur("duration", time.Since(start)).Msg("resource stopped")
+	return nil
+}
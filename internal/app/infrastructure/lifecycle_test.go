@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycle_Shutdown(t *testing.T) {
+	errClose := errors.New("close error")
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	closer := func(name string, err error) Closer {
+		return CloserFunc(func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return err
+		})
+	}
+
+	tests := []struct {
+		name      string
+		resources func(l *Lifecycle)
+		wantOrder []string
+		wantErr   error
+	}{
+		{
+			name: "Lifecycle. Shutdown. Case #1. Phases order and reverse registration order",
+			resources: func(l *Lifecycle) {
+				l.Add(PhaseStorage, "postgres", closer("postgres", nil))
+				l.Add(PhaseOutput, "kafka-producer", closer("kafka-producer", nil))
+				l.Add(PhaseBackground, "partition-maintainer", closer("partition-maintainer", nil))
+				l.Add(PhaseBackground, "dependency-monitor", closer("dependency-monitor", nil))
+				l.Add(PhaseInput, "http", closer("http", nil))
+			},
+			wantOrder: []string{"http", "dependency-monitor", "partition-maintainer", "kafka-producer", "postgres"},
+		},
+		{
+			name: "Lifecycle. Shutdown. Case #2. All resources are closed despite errors",
+			resources: func(l *Lifecycle) {
+				l.Add(PhaseStorage, "postgres", closer("postgres", nil))
+				l.Add(PhaseOutput, "kafka-producer", closer("kafka-producer", errClose))
+			},
+			wantOrder: []string{"kafka-producer", "postgres"},
+			wantErr:   errClose,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order = nil
+			l := NewLifecycle(time.Second)
+			tt.resources(l)
+			err := l.Shutdown(context.Background())
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantOrder, order)
+			assert.ErrorIs(t, l.Shutdown(context.Background()), ErrLifecycleClosed)
+		})
+	}
+}
+
+func TestLifecycle_ShutdownDrainTimeout(t *testing.T) {
+	var closed []string
+	l := NewLifecycle(time.Millisecond * 50)
+	l.Add(PhaseInput, "kafka-consumer", CloserFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	l.Add(PhaseInput, "http", CloserFunc(func(ctx context.Context) error {
+		time.Sleep(time.Hour)
+		return nil
+	}))
+	l.Add(PhaseStorage, "postgres", CloserFunc(func(ctx context.Context) error {
+		closed = append(closed, "postgres")
+		return ctx.Err()
+	}))
+
+	start := time.Now()
+	err := l.Shutdown(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	var shutdownErr *ShutdownError
+	require.ErrorAs(t, err, &shutdownErr)
+	assert.Len(t, shutdownErr.Errors, 2)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// drain timeout doesn't affect next phases
+	assert.Equal(t, []string{"postgres"}, closed)
+}
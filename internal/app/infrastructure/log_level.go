@@ -0,0 +1,156 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	zero "github.com/rs/zerolog"
+)
+
+// ErrBadLogLevel - "bad log level" error
+var ErrBadLogLevel = errors.New("bad log level")
+
+// ComponentField - component field name. Used for logger
+const ComponentField = "component"
+
+// components with own log level. Loggers of incoming requests, messages, notifications and jobs belong to them
+const (
+	// ComponentHTTP - http requests
+	ComponentHTTP = "http"
+
+	// ComponentKafkaConsumer - incoming kafka messages
+	ComponentKafkaConsumer = "kafka-consumer"
+
+	// ComponentPostgresListener - postgres notifications
+	ComponentPostgresListener = "postgres-listener"
+
+	// ComponentJobQueue - background jobs
+	ComponentJobQueue = "job-queue"
+)
+
+var knownComponents = map[string]bool{
+	ComponentHTTP:             true,
+	ComponentKafkaConsumer:    true,
+	ComponentPostgresListener: true,
+	ComponentJobQueue:         true,
+}
+
+// logLevels - global and per-component log levels. zerolog global level is the lowest of them,
+// so loggers of components may be more verbose than the base logger
+type logLevels struct {
+	mu         sync.RWMutex
+	root       Logger
+	global     zero.Level
+	components map[string]zero.Level
+}
+
+var levels = &logLevels{components: make(map[string]zero.Level)}
+
+func (l *logLevels) init(root Logger, global zero.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.root = root
+	l.global = global
+	l.apply()
+}
+
+// apply - sets zerolog global level and replaces the base logger. Must be called under lock
+func (l *logLevels) apply() {
+	lowest := l.global
+	for _, level := range l.components {
+		if level < lowest {
+			lowest = level
+		}
+	}
+	zero.SetGlobalLevel(lowest)
+	base := l.root.Level(l.global)
+	baseLog.Store(&base)
+}
+
+// SetLogLevel - changes global log level. Loggers created before the change (e.g. of in-flight requests) keep their level
+func SetLogLevel(level string) error {
+	lvl, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.global = lvl
+	levels.apply()
+	return nil
+}
+
+// SetComponentLogLevel - overrides log level of the component
+func SetComponentLogLevel(component, level string) error {
+	if !knownComponents[component] {
+		return fmt.Errorf("%w: unknown component %q", ErrBadLogLevel, component)
+	}
+	lvl, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.components[component] = lvl
+	levels.apply()
+	return nil
+}
+
+// ResetComponentLogLevel - removes override of the component log level. The component uses global level after it
+func ResetComponentLogLevel(component string) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	delete(levels.components, component)
+	levels.apply()
+}
+
+// LogLevels - returns global log level and overridden levels of components
+func LogLevels() (string, map[string]string) {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+	components := make(map[string]string, len(levels.components))
+	for component, level := range levels.components {
+		components[component] = level.String()
+	}
+	return levels.global.String(), components
+}
+
+// GetComponentLogger - returns base logger of the component with component field and its log level
+func GetComponentLogger(component string) *Logger {
+	levels.mu.RLock()
+	level, ok := levels.components[component]
+	levels.mu.RUnlock()
+
+	l := GetBaseLogger(context.Background()).With().Str(ComponentField, component).Logger()
+	if ok {
+		l = l.Level(level)
+	}
+	return &l
+}
+
+// LogLevelNames - returns names of supported log levels
+func LogLevelNames() []string {
+	res := []string{"warning"}
+	for level := zero.TraceLevel; level <= zero.PanicLevel; level++ {
+		res = append(res, level.String())
+	}
+	sort.Strings(res)
+	return res
+}
+
+// parseLogLevel - parses log level name. "warning" is supported for compatibility with logger.logLevel config param
+func parseLogLevel(level string) (zero.Level, error) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if level == "warning" {
+		return zero.WarnLevel, nil
+	}
+	lvl, err := zero.ParseLevel(level)
+	if err != nil || level == "" || lvl == zero.NoLevel || lvl == zero.Disabled {
+		return zero.NoLevel, fmt.Errorf("%w: %q", ErrBadLogLevel, level)
+	}
+	return lvl, nil
+}
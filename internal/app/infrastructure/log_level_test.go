@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"testing"
+
+	zero "github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetComponentLogLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		global     string
+		component  string
+		level      string
+		wantErr    error
+		wantGlobal zero.Level
+		wantLevel  zero.Level
+	}{
+		{
+			name:       "SetComponentLogLevel Case#1 Component is more verbose than global level",
+			global:     "info",
+			component:  ComponentHTTP,
+			level:      "debug",
+			wantGlobal: zero.DebugLevel,
+			wantLevel:  zero.DebugLevel,
+		},
+		{
+			name:       "SetComponentLogLevel Case#2 Component is less verbose than global level",
+			global:     "warning",
+			component:  ComponentKafkaConsumer,
+			level:      "error",
+			wantGlobal: zero.WarnLevel,
+			wantLevel:  zero.ErrorLevel,
+		},
+		{
+			name:       "SetComponentLogLevel Case#3 Unknown level",
+			global:     "info",
+			component:  ComponentHTTP,
+			level:      "loud",
+			wantErr:    ErrBadLogLevel,
+			wantGlobal: zero.InfoLevel,
+			wantLevel:  zero.InfoLevel,
+		},
+		{
+			name:       "SetComponentLogLevel Case#4 Unknown component",
+			global:     "info",
+			component:  "redis",
+			level:      "debug",
+			wantErr:    ErrBadLogLevel,
+			wantGlobal: zero.InfoLevel,
+			wantLevel:  zero.InfoLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, SetLogLevel(tt.global))
+			defer ResetComponentLogLevel(tt.component)
+
+			err := SetComponentLogLevel(tt.component, tt.level)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantGlobal, zero.GlobalLevel())
+			assert.Equal(t, tt.wantLevel, GetComponentLogger(tt.component).GetLevel())
+		})
+	}
+}
+
+func TestResetComponentLogLevel(t *testing.T) {
+	assert.NoError(t, SetLogLevel("info"))
+	assert.NoError(t, SetComponentLogLevel(ComponentJobQueue, "trace"))
+	level, components := LogLevels()
+	assert.Equal(t, "info", level)
+	assert.Equal(t, map[string]string{ComponentJobQueue: "trace"}, components)
+	assert.Equal(t, zero.TraceLevel, zero.GlobalLevel())
+
+	ResetComponentLogLevel(ComponentJobQueue)
+	_, components = LogLevels()
+	assert.Empty(t, components)
+	assert.Equal(t, zero.InfoLevel, zero.GlobalLevel())
+	assert.Equal(t, zero.InfoLevel, GetComponentLogger(ComponentJobQueue).GetLevel())
+}
@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"fmt"
 	"os"
+	"sync/atomic"
 
 	"github.com/lithammer/shortuuid/v4"
 	zero "github.com/rs/zerolog"
@@ -10,7 +11,8 @@ import (
 	"golang.org/x/net/context"
 )
 
-var baseLog *Logger
+// baseLog - *Logger with the global level applied. It's replaced by SetLogLevel
+var baseLog atomic.Value
 
 type (
 	// Logger - alias for zero.Logger type
@@ -80,19 +82,11 @@ func InitGlobalLogger(logLevel string, serviceName string, serviceInstance strin
 		Str("service", serviceName).
 		Str("instance", serviceInstance).
 		Logger()
-	switch logLevel {
-	case "warning":
-		zero.SetGlobalLevel(zero.WarnLevel)
-	case "info":
-		zero.SetGlobalLevel(zero.InfoLevel)
-	case "debug":
-		zero.SetGlobalLevel(zero.DebugLevel)
-	case "trace":
-		zero.SetGlobalLevel(zero.TraceLevel)
-	default:
-		zero.SetGlobalLevel(zero.InfoLevel)
+	level, err := parseLogLevel(logLevel)
+	if err != nil {
+		level = zero.InfoLevel
 	}
-	baseLog = &l
+	levels.init(l, level)
 }
 
 //------------------------------Sarama logger--------------------------------------------------//
@@ -132,7 +126,7 @@ func GetBaseLogger(ctx context.Context) *Logger {
 	l, ok := ctx.Value(CtxKeyLogger{}).(*Logger)
 	if !ok || l == nil {
 		// looking for logger in context. If not found return baseLog
-		l = baseLog
+		l, _ = baseLog.Load().(*Logger)
 	}
 	return l
 }
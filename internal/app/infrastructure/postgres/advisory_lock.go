@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+var (
+	// ErrLockTimeout - "can't acquire lock: timeout expired" error
+	ErrLockTimeout = errors.New("can't acquire lock: timeout expired")
+
+	// ErrLockReleased - "lock is already released" error
+	ErrLockReleased = errors.New("lock is already released")
+)
+
+const (
+	lockPollInterval = time.Millisecond * 200
+
+	tryAdvisoryLockStatement     = "SELECT pg_try_advisory_lock($1)"
+	advisoryUnlockStatement      = "SELECT pg_advisory_unlock($1)"
+	tryAdvisoryXactLockStatement = "SELECT pg_try_advisory_xact_lock($1)"
+)
+
+// LockKey - converts lock name into the key for pg_advisory_lock functions
+func LockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// AdvisoryLock - session level advisory lock. Lock holds the connection until Unlock is called
+type AdvisoryLock struct {
+	name string
+	key  int64
+	conn *pgxpool.Conn
+}
+
+// Name - returns lock name
+func (l *AdvisoryLock) Name() string {
+	return l.name
+}
+
+// Unlock - releases lock and returns connection into the pool
+func (l *AdvisoryLock) Unlock(ctx context.Context) error {
+	if l.conn == nil {
+		return ErrLockReleased
+	}
+	conn := l.conn
+	l.conn = nil
+	defer conn.Release()
+
+	var released bool
+	if err := conn.QueryRow(ctx, advisoryUnlockStatement, l.key).Scan(&released); err != nil {
+		// connection state is unknown. Close it, so lock will be released by the server
+		_ = conn.Conn().Close(ctx)
+		return err
+	}
+	if !released {
+		return ErrLockReleased
+	}
+	return nil
+}
+
+// TryLock - tries to acquire session level advisory lock without waiting. If lock is held by someone else, nil lock is returned
+func (handler *PostgresqlHandlerTX) TryLock(ctx context.Context, name string) (*AdvisoryLock, error) {
+	conn, err := handler.pool.Acquire(ctx)
+	if err != nil {
+		handler.LogError(ctx, "Can't acquire connection from pool", err)
+		return nil, err
+	}
+	key := LockKey(name)
+	var locked bool
+	if err = conn.QueryRow(ctx, tryAdvisoryLockStatement, key).Scan(&locked); err != nil {
+		conn.Release()
+		handler.LogError(ctx, "can't acquire advisory lock "+name, err)
+		return nil, err
+	}
+	if !locked {
+		conn.Release()
+		return nil, nil //nolint:nilnil
+	}
+	return &AdvisoryLock{name: name, key: key, conn: conn}, nil
+}
+
+// Lock - acquires session level advisory lock. Waits for lock until timeout expires or context is canceled.
+// If timeout is expired ErrLockTimeout is returned
+func (handler *PostgresqlHandlerTX) Lock(ctx context.Context, name string, timeout time.Duration) (*AdvisoryLock, error) {
+	var lock *AdvisoryLock
+	err := handler.waitLock(ctx, timeout, func() (bool, error) {
+		var err error
+		lock, err = handler.TryLock(ctx, name)
+		return lock != nil, err
+	})
+	return lock, err
+}
+
+// TryTxLock - tries to acquire transaction level advisory lock without waiting.
+// Lock is released automatically at the end of transaction. Transaction must be in the context
+func (handler *PostgresqlHandlerTX) TryTxLock(ctx context.Context, name string) (bool, error) {
+	tx, err := handler.getTx(ctx)
+	if err != nil {
+		handler.LogError(ctx, "transaction level lock requires transaction", err)
+		return false, err
+	}
+	var locked bool
+	if err = tx.QueryRow(ctx, tryAdvisoryXactLockStatement, LockKey(name)).Scan(&locked); err != nil {
+		handler.LogError(ctx, "can't acquire transaction advisory lock "+name, err)
+		return false, err
+	}
+	return locked, nil
+}
+
+// TxLock - acquires transaction level advisory lock. Waits for lock until timeout expires or context is canceled.
+// If timeout is expired ErrLockTimeout is returned
+func (handler *PostgresqlHandlerTX) TxLock(ctx context.Context, name string, timeout time.Duration) error {
+	return handler.waitLock(ctx, timeout, func() (bool, error) {
+		return handler.TryTxLock(ctx, name)
+	})
+}
+
+// waitLock - calls tryLock until it succeeds, timeout expires or context is canceled
+func (handler *PostgresqlHandlerTX) waitLock(ctx context.Context, timeout time.Duration, tryLock func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock()
+		if err != nil {
+			return err
+		}
+		if locked {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return ErrLockTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
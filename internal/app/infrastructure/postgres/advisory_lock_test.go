@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockKey(t *testing.T) {
+	assert.Equal(t, LockKey("job"), LockKey("job"))
+	assert.NotEqual(t, LockKey("job1"), LockKey("job2"))
+}
+
+func TestNewLeaderElector(t *testing.T) {
+	_, err := NewLeaderElector(context.Background(), &PostgresqlHandlerTX{}, LeaderElectionConfig{}, LeaderCallbacks{})
+	assert.ErrorIs(t, err, ErrLeaderElectorBadParam)
+
+	e, err := NewLeaderElector(context.Background(), &PostgresqlHandlerTX{}, LeaderElectionConfig{Name: "job"}, LeaderCallbacks{})
+	assert.NoError(t, err)
+	assert.False(t, e.IsLeader())
+	assert.NoError(t, e.Close(context.Background()))
+}
+
+func TestIntegrationPostgresqlHandlerTX_Lock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := context.Background()
+
+	lock, err := target.TryLock(ctx, "test_lock")
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+
+	// lock is held by another session
+	another, err := target.TryLock(ctx, "test_lock")
+	assert.NoError(t, err)
+	assert.Nil(t, another)
+
+	_, err = target.Lock(ctx, "test_lock", time.Second)
+	assert.ErrorIs(t, err, ErrLockTimeout)
+
+	assert.NoError(t, lock.Unlock(ctx))
+	assert.ErrorIs(t, lock.Unlock(ctx), ErrLockReleased)
+
+	another, err = target.Lock(ctx, "test_lock", time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, another)
+	assert.NoError(t, another.Unlock(ctx))
+}
+
+func TestIntegrationPostgresqlHandlerTX_TxLock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+
+	_, err := target.TryTxLock(context.Background(), "test_tx_lock")
+	assert.ErrorIs(t, err, ErrTxNotFound)
+
+	err = target.WithTx(context.Background())(context.Background(), func(ctx context.Context) error {
+		locked, err := target.TryTxLock(ctx, "test_tx_lock")
+		assert.NoError(t, err)
+		assert.True(t, locked)
+
+		// lock is held by another transaction
+		return target.WithTx(context.Background())(context.Background(), func(ctx context.Context) error {
+			locked, err := target.TryTxLock(ctx, "test_tx_lock")
+			assert.NoError(t, err)
+			assert.False(t, locked)
+			return nil
+		})
+	})
+	assert.NoError(t, err)
+
+	// lock is released at the end of transaction
+	err = target.WithTx(context.Background())(context.Background(), func(ctx context.Context) error {
+		return target.TxLock(ctx, "test_tx_lock", time.Second)
+	})
+	assert.NoError(t, err)
+}
+
+func TestIntegrationLeaderElector(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var leaders int32
+	callbacks := LeaderCallbacks{
+		OnStartedLeading: func(ctx context.Context) { atomic.AddInt32(&leaders, 1) },
+		OnStoppedLeading: func() { atomic.AddInt32(&leaders, -1) },
+	}
+	cfg := LeaderElectionConfig{Name: "test_election", CheckInterval: time.Millisecond * 200}
+	first, err := NewLeaderElector(ctx, target, cfg, callbacks)
+	require.NoError(t, err)
+	second, err := NewLeaderElector(ctx, target, cfg, callbacks)
+	require.NoError(t, err)
+
+	go func() { _ = first.Start(ctx) }()
+	require.Eventually(t, first.IsLeader, time.Second*10, time.Millisecond*100)
+
+	go func() { _ = second.Start(ctx) }()
+	time.Sleep(time.Second)
+	assert.False(t, second.IsLeader())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&leaders))
+
+	assert.NoError(t, first.Close(ctx))
+	assert.False(t, first.IsLeader())
+	require.Eventually(t, second.IsLeader, time.Second*10, time.Millisecond*100)
+	assert.NoError(t, second.Close(ctx))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&leaders))
+}
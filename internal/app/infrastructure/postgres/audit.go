@@ -0,0 +1,18 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// setAuditContextStatement - set_config(..., true) is equivalent of SET LOCAL, settings are reset at the end of transaction
+const setAuditContextStatement = "SELECT set_config('app.user', $1, true), set_config('app.request_id', $2, true)"
+
+// setAuditContext - passes caller identity and requestID from ctx into transaction settings for audit_log triggers
+func (handler *PostgresqlHandlerTX) setAuditContext(ctx context.Context, tx pgx.Tx) error {
+	requestID, _ := ctx.Value(infrastructure.CtxKeyRequestID{}).(string)
+	_, err := tx.Exec(ctx, setAuditContextStatement, infrastructure.UserFromContext(ctx), requestID)
+	return err
+}
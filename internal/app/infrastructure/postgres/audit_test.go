@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/infrastructure"
+)
+
+func TestIntegrationPostgresqlHandlerTX_AuditContext(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := context.Background()
+	require.NoError(t, target.Execute(ctx, "CREATE TABLE IF NOT EXISTS audited_table (id bigint PRIMARY KEY, name text)"))
+	require.NoError(t, target.Execute(ctx, "DELETE FROM audited_table"))
+	require.NoError(t, target.Execute(ctx, "SELECT audit_log_enable('audited_table')"))
+
+	handler, err := NewPostgresqlHandlerTX(ctx, dsn, PgPoolConfig{AuditContext: true})
+	require.NoError(t, err)
+	defer handler.Close(ctx)
+
+	ctx = infrastructure.WithUser(context.WithValue(ctx, infrastructure.CtxKeyRequestID{}, "req-1"), "alice")
+	err = handler.WithTx(ctx)(ctx, func(ctx context.Context) error {
+		if err := handler.Execute(ctx, "INSERT INTO audited_table (id, name) VALUES (1, 'a')"); err != nil {
+			return err
+		}
+		return handler.Execute(ctx, "UPDATE audited_table SET name = 'b' WHERE id = 1")
+	})
+	require.NoError(t, err)
+
+	rows, err := handler.Query(context.Background(), `SELECT operation, app_user, request_id, new_data->>'name'
+		FROM audit_log WHERE table_name = 'audited_table' AND entity_id = '1' ORDER BY id`)
+	require.NoError(t, err)
+	defer rows.Close()
+	var got [][]string
+	for rows.Next() {
+		var operation, user, requestID, name string
+		require.NoError(t, rows.Scan(&operation, &user, &requestID, &name))
+		got = append(got, []string{operation, user, requestID, name})
+	}
+	assert.Equal(t, [][]string{{"INSERT", "alice", "req-1", "a"}, {"UPDATE", "alice", "req-1", "b"}}, got)
+}
@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ErrCursorClosed - "cursor is closed" error
+var ErrCursorClosed = errors.New("cursor is closed")
+
+const (
+	defaultCursorFetchSize = 1000
+	cursorCloseTimeout     = time.Second * 5
+)
+
+// cursorCounter - is used for unique cursor names
+var cursorCounter uint64
+
+// Cursor - iterator over server side cursor. Rows are fetched by pages of fetchSize rows. Next page is fetched
+// only when the current one is read, so slow consumer doesn't make the service buffer the whole result set.
+// Cursor holds the connection until Close is called or ctx passed to OpenCursor is canceled.
+// Cursor isn't intended for concurrent reading, but Close can be called from any goroutine
+type Cursor struct {
+	handler   *PostgresqlHandlerTX
+	ctx       context.Context
+	name      string
+	fetchSize int32
+	tx        pgx.Tx
+	conn      *pgxpool.Conn
+
+	mu        sync.Mutex
+	page      pgx.Rows
+	pageRows  int32
+	exhausted bool
+	closed    bool
+	err       error
+	done      chan struct{}
+}
+
+// OpenCursor - declares server side cursor for statement and returns iterator over its rows.
+// fetchSize - count of rows fetched per one round trip. If fetchSize <= 0, PgPoolConfig.CursorFetchSize is used.
+// If a transaction is in ctx, cursor is declared inside it. Otherwise, cursor gets its own read only transaction
+// on the connection acquired for read (replica is used if it's available). Cursor must be closed by Close
+func (handler *PostgresqlHandlerTX) OpenCursor(ctx context.Context, fetchSize int32, statement string, args ...interface{}) (*Cursor, error) {
+	if fetchSize <= 0 {
+		fetchSize = handler.pgPoolConfig.CursorFetchSize
+	}
+	if fetchSize <= 0 {
+		fetchSize = defaultCursorFetchSize
+	}
+	c := &Cursor{
+		handler:   handler,
+		ctx:       ctx,
+		name:      fmt.Sprintf("cursor_%d", atomic.AddUint64(&cursorCounter, 1)),
+		fetchSize: fetchSize,
+		done:      make(chan struct{}),
+	}
+
+	tx, err := handler.getTx(ctx)
+	if err != nil {
+		conn, e := handler.acquireForRead(ctx)
+		if e != nil {
+			handler.LogError(ctx, "Can't acquire connection from pool", e)
+			return nil, translateError(ctx, "OpenCursor", e)
+		}
+		tx, e = conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+		if e != nil {
+			conn.Release()
+			handler.LogError(ctx, "Can't start transaction for cursor", e)
+			return nil, translateError(ctx, "OpenCursor", e)
+		}
+		c.conn = conn
+	}
+	c.tx = tx
+
+	declare := "DECLARE " + pgx.Identifier{c.name}.Sanitize() + " NO SCROLL CURSOR FOR " + handler.clearStatement(statement)
+	declareCtx, st := handler.startTrace(ctx, "OpenCursor", handler.pgPoolConfig.ReadTimeout, declare, args)
+	_, err = tx.Exec(declareCtx, declare, args...)
+	if err = st.finish(unknownRows, err); err != nil {
+		handler.LogError(ctx, "Can't declare cursor", err)
+		if c.conn != nil {
+			// cursor isn't declared. Only own transaction must be finished
+			_ = c.release(ctx)
+		}
+		return nil, err
+	}
+
+	go c.watch()
+	return c, nil
+}
+
+// watch - closes cursor when ctx is canceled, so the connection isn't held by abandoned cursor
+func (c *Cursor) watch() {
+	select {
+	case <-c.ctx.Done():
+		_ = c.Close(context.Background())
+	case <-c.done:
+	}
+}
+
+// Next - prepares next row for reading by Scan. Returns false if there are no more rows or error occurs (see Err)
+func (c *Cursor) Next() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || c.err != nil {
+		return false
+	}
+	for {
+		if c.page == nil {
+			if c.exhausted {
+				return false
+			}
+			if err := c.fetch(); err != nil {
+				c.err = err
+				return false
+			}
+		}
+		if c.page.Next() {
+			c.pageRows++
+			return true
+		}
+		if err := c.page.Err(); err != nil {
+			c.err = err
+			c.page = nil
+			return false
+		}
+		// short page means that cursor is read to the end
+		c.exhausted = c.pageRows < c.fetchSize
+		c.page = nil
+	}
+}
+
+// fetch - fetches next page from cursor
+func (c *Cursor) fetch() error {
+	statement := fmt.Sprintf("FETCH %d FROM %s", c.fetchSize, pgx.Identifier{c.name}.Sanitize())
+	ctx, st := c.handler.startTrace(c.ctx, "FetchCursor", c.handler.pgPoolConfig.ReadTimeout, statement, nil)
+	rows, err := c.tx.Query(ctx, statement)
+	if err != nil {
+		err = st.finish(unknownRows, err)
+		c.handler.LogError(c.ctx, "Can't fetch from cursor", err)
+		return err
+	}
+	c.page = &tracedRows{Rows: rows, trace: st}
+	c.pageRows = 0
+	return nil
+}
+
+// Scan - reads values of the current row into dest
+func (c *Cursor) Scan(dest ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrCursorClosed
+	}
+	if c.page == nil {
+		return pgx.ErrNoRows
+	}
+	return c.page.Scan(dest...)
+}
+
+// Err - returns error occurred during iteration
+func (c *Cursor) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil && c.closed && c.ctx.Err() != nil {
+		return c.ctx.Err()
+	}
+	return c.err
+}
+
+// Close - closes cursor and releases the connection. It's safe to call Close several times
+func (c *Cursor) Close(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	if c.page != nil {
+		c.page.Close()
+		c.page = nil
+	}
+	return c.release(ctx)
+}
+
+// release - closes cursor on the server side. If cursor has its own transaction, transaction is finished
+// and the connection is returned into the pool
+func (c *Cursor) release(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, cursorCloseTimeout)
+	defer cancel()
+
+	var err error
+	if c.conn != nil {
+		// cursor is closed with the transaction
+		err = c.tx.Rollback(ctx)
+		c.conn.Release()
+	} else {
+		_, err = c.tx.Exec(ctx, "CLOSE "+pgx.Identifier{c.name}.Sanitize())
+	}
+	if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		c.handler.LogError(ctx, "Can't close cursor", err)
+		return err
+	}
+	return nil
+}
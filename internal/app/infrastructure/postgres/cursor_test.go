@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationPostgresqlHandlerTX_OpenCursor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	tests := []struct {
+		name      string
+		fetchSize int32
+		count     int
+	}{
+		{
+			name:      "OpenCursor. Case #1. Several pages",
+			fetchSize: 10,
+			count:     25,
+		},
+		{
+			name:      "OpenCursor. Case #2. Count is multiple of page size",
+			fetchSize: 5,
+			count:     10,
+		},
+		{
+			name:      "OpenCursor. Case #3. Empty result",
+			fetchSize: 5,
+			count:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, err := target.OpenCursor(ctx, tt.fetchSize, "SELECT generate_series(1, $1::int)", tt.count)
+			require.NoError(t, err)
+
+			var got []int
+			for c.Next() {
+				var val int
+				require.NoError(t, c.Scan(&val))
+				got = append(got, val)
+			}
+			assert.NoError(t, c.Err())
+			assert.NoError(t, c.Close(ctx))
+			assert.Len(t, got, tt.count)
+			for ind, val := range got {
+				assert.Equal(t, ind+1, val)
+			}
+			assert.ErrorIs(t, c.Scan(), ErrCursorClosed)
+		})
+	}
+}
+
+func TestIntegrationPostgresqlHandlerTX_OpenCursorInTx(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	err := target.WithTx(context.Background())(context.Background(), func(ctx context.Context) error {
+		c, err := target.OpenCursor(ctx, 2, "SELECT generate_series(1, 5)")
+		if err != nil {
+			return err
+		}
+		cnt := 0
+		for c.Next() {
+			cnt++
+		}
+		assert.Equal(t, 5, cnt)
+		return c.Close(ctx)
+	})
+	assert.NoError(t, err)
+}
+
+func TestIntegrationPostgresqlHandlerTX_OpenCursorCancel(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := target.OpenCursor(ctx, 10, "SELECT generate_series(1, 1000)")
+	require.NoError(t, err)
+	require.True(t, c.Next())
+
+	cancel()
+	// connection is released without Close
+	assert.Eventually(t, func() bool {
+		return target.pool.Stat().AcquiredConns() == 0
+	}, time.Second*5, time.Millisecond*50)
+	for c.Next() {
+	}
+	assert.ErrorIs(t, c.Err(), context.Canceled)
+	assert.NoError(t, c.Close(context.Background()))
+}
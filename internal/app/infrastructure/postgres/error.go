@@ -1,6 +1,14 @@
 package postgres
 
-import "errors"
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
 
 var (
 	// ErrTxNotFound - "can't get tx from context: nil value got" error
@@ -9,3 +17,56 @@ var (
 	// ErrTxTypeConversation - "can't get tx from context: conversion error"
 	ErrTxTypeConversation = errors.New("can't get tx from context: conversion error")
 )
+
+// TranslateError - translates postgres error into basedbhandler.DBError:
+//   - pgx.ErrNoRows - basedbhandler.ErrNotFound
+//   - unique violation - basedbhandler.ErrConflict
+//   - foreign key, check and not null violations - basedbhandler.ErrConstraintViolation
+//   - serialization failure, deadlock, lock timeout and connection errors - basedbhandler.ErrRetryable
+//
+// Other errors are returned as is. Original error is available by errors.Is / errors.As
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var (
+		dbErr      *basedbhandler.DBError
+		timeoutErr *StatementTimeoutError
+		pgErr      *pgconn.PgError
+	)
+	switch {
+	case errors.As(err, &dbErr), errors.As(err, &timeoutErr):
+		return err
+	case errors.Is(err, pgx.ErrNoRows):
+		return &basedbhandler.DBError{Kind: basedbhandler.ErrNotFound, Err: err}
+	case !errors.As(err, &pgErr):
+		return err
+	}
+
+	var kind error
+	switch {
+	case pgErr.Code == pgerrcode.UniqueViolation:
+		kind = basedbhandler.ErrConflict
+	case pgErr.Code == pgerrcode.ForeignKeyViolation,
+		pgErr.Code == pgerrcode.CheckViolation,
+		pgErr.Code == pgerrcode.NotNullViolation:
+		kind = basedbhandler.ErrConstraintViolation
+	case pgErr.Code == pgerrcode.SerializationFailure,
+		pgErr.Code == pgerrcode.DeadlockDetected,
+		pgErr.Code == pgerrcode.LockNotAvailable,
+		pgErr.Code == pgerrcode.TooManyConnections,
+		pgErr.Code == pgerrcode.AdminShutdown,
+		pgErr.Code == pgerrcode.CannotConnectNow,
+		pgerrcode.IsConnectionException(pgErr.Code):
+		kind = basedbhandler.ErrRetryable
+	default:
+		return err
+	}
+	return &basedbhandler.DBError{Kind: kind, Constraint: pgErr.ConstraintName, Err: err}
+}
+
+// translateError - converts timeout errors into StatementTimeoutError and translates other errors by TranslateError.
+// ctx is the context of the caller without statement timeout (see timeoutError)
+func translateError(ctx context.Context, operation string, err error) error {
+	return TranslateError(timeoutError(ctx, operation, err))
+}
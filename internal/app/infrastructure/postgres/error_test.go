@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+func TestTranslateError(t *testing.T) {
+	errSome := errors.New("some error")
+	tests := []struct {
+		name           string
+		err            error
+		wantKind       error
+		wantConstraint string
+	}{
+		{
+			name: "TranslateError. Case #1. Nil error",
+			err:  nil,
+		},
+		{
+			name: "TranslateError. Case #2. Not postgres error",
+			err:  errSome,
+		},
+		{
+			name:     "TranslateError. Case #3. No rows",
+			err:      fmt.Errorf("scan: %w", pgx.ErrNoRows),
+			wantKind: basedbhandler.ErrNotFound,
+		},
+		{
+			name:           "TranslateError. Case #4. Unique violation",
+			err:            &pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: "users_pk"},
+			wantKind:       basedbhandler.ErrConflict,
+			wantConstraint: "users_pk",
+		},
+		{
+			name:           "TranslateError. Case #5. Foreign key violation",
+			err:            &pgconn.PgError{Code: pgerrcode.ForeignKeyViolation, ConstraintName: "orders_user_fk"},
+			wantKind:       basedbhandler.ErrConstraintViolation,
+			wantConstraint: "orders_user_fk",
+		},
+		{
+			name:           "TranslateError. Case #6. Check violation",
+			err:            &pgconn.PgError{Code: pgerrcode.CheckViolation, ConstraintName: "amount_check"},
+			wantKind:       basedbhandler.ErrConstraintViolation,
+			wantConstraint: "amount_check",
+		},
+		{
+			name:     "TranslateError. Case #7. Serialization failure",
+			err:      &pgconn.PgError{Code: pgerrcode.SerializationFailure},
+			wantKind: basedbhandler.ErrRetryable,
+		},
+		{
+			name:     "TranslateError. Case #8. Deadlock",
+			err:      &pgconn.PgError{Code: pgerrcode.DeadlockDetected},
+			wantKind: basedbhandler.ErrRetryable,
+		},
+		{
+			name:     "TranslateError. Case #9. Lock not available",
+			err:      &pgconn.PgError{Code: pgerrcode.LockNotAvailable},
+			wantKind: basedbhandler.ErrRetryable,
+		},
+		{
+			name: "TranslateError. Case #10. Other postgres error",
+			err:  &pgconn.PgError{Code: pgerrcode.SyntaxError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TranslateError(tt.err)
+			if tt.wantKind == nil {
+				assert.Equal(t, tt.err, got)
+				return
+			}
+			assert.ErrorIs(t, got, tt.wantKind)
+			assert.ErrorIs(t, got, tt.err)
+			var dbErr *basedbhandler.DBError
+			if assert.ErrorAs(t, got, &dbErr) {
+				assert.Equal(t, tt.wantConstraint, dbErr.Constraint)
+			}
+			// translation is idempotent
+			assert.Equal(t, got, TranslateError(got))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// ErrPoolNotReady - "database connection isn't ready" error
+var ErrPoolNotReady = errors.New("database connection isn't ready")
+
+const defaultReadinessCheckPeriod = time.Second * 5
+
+// connectPool - connects pool. If the database is unavailable, pool is created in lazy mode, so the service starts
+// in degraded state and the pool connects as soon as the database is available (see monitor)
+func (handler *PostgresqlHandlerTX) connectPool(ctx context.Context, poolConfig *pgxpool.Config) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+	if err == nil {
+		// lazy pool isn't checked yet. Readiness is set by monitor
+		if !poolConfig.LazyConnect {
+			handler.setReady(ctx, true)
+		}
+		return pool, nil
+	}
+	if ctx.Err() != nil {
+		return nil, err
+	}
+	handler.Log(ctx).Warn().Err(err).Msg("database is unavailable. starting in degraded state")
+	poolConfig.LazyConnect = true
+	return pgxpool.ConnectConfig(ctx, poolConfig)
+}
+
+// Ready - returns true if the last health check of the primary pool succeeded
+func (handler *PostgresqlHandlerTX) Ready() bool {
+	return atomic.LoadInt32(&handler.ready) == 1
+}
+
+// HealthCheck - returns ErrPoolNotReady if the last health check of the primary pool failed.
+// It doesn't touch the database, the state is maintained by background monitor
+func (handler *PostgresqlHandlerTX) HealthCheck(_ context.Context) error {
+	if !handler.Ready() {
+		return ErrPoolNotReady
+	}
+	return nil
+}
+
+// Ping - pings the primary pool. Unlike HealthCheck it goes to the database, it's used for latency measurement
+func (handler *PostgresqlHandlerTX) Ping(ctx context.Context) error {
+	return handler.pool.Ping(ctx)
+}
+
+// WaitReady - blocks until the primary pool is ready or ctx is canceled
+func (handler *PostgresqlHandlerTX) WaitReady(ctx context.Context) error {
+	ticker := time.NewTicker(time.Millisecond * 100)
+	defer ticker.Stop()
+	for !handler.Ready() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (handler *PostgresqlHandlerTX) setReady(ctx context.Context, ready bool) {
+	var val int32
+	if ready {
+		val = 1
+	}
+	if atomic.SwapInt32(&handler.ready, val) == val {
+		return
+	}
+	if ready {
+		handler.LogInfo(ctx, "database connection is ready")
+	} else {
+		handler.LogWarn(ctx, "database connection is lost")
+	}
+}
+
+// startMonitor - starts background health check of the primary pool
+func (handler *PostgresqlHandlerTX) startMonitor() {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler.monitorCancel = cancel
+	handler.monitorDone = make(chan struct{})
+	go handler.monitor(ctx)
+}
+
+// monitor - pings the primary pool each ReadinessCheckPeriod while it is ready. When ping fails, pool is pinged
+// with exponential backoff (ReconnectInterval..MaxReconnectInterval) until the connection is restored
+func (handler *PostgresqlHandlerTX) monitor(ctx context.Context) {
+	defer close(handler.monitorDone)
+	period := handler.pgPoolConfig.ReadinessCheckPeriod
+	if period <= 0 {
+		period = defaultReadinessCheckPeriod
+	}
+	backoff := infrastructure.Backoff{
+		Initial: handler.pgPoolConfig.ReconnectInterval,
+		Max:     handler.pgPoolConfig.MaxReconnectInterval,
+	}
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, period)
+		err := handler.pool.Ping(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		var delay time.Duration
+		if err == nil {
+			handler.setReady(ctx, true)
+			backoff.Reset()
+			delay = period
+		} else {
+			handler.setReady(ctx, false)
+			delay = backoff.Next()
+			handler.Log(ctx).Debug().Err(err).Dur("delay", delay).Msg("database health check failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// stopMonitor - stops background health check
+func (handler *PostgresqlHandlerTX) stopMonitor() {
+	if handler.monitorCancel == nil {
+		return
+	}
+	handler.monitorCancel()
+	<-handler.monitorDone
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+
+	"go-service-template/internal/app/infrastructure"
+)
+
+var (
+	// ErrBadSequenceName - "bad sequence name" error
+	ErrBadSequenceName = errors.New("bad sequence name")
+
+	// ErrNoIDsAllocated - "no ids allocated" error. Sequence returned no values
+	ErrNoIDsAllocated = errors.New("no ids allocated")
+)
+
+const (
+	// nextIDsStatement - reserves block of ids. Sequence name is passed as parameter, so it can't be used for injection
+	nextIDsStatement = "SELECT nextval($1::regclass) FROM generate_series(1, $2)"
+)
+
+// sequenceNameRegexp - allowed sequence names: identifier or schema.identifier
+var sequenceNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]{0,62}(\.[A-Za-z_][A-Za-z0-9_$]{0,62})?$`)
+
+// validateSequenceName - checks that sequenceName is a plain (optionally schema qualified) identifier
+func validateSequenceName(sequenceName string) error {
+	if !sequenceNameRegexp.MatchString(sequenceName) {
+		return ErrBadSequenceName
+	}
+	return nil
+}
+
+// fetchIDsFunc - func type for reserving n ids from the sequence
+type fetchIDsFunc func(ctx context.Context, sequenceName string, n int) ([]int64, error)
+
+// IDAllocator - allocates ids from sequences by blocks. One round trip reserves blockSize ids,
+// they are handed out from memory. Ids aren't reused: ids reserved but not handed out before restart
+// are lost, so allocated ids are unique but may have gaps
+type IDAllocator struct {
+	infrastructure.SugarLogger
+	fetch     fetchIDsFunc
+	blockSize int
+
+	mu     sync.Mutex
+	blocks map[string]*idBlock
+}
+
+// idBlock - ids reserved for one sequence
+type idBlock struct {
+	mu  sync.Mutex
+	ids []int64
+}
+
+func newIDAllocator(fetch fetchIDsFunc, blockSize int) *IDAllocator {
+	if blockSize < 1 {
+		blockSize = 1
+	}
+	return &IDAllocator{
+		fetch:     fetch,
+		blockSize: blockSize,
+		blocks:    make(map[string]*idBlock),
+	}
+}
+
+// NextID - returns next id for the sequence. Ids are reserved separately for each tenant from the context
+func (a *IDAllocator) NextID(ctx context.Context, sequenceName string) (int64, error) {
+	if err := validateSequenceName(sequenceName); err != nil {
+		a.LogError(ctx, "can't get next value from sequence "+sequenceName, err)
+		return 0, err
+	}
+	// unqualified sequence name is resolved by search_path, so each tenant has its own sequence
+	key := sequenceName
+	if tenant, ok := infrastructure.TenantFromContext(ctx); ok {
+		key = tenant + "/" + sequenceName
+	}
+	block := a.block(key)
+
+	block.mu.Lock()
+	defer block.mu.Unlock()
+	if len(block.ids) == 0 {
+		ids, err := a.fetch(ctx, sequenceName, a.blockSize)
+		if err != nil {
+			a.LogError(ctx, "can't reserve ids from sequence "+sequenceName, err)
+			return 0, err
+		}
+		if len(ids) == 0 {
+			a.LogError(ctx, "can't reserve ids from sequence "+sequenceName, ErrNoIDsAllocated)
+			return 0, ErrNoIDsAllocated
+		}
+		block.ids = ids
+	}
+	id := block.ids[0]
+	block.ids = block.ids[1:]
+	return id, nil
+}
+
+func (a *IDAllocator) block(sequenceName string) *idBlock {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	block, ok := a.blocks[sequenceName]
+	if !ok {
+		block = &idBlock{}
+		a.blocks[sequenceName] = block
+	}
+	return block
+}
+
+// fetchIDs - reserves n ids from the sequence. Query is executed on the primary outside any transaction from context:
+// nextval isn't transactional, and reserved block is shared between callers
+func (handler *PostgresqlHandlerTX) fetchIDs(ctx context.Context, sequenceName string, n int) (ids []int64, err error) {
+	ctx, st := handler.startTrace(ctx, "GetNextID", handler.pgPoolConfig.WriteTimeout, nextIDsStatement, []interface{}{sequenceName, n})
+	defer func() {
+		err = st.finish(int64(len(ids)), err)
+	}()
+
+	rows, err := handler.pool.Query(ctx, nextIDsStatement, sequenceName, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids = make([]int64, 0, n)
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateSequenceName(t *testing.T) {
+	tests := []struct {
+		name         string
+		sequenceName string
+		wantErr      bool
+	}{
+		{name: "Case #1. Plain name", sequenceName: "kafka_in_error_messages_sq", wantErr: false},
+		{name: "Case #2. Schema qualified name", sequenceName: "test.kafka_in_error_messages_sq", wantErr: false},
+		{name: "Case #3. Empty name", sequenceName: "", wantErr: true},
+		{name: "Case #4. Injection", sequenceName: "sq'); drop table test_table; --", wantErr: true},
+		{name: "Case #5. Quoted name", sequenceName: `"sq"`, wantErr: true},
+		{name: "Case #6. Leading digit", sequenceName: "1sq", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSequenceName(tt.sequenceName)
+			assert.Equal(t, tt.wantErr, err != nil, "validateSequenceName() error = %v, wantErr %v", err, tt.wantErr)
+		})
+	}
+}
+
+func TestIDAllocator_NextID(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		last    int64
+		fetches int
+	)
+	fetch := func(ctx context.Context, sequenceName string, n int) ([]int64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		ids := make([]int64, n)
+		for i := range ids {
+			last++
+			ids[i] = last
+		}
+		return ids, nil
+	}
+	a := newIDAllocator(fetch, 10)
+
+	const goroutines, perGoroutine = 8, 25
+	res := make(chan int64, goroutines*perGoroutine)
+	wg := sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				id, err := a.NextID(context.Background(), "test_sq")
+				assert.NoError(t, err)
+				res <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(res)
+
+	unique := make(map[int64]struct{})
+	for id := range res {
+		unique[id] = struct{}{}
+	}
+	assert.Len(t, unique, goroutines*perGoroutine)
+	assert.Equal(t, goroutines*perGoroutine/10, fetches)
+}
+
+func TestIDAllocator_NextIDError(t *testing.T) {
+	fetchErr := errors.New("db error")
+	a := newIDAllocator(func(ctx context.Context, sequenceName string, n int) ([]int64, error) {
+		return nil, fetchErr
+	}, 10)
+
+	_, err := a.NextID(context.Background(), "test_sq")
+	assert.ErrorIs(t, err, fetchErr)
+
+	_, err = a.NextID(context.Background(), "bad'name")
+	assert.ErrorIs(t, err, ErrBadSequenceName)
+
+	a = newIDAllocator(func(ctx context.Context, sequenceName string, n int) ([]int64, error) {
+		return nil, nil
+	}, 10)
+	_, err = a.NextID(context.Background(), "test_sq")
+	assert.ErrorIs(t, err, ErrNoIDsAllocated)
+}
+
+func TestIntegrationPostgresqlHandlerTX_GetNextID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := context.Background()
+	first, err := target.GetNextID(ctx, "kafka_in_error_messages_sq")
+	require.NoError(t, err)
+	second, err := target.GetNextID(ctx, "kafka_in_error_messages_sq")
+	require.NoError(t, err)
+	assert.Greater(t, second, first)
+
+	_, err = target.GetNextID(ctx, "unknown_sq")
+	assert.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+const (
+	// idempotencyCleanupInterval - expired keys are deleted once per interval
+	idempotencyCleanupInterval = time.Hour
+
+	// the key is locked if it doesn't exist or expired, otherwise the stored request is returned
+	lockIdempotencyKeyStatement = `WITH locked AS (
+			INSERT INTO idempotency_keys AS k (key, fingerprint, expire_time, lock_id)
+			VALUES ($1, $2, now() + $3 * interval '1 millisecond', $4)
+			ON CONFLICT (key) DO UPDATE SET fingerprint = excluded.fingerprint, status = NULL, headers = NULL, body = NULL,
+				create_time = now(), expire_time = excluded.expire_time, lock_id = excluded.lock_id
+			WHERE k.expire_time < now()
+			RETURNING true AS locked)
+		SELECT true, '', NULL::integer, NULL::jsonb, NULL::bytea FROM locked
+		UNION ALL
+		SELECT false, fingerprint, status, headers, body FROM idempotency_keys
+		WHERE key = $1 AND NOT EXISTS (SELECT 1 FROM locked)`
+	// the response is stored only by the request holding the key. The lock may expire and be taken by a retry
+	completeIdempotencyKeyStatement = `UPDATE idempotency_keys SET status = $3, headers = $4, body = $5,
+		expire_time = now() + $6 * interval '1 millisecond' WHERE key = $1 AND lock_id = $2 AND status IS NULL`
+	unlockIdempotencyKeyStatement  = `DELETE FROM idempotency_keys WHERE key = $1 AND lock_id = $2 AND status IS NULL`
+	cleanupIdempotencyKeyStatement = `DELETE FROM idempotency_keys WHERE expire_time < now()`
+)
+
+// IdempotencyStore - requests with idempotency keys in idempotency_keys table. Keys are shared by all instances of the service.
+// The table is in the default schema, keys of tenants are scoped by the caller
+type IdempotencyStore struct {
+	infrastructure.SugarLogger
+	db          *PostgresqlHandlerTX
+	lastCleanup int64
+}
+
+// NewIdempotencyStore returns new IdempotencyStore
+func NewIdempotencyStore(db *PostgresqlHandlerTX) *IdempotencyStore {
+	var target IdempotencyStore
+	target.db = db
+	target.lastCleanup = time.Now().UnixNano()
+	return &target
+}
+
+// Lock - reserves the key for the request. It returns stored response if the request was completed
+func (s *IdempotencyStore) Lock(ctx context.Context, key, lockID, fingerprint string, lockTimeout time.Duration) (*infrastructure.IdempotentResponse, error) {
+	s.cleanup(ctx)
+	row, err := s.db.QueryRow(WithPrimary(withoutTenant(ctx)), lockIdempotencyKeyStatement, key, fingerprint, lockTimeout.Milliseconds(), lockID)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		locked        bool
+		storedPrint   string
+		status        *int
+		headers, body []byte
+	)
+	err = row.Scan(&locked, &storedPrint, &status, &headers, &body)
+	switch {
+	// the key was locked by concurrent request after the statement snapshot
+	case errors.Is(err, basedbhandler.ErrNotFound):
+		return nil, infrastructure.ErrIdempotencyKeyInUse
+	case err != nil:
+		return nil, err
+	case locked:
+		return nil, nil
+	case storedPrint != fingerprint:
+		return nil, infrastructure.ErrIdempotencyKeyReused
+	case status == nil:
+		return nil, infrastructure.ErrIdempotencyKeyInUse
+	}
+
+	res := infrastructure.IdempotentResponse{Status: *status, Header: http.Header{}, Body: body}
+	if len(headers) > 0 {
+		if err = json.Unmarshal(headers, &res.Header); err != nil {
+			return nil, fmt.Errorf("can't unmarshal stored headers: %w", err)
+		}
+	}
+	return &res, nil
+}
+
+// Complete - stores response of the request holding the key
+func (s *IdempotencyStore) Complete(ctx context.Context, key, lockID string, response infrastructure.IdempotentResponse, ttl time.Duration) error {
+	headers, err := json.Marshal(response.Header)
+	if err != nil {
+		return err
+	}
+	return s.db.Execute(withoutTenant(ctx), completeIdempotencyKeyStatement, key, lockID, response.Status, string(headers), response.Body, ttl.Milliseconds())
+}
+
+// Unlock - deletes the key held by the request in progress
+func (s *IdempotencyStore) Unlock(ctx context.Context, key, lockID string) error {
+	return s.db.Execute(withoutTenant(ctx), unlockIdempotencyKeyStatement, key, lockID)
+}
+
+// cleanup - deletes expired keys in background once per idempotencyCleanupInterval
+func (s *IdempotencyStore) cleanup(ctx context.Context) {
+	last := atomic.LoadInt64(&s.lastCleanup)
+	now := time.Now().UnixNano()
+	if time.Duration(now-last) < idempotencyCleanupInterval || !atomic.CompareAndSwapInt64(&s.lastCleanup, last, now) {
+		return
+	}
+	l := s.Log(ctx)
+	go func() {
+		ctx := context.WithValue(context.Background(), infrastructure.CtxKeyLogger{}, l)
+		if err := s.db.Execute(ctx, cleanupIdempotencyKeyStatement); err != nil {
+			l.Warn().Err(err).Msg("can't delete expired idempotency keys")
+		}
+	}()
+}
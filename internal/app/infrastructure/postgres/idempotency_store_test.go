@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/infrastructure"
+)
+
+func TestIntegrationIdempotencyStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	store := NewIdempotencyStore(target)
+	key := "test:" + time.Now().String()
+
+	res, err := store.Lock(ctx, key, "l1", "print", time.Minute)
+	require.NoError(t, err, "Lock. Case #1. New key is locked")
+	assert.Nil(t, res)
+
+	_, err = store.Lock(ctx, key, "l2", "print", time.Minute)
+	assert.ErrorIs(t, err, infrastructure.ErrIdempotencyKeyInUse, "Lock. Case #2. Request is in progress")
+
+	_, err = store.Lock(ctx, key, "l3", "other", time.Minute)
+	assert.ErrorIs(t, err, infrastructure.ErrIdempotencyKeyReused, "Lock. Case #3. Other request")
+
+	response := infrastructure.IdempotentResponse{
+		Status: http.StatusCreated,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   []byte(`{"id":1}`),
+	}
+	require.NoError(t, store.Complete(ctx, key, "l1", response, time.Minute))
+	res, err = store.Lock(ctx, key, "l4", "print", time.Minute)
+	require.NoError(t, err, "Lock. Case #4. Stored response")
+	assert.Equal(t, &response, res)
+
+	key2 := key + ":unlock"
+	_, err = store.Lock(ctx, key2, "l1", "print", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.Unlock(ctx, key2, "l1"))
+	res, err = store.Lock(ctx, key2, "l2", "print", time.Minute)
+	require.NoError(t, err, "Lock. Case #5. Unlocked key is locked again")
+	assert.Nil(t, res)
+
+	key3 := key + ":expired"
+	_, err = store.Lock(ctx, key3, "l1", "print", time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond * 10)
+	res, err = store.Lock(ctx, key3, "l2", "other", time.Minute)
+	require.NoError(t, err, "Lock. Case #6. Expired key is locked by other request")
+	assert.Nil(t, res)
+
+	// the first request finished after its lock expired
+	require.NoError(t, store.Complete(ctx, key3, "l1", response, time.Minute))
+	require.NoError(t, store.Unlock(ctx, key3, "l1"))
+	_, err = store.Lock(ctx, key3, "l3", "other", time.Minute)
+	assert.ErrorIs(t, err, infrastructure.ErrIdempotencyKeyInUse, "Lock. Case #7. Late request doesn't change the key of other request")
+	other := infrastructure.IdempotentResponse{Status: http.StatusOK, Header: http.Header{}, Body: []byte(`{"id":2}`)}
+	require.NoError(t, store.Complete(ctx, key3, "l2", other, time.Minute))
+	res, err = store.Lock(ctx, key3, "l3", "other", time.Minute)
+	require.NoError(t, err, "Lock. Case #8. Response of the request holding the key")
+	assert.Equal(t, &other, res)
+}
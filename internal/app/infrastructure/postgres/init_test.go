@@ -18,6 +18,9 @@ var (
 	target *PostgresqlHandlerTX
 	dsn    string
 	log    *infrastructure.Logger
+
+	// serviceDSN - dsn of the service user. It has DML privileges only
+	serviceDSN string
 )
 
 func initDatabase(ctx context.Context) {
@@ -61,6 +64,7 @@ func TestMain(m *testing.M) {
 		}
 		defer db.Close(ctx)
 		dsn = fmt.Sprintf("postgres://%s:%s@127.0.0.1:%d/%s?sslmode=disable", cfg.OwnerSchema, cfg.OwnerSchemaPass, db.Port(ctx), cfg.DatabaseName)
+		serviceDSN = db.ConnectionString(ctx)
 
 		initDatabase(ctx)
 		target, err = NewPostgresqlHandlerTX(ctx, dsn, PgPoolConfig{})
@@ -0,0 +1,451 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+var (
+	// ErrJobQueueBadParam - "bad job queue param" error
+	ErrJobQueueBadParam = errors.New("bad job queue param")
+
+	// ErrJobQueueStarted - "job queue is already started" error
+	ErrJobQueueStarted = errors.New("job queue is already started")
+
+	// ErrJobDuplicate - "job with the same unique key is already in the queue" error
+	ErrJobDuplicate = errors.New("job with the same unique key is already in the queue")
+
+	// ErrJobHandlerNotFound - "job handler not found" error
+	ErrJobHandlerNotFound = errors.New("job handler not found")
+)
+
+const (
+	// DefaultJobQueue - queue name used if queue isn't set by WithJobQueue
+	DefaultJobQueue = "default"
+
+	defaultJobMaxAttempts      = 10
+	defaultJobWorkers          = 4
+	defaultJobPollInterval     = time.Second
+	defaultJobRetryInterval    = time.Second * 10
+	defaultJobMaxRetryInterval = time.Hour
+	defaultJobTimeout          = time.Minute * 15
+
+	// jobResultTimeout - timeout of saving the result of the job attempt
+	jobResultTimeout = time.Second * 10
+
+	enqueueJobStatement = `INSERT INTO jobs (queue, kind, payload, unique_key, max_attempts, run_at, tenant)
+		VALUES ($1, $2, $3, $4, $5, coalesce($6, now()), $7)
+		ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL DO NOTHING
+		RETURNING id`
+	fetchJobStatement = `UPDATE jobs SET status = 'running', attempt = attempt + 1, locked_at = now(), locked_by = $2
+		WHERE id = (SELECT id FROM jobs
+			WHERE queue = ANY($1) AND status = 'pending' AND run_at <= now()
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id, queue, kind, payload, coalesce(unique_key, ''), attempt, max_attempts, run_at, create_time,
+			coalesce(tenant, '')`
+	// result is saved only by the worker holding the attempt. The job may be rescued and taken by another worker
+	completeJobStatement = `DELETE FROM jobs WHERE id = $1 AND locked_by = $2 AND attempt = $3`
+	retryJobStatement    = `UPDATE jobs SET status = 'pending', run_at = now() + $2 * interval '1 millisecond',
+		locked_at = NULL, locked_by = NULL, last_error = $3
+		WHERE id = $1 AND locked_by = $4 AND attempt = $5`
+	killJobStatement = `WITH j AS (DELETE FROM jobs WHERE id = $1 AND locked_by = $3 AND attempt = $4 RETURNING *)
+		INSERT INTO jobs_dead (id, queue, kind, payload, unique_key, attempt, max_attempts, last_error, create_time, tenant)
+		SELECT id, queue, kind, payload, unique_key, attempt, max_attempts, $2, create_time, tenant FROM j`
+	// abandoned jobs without attempts left are moved into jobs_dead, so a job crashing its worker isn't retried forever
+	rescueJobsStatement = `WITH abandoned AS (SELECT id FROM jobs
+			WHERE status = 'running' AND locked_at < now() - $1 * interval '1 millisecond'
+			FOR UPDATE SKIP LOCKED),
+		dead AS (DELETE FROM jobs WHERE id IN (SELECT id FROM abandoned) AND attempt >= max_attempts RETURNING *),
+		killed AS (INSERT INTO jobs_dead (id, queue, kind, payload, unique_key, attempt, max_attempts, last_error, create_time, tenant)
+			SELECT id, queue, kind, payload, unique_key, attempt, max_attempts,
+				'job was abandoned by worker ' || coalesce(locked_by, ''), create_time, tenant FROM dead)
+		UPDATE jobs SET status = 'pending', locked_at = NULL, locked_by = NULL,
+			last_error = 'job was abandoned by worker ' || coalesce(locked_by, '')
+		WHERE id IN (SELECT id FROM abandoned) AND attempt < max_attempts`
+)
+
+// Job - job taken by a worker
+type Job struct {
+	ID          int64
+	Queue       string
+	Kind        string
+	Payload     json.RawMessage
+	UniqueKey   string
+	Attempt     int32
+	MaxAttempts int32
+	RunAt       time.Time
+	CreateTime  time.Time
+	// Tenant - tenant of the context which enqueued the job. Handler context has the same tenant
+	Tenant string
+}
+
+// JobHandleFunc - func type for job handlers. If handler returns error, job is retried with backoff
+// until Job.MaxAttempts are exhausted. Then job is moved into jobs_dead table
+type JobHandleFunc func(ctx context.Context, job Job) error
+
+// JobOption - option of the enqueued job
+type JobOption func(o *jobOptions)
+
+type jobOptions struct {
+	queue       string
+	uniqueKey   *string
+	maxAttempts int32
+	runAt       *time.Time
+}
+
+// WithJobQueue - puts job into the queue. DefaultJobQueue is used by default
+func WithJobQueue(queue string) JobOption {
+	return func(o *jobOptions) { o.queue = queue }
+}
+
+// WithJobUniqueKey - only one job of the kind with the key can be in the queue. Enqueue returns ErrJobDuplicate for the second one
+func WithJobUniqueKey(key string) JobOption {
+	return func(o *jobOptions) { o.uniqueKey = &key }
+}
+
+// WithJobMaxAttempts - max count of attempts. Default is 10
+func WithJobMaxAttempts(n int32) JobOption {
+	return func(o *jobOptions) { o.maxAttempts = n }
+}
+
+// WithJobRunAt - job isn't started before runAt
+func WithJobRunAt(runAt time.Time) JobOption {
+	return func(o *jobOptions) { o.runAt = &runAt }
+}
+
+// Enqueue - adds job into the queue. payload is marshaled into JSON. If a transaction is in ctx, job is added
+// inside it, so job becomes visible to workers only after commit. Returns id of the job.
+// Jobs table is shared by tenants: the tenant from ctx is stored with the job and restored for the handler
+func (handler *PostgresqlHandlerTX) Enqueue(ctx context.Context, kind string, payload interface{}, opts ...JobOption) (int64, error) {
+	o := jobOptions{queue: DefaultJobQueue, maxAttempts: defaultJobMaxAttempts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if kind == "" || o.queue == "" || o.maxAttempts <= 0 {
+		handler.LogError(ctx, "bad job params", ErrJobQueueBadParam)
+		return 0, ErrJobQueueBadParam
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		handler.LogError(ctx, "can't marshal job payload", err)
+		return 0, err
+	}
+
+	var tenant *string
+	if t, ok := infrastructure.TenantFromContext(ctx); ok {
+		tenant = &t
+	}
+	row, err := handler.QueryRow(WithPrimary(withoutTenant(ctx)), enqueueJobStatement, o.queue, kind, data, o.uniqueKey, o.maxAttempts, o.runAt, tenant)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	if err = row.Scan(&id); err != nil {
+		if errors.Is(err, basedbhandler.ErrNotFound) {
+			return 0, ErrJobDuplicate
+		}
+		handler.LogError(ctx, "can't enqueue job", err)
+		return 0, err
+	}
+	return id, nil
+}
+
+// JobQueueConfig - struct for job workers params
+type JobQueueConfig struct {
+	// Enabled - start job workers
+	Enabled bool `env:"JOB_QUEUE_ENABLED" yaml:"enabled"`
+
+	// Queues - list of queues processed by workers. Default is DefaultJobQueue
+	Queues []string `yaml:"queues"`
+
+	// Workers - count of concurrent workers
+	Workers int `yaml:"workers"`
+
+	// PollInterval - delay between queue polls when there are no jobs ready to run
+	PollInterval time.Duration `yaml:"pollInterval"`
+
+	// RetryInterval - delay before the first retry of failed job. Delay is doubled after each failed attempt
+	RetryInterval time.Duration `yaml:"retryInterval"`
+
+	// MaxRetryInterval - max delay between retries
+	MaxRetryInterval time.Duration `yaml:"maxRetryInterval"`
+
+	// JobTimeout - max duration of one attempt. Jobs running longer (e.g. taken by crashed instance) are returned into the queue
+	JobTimeout time.Duration `yaml:"jobTimeout"`
+}
+
+// JobQueue - pool of workers processing jobs from postgres. Jobs are taken with FOR UPDATE SKIP LOCKED,
+// so any count of workers and instances can process the same queues
+type JobQueue struct {
+	infrastructure.SugarLogger
+	db       *PostgresqlHandlerTX
+	cfg      JobQueueConfig
+	name     string
+	handlers map[string]JobHandleFunc
+
+	mu      sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewJobQueue returns new JobQueue
+func NewJobQueue(ctx context.Context, db *PostgresqlHandlerTX, cfg JobQueueConfig) (*JobQueue, error) {
+	var target JobQueue
+	target.db = db
+	target.cfg = cfg
+
+	if err := target.Init(ctx); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Init - func for initialisation
+func (q *JobQueue) Init(ctx context.Context) error {
+	if q.db == nil {
+		q.LogError(ctx, "db handler is not set", ErrJobQueueBadParam)
+		return ErrJobQueueBadParam
+	}
+	if len(q.cfg.Queues) == 0 {
+		q.cfg.Queues = []string{DefaultJobQueue}
+	}
+	if q.cfg.Workers <= 0 {
+		q.cfg.Workers = defaultJobWorkers
+	}
+	if q.cfg.PollInterval <= 0 {
+		q.cfg.PollInterval = defaultJobPollInterval
+	}
+	if q.cfg.RetryInterval <= 0 {
+		q.cfg.RetryInterval = defaultJobRetryInterval
+	}
+	if q.cfg.MaxRetryInterval < q.cfg.RetryInterval {
+		q.cfg.MaxRetryInterval = defaultJobMaxRetryInterval
+	}
+	if q.cfg.JobTimeout <= 0 {
+		q.cfg.JobTimeout = defaultJobTimeout
+	}
+	host, _ := os.Hostname()
+	q.name = fmt.Sprintf("%s-%d", host, os.Getpid())
+	q.handlers = make(map[string]JobHandleFunc)
+	q.done = make(chan struct{})
+	return nil
+}
+
+// AddHandler - adds handler for jobs of the kind. Must be called before Start
+func (q *JobQueue) AddHandler(ctx context.Context, kind string, h JobHandleFunc) error {
+	if kind == "" {
+		q.LogError(ctx, "job kind is empty", ErrJobQueueBadParam)
+		return ErrJobQueueBadParam
+	}
+	if h == nil {
+		q.LogError(ctx, "can't find any handler", ErrJobQueueBadParam)
+		return ErrJobQueueBadParam
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		q.LogError(ctx, "can't add handler", ErrJobQueueStarted)
+		return ErrJobQueueStarted
+	}
+	q.handlers[kind] = h
+	return nil
+}
+
+// AddJobHandler - adds typed handler for jobs of the kind. Job payload is unmarshaled into T.
+// Jobs with payload which can't be unmarshaled fail as any other failed job
+func AddJobHandler[T any](ctx context.Context, q *JobQueue, kind string, h func(ctx context.Context, job Job, payload T) error) error {
+	if h == nil {
+		q.LogError(ctx, "can't find any handler", ErrJobQueueBadParam)
+		return ErrJobQueueBadParam
+	}
+	return q.AddHandler(ctx, kind, func(ctx context.Context, job Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("can't unmarshal job payload: %w", err)
+		}
+		return h(ctx, job, payload)
+	})
+}
+
+// Start - starts workers. Blocks until ctx is canceled or Close is called. Running jobs are finished before return
+func (q *JobQueue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	if q.started {
+		q.mu.Unlock()
+		return ErrJobQueueStarted
+	}
+	q.started = true
+	ctx, q.cancel = context.WithCancel(ctx)
+	q.mu.Unlock()
+	defer close(q.done)
+
+	wg := &sync.WaitGroup{}
+	for ind := 0; ind < q.cfg.Workers; ind++ {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			q.work(ctx, worker)
+		}(fmt.Sprintf("%s-%d", q.name, ind))
+	}
+	q.LogInfo(ctx, "job queue up and running")
+
+	ticker := time.NewTicker(q.cfg.JobTimeout / 2)
+	defer ticker.Stop()
+	for {
+		q.rescue(ctx)
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			q.LogInfo(ctx, "job queue terminating: context canceled")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// work - worker loop. Takes jobs one by one. Waits PollInterval if there are no jobs ready to run
+func (q *JobQueue) work(ctx context.Context, worker string) {
+	for ctx.Err() == nil {
+		job, err := q.fetch(ctx, worker)
+		if err == nil {
+			q.process(worker, job)
+			continue
+		}
+		if !errors.Is(err, basedbhandler.ErrNotFound) && ctx.Err() == nil {
+			q.Log(ctx).Error().Err(err).Str("worker", worker).Msg("can't fetch job")
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(q.cfg.PollInterval):
+		}
+	}
+}
+
+// fetch - takes the next job ready to run. basedbhandler.ErrNotFound is returned if there are no such jobs
+func (q *JobQueue) fetch(ctx context.Context, worker string) (Job, error) {
+	var (
+		job     Job
+		payload []byte
+	)
+	row, err := q.db.QueryRow(WithPrimary(ctx), fetchJobStatement, q.cfg.Queues, worker)
+	if err != nil {
+		return job, err
+	}
+	err = row.Scan(&job.ID, &job.Queue, &job.Kind, &payload, &job.UniqueKey, &job.Attempt, &job.MaxAttempts, &job.RunAt, &job.CreateTime,
+		&job.Tenant)
+	job.Payload = payload
+	return job, err
+}
+
+// process - runs job handler and saves the result. Job context isn't canceled on shutdown,
+// so the started attempt is finished within JobTimeout
+func (q *JobQueue) process(worker string, job Job) {
+	// each attempt gets its own requestID
+	requestID := infrastructure.GenerateID()
+	lc := infrastructure.GetComponentLogger(infrastructure.ComponentJobQueue).With()
+	lc = lc.Str(infrastructure.RequestIDField, requestID).
+		Int64("job_id", job.ID).
+		Str("job_kind", job.Kind).
+		Int32("attempt", job.Attempt)
+	l := lc.Logger()
+	jobCtx := context.WithValue(context.Background(), infrastructure.CtxKeyLogger{}, &l) //nolint:contextcheck
+	jobCtx = context.WithValue(jobCtx, infrastructure.CtxKeyRequestID{}, requestID)
+	if job.Tenant != "" {
+		jobCtx = infrastructure.WithTenant(jobCtx, job.Tenant)
+	}
+	jobCtx, cancel := context.WithTimeout(jobCtx, q.cfg.JobTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := q.run(jobCtx, job)
+	log := infrastructure.GetBaseLogger(jobCtx)
+
+	// job context may be expired by JobTimeout, so the result is saved with own timeout
+	saveCtx := context.WithValue(context.Background(), infrastructure.CtxKeyLogger{}, &l) //nolint:contextcheck
+	saveCtx = context.WithValue(saveCtx, infrastructure.CtxKeyRequestID{}, requestID)
+	saveCtx, saveCancel := context.WithTimeout(saveCtx, jobResultTimeout)
+	defer saveCancel()
+	if err == nil {
+		log.Info().Dur("latency", time.Since(start)).Msg("job completed")
+		if err = q.db.Execute(saveCtx, completeJobStatement, job.ID, worker, job.Attempt); err != nil {
+			log.Error().Err(err).Msg("can't complete job")
+		}
+		return
+	}
+
+	if job.Attempt >= job.MaxAttempts {
+		log.Error().Err(err).Msg("job failed. no attempts left")
+		err = q.db.Execute(saveCtx, killJobStatement, job.ID, err.Error(), worker, job.Attempt)
+	} else {
+		delay := q.retryDelay(job.Attempt)
+		log.Warn().Err(err).Dur("delay", delay).Msg("job failed. will be retried")
+		err = q.db.Execute(saveCtx, retryJobStatement, job.ID, delay.Milliseconds(), err.Error(), worker, job.Attempt)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("can't save job result")
+	}
+}
+
+// run - runs job handler. Panic in handler is returned as error
+func (q *JobQueue) run(ctx context.Context, job Job) (err error) {
+	h, ok := q.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobHandlerNotFound, job.Kind)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panic: %v", r) //nolint:goerr113
+		}
+	}()
+	return h(ctx, job)
+}
+
+// retryDelay - returns delay before the next attempt. attempt - count of finished attempts
+func (q *JobQueue) retryDelay(attempt int32) time.Duration {
+	delay := q.cfg.RetryInterval
+	for i := int32(1); i < attempt && delay < q.cfg.MaxRetryInterval; i++ {
+		delay *= 2
+	}
+	if delay > q.cfg.MaxRetryInterval {
+		delay = q.cfg.MaxRetryInterval
+	}
+	return delay
+}
+
+// rescue - returns into the queue jobs which run longer than JobTimeout (worker crashed or instance was killed).
+// Jobs without attempts left are moved into jobs_dead
+func (q *JobQueue) rescue(ctx context.Context) {
+	if err := q.db.Execute(ctx, rescueJobsStatement, q.cfg.JobTimeout.Milliseconds()); err != nil && ctx.Err() == nil {
+		q.LogError(ctx, "can't rescue abandoned jobs", err)
+	}
+}
+
+// Close - stops workers. Waits for running jobs until ctx is canceled
+func (q *JobQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	started := q.started
+	cancel := q.cancel
+	q.mu.Unlock()
+	if !started {
+		return nil
+	}
+	cancel()
+	select {
+	case <-q.done:
+	case <-ctx.Done():
+		q.LogWarn(ctx, "job queue wasn't stopped in time")
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+func TestJobQueue_retryDelay(t *testing.T) {
+	q, err := NewJobQueue(context.Background(), &PostgresqlHandlerTX{}, JobQueueConfig{
+		RetryInterval:    time.Second,
+		MaxRetryInterval: time.Second * 10,
+	})
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		attempt int32
+		want    time.Duration
+	}{
+		{name: "JobQueue. retryDelay. Case #1. First attempt", attempt: 1, want: time.Second},
+		{name: "JobQueue. retryDelay. Case #2. Third attempt", attempt: 3, want: time.Second * 4},
+		{name: "JobQueue. retryDelay. Case #3. Max delay", attempt: 10, want: time.Second * 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, q.retryDelay(tt.attempt))
+		})
+	}
+}
+
+func TestJobQueue_AddHandler(t *testing.T) {
+	h := func(ctx context.Context, job Job) error { return nil }
+	tests := []struct {
+		name    string
+		kind    string
+		h       JobHandleFunc
+		started bool
+		wantErr error
+	}{
+		{name: "JobQueue. AddHandler. Case #1. Positive", kind: "send_email", h: h},
+		{name: "JobQueue. AddHandler. Case #2. Empty kind", kind: "", h: h, wantErr: ErrJobQueueBadParam},
+		{name: "JobQueue. AddHandler. Case #3. Empty handler", kind: "send_email", wantErr: ErrJobQueueBadParam},
+		{name: "JobQueue. AddHandler. Case #4. Queue is started", kind: "send_email", h: h, started: true, wantErr: ErrJobQueueStarted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewJobQueue(context.Background(), &PostgresqlHandlerTX{}, JobQueueConfig{})
+			require.NoError(t, err)
+			q.started = tt.started
+			assert.ErrorIs(t, q.AddHandler(context.Background(), tt.kind, tt.h), tt.wantErr)
+		})
+	}
+}
+
+func TestJobQueue_run(t *testing.T) {
+	type email struct {
+		To string `json:"to"`
+	}
+	var got email
+	q, err := NewJobQueue(context.Background(), &PostgresqlHandlerTX{}, JobQueueConfig{})
+	require.NoError(t, err)
+	require.NoError(t, AddJobHandler(context.Background(), q, "send_email", func(ctx context.Context, job Job, payload email) error {
+		got = payload
+		return nil
+	}))
+	require.NoError(t, q.AddHandler(context.Background(), "panic", func(ctx context.Context, job Job) error {
+		panic("test panic")
+	}))
+
+	tests := []struct {
+		name    string
+		job     Job
+		wantErr bool
+		want    email
+	}{
+		{
+			name: "JobQueue. run. Case #1. Typed handler",
+			job:  Job{Kind: "send_email", Payload: json.RawMessage(`{"to":"user@example.com"}`)},
+			want: email{To: "user@example.com"},
+		},
+		{
+			name:    "JobQueue. run. Case #2. Bad payload",
+			job:     Job{Kind: "send_email", Payload: json.RawMessage(`[]`)},
+			wantErr: true,
+		},
+		{
+			name:    "JobQueue. run. Case #3. Unknown kind",
+			job:     Job{Kind: "unknown", Payload: json.RawMessage(`{}`)},
+			wantErr: true,
+		},
+		{
+			name:    "JobQueue. run. Case #4. Handler panic",
+			job:     Job{Kind: "panic", Payload: json.RawMessage(`{}`)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = email{}
+			err := q.run(context.Background(), tt.job)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPostgresqlHandlerTX_EnqueueBadParam(t *testing.T) {
+	handler := &PostgresqlHandlerTX{}
+	_, err := handler.Enqueue(context.Background(), "", nil)
+	assert.ErrorIs(t, err, ErrJobQueueBadParam)
+	_, err = handler.Enqueue(context.Background(), "send_email", nil, WithJobMaxAttempts(0))
+	assert.ErrorIs(t, err, ErrJobQueueBadParam)
+}
+
+func TestIntegrationJobQueue_Process(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	const queue = "test_queue"
+
+	// job enqueued in rolled back transaction is discarded
+	err := target.WithTx(ctx)(ctx, func(ctx context.Context) error {
+		if _, err := target.Enqueue(ctx, "ok", map[string]string{"a": "b"}, WithJobQueue(queue)); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+
+	okID, err := target.Enqueue(ctx, "ok", map[string]string{"a": "b"}, WithJobQueue(queue), WithJobUniqueKey("k1"))
+	require.NoError(t, err)
+	_, err = target.Enqueue(ctx, "ok", map[string]string{"a": "b"}, WithJobQueue(queue), WithJobUniqueKey("k1"))
+	require.ErrorIs(t, err, ErrJobDuplicate)
+	failID, err := target.Enqueue(ctx, "fail", nil, WithJobQueue(queue), WithJobMaxAttempts(2))
+	require.NoError(t, err)
+
+	q, err := NewJobQueue(ctx, target, JobQueueConfig{
+		Queues:        []string{queue},
+		Workers:       2,
+		PollInterval:  time.Millisecond * 50,
+		RetryInterval: time.Millisecond,
+	})
+	require.NoError(t, err)
+	processed := make(chan int64, 10)
+	require.NoError(t, q.AddHandler(ctx, "ok", func(ctx context.Context, job Job) error {
+		processed <- job.ID
+		return nil
+	}))
+	require.NoError(t, q.AddHandler(ctx, "fail", func(ctx context.Context, job Job) error {
+		return errors.New("test error")
+	}))
+	go func() {
+		_ = q.Start(ctx)
+	}()
+	defer q.Close(ctx)
+
+	select {
+	case id := <-processed:
+		assert.Equal(t, okID, id)
+	case <-ctx.Done():
+		require.FailNow(t, "job wasn't processed")
+	}
+
+	require.Eventually(t, func() bool {
+		row, err := target.QueryRow(ctx, "SELECT attempt FROM jobs_dead WHERE id = $1", failID)
+		if err != nil {
+			return false
+		}
+		var attempt int32
+		return row.Scan(&attempt) == nil && attempt == 2
+	}, time.Second*10, time.Millisecond*100)
+}
+
+func TestIntegrationJobQueue_ProcessTimeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	const queue = "test_queue_timeout"
+
+	id, err := target.Enqueue(ctx, "slow", nil, WithJobQueue(queue), WithJobMaxAttempts(1))
+	require.NoError(t, err)
+
+	q, err := NewJobQueue(ctx, target, JobQueueConfig{
+		Queues:       []string{queue},
+		Workers:      1,
+		PollInterval: time.Millisecond * 50,
+		JobTimeout:   time.Millisecond * 200,
+	})
+	require.NoError(t, err)
+	require.NoError(t, q.AddHandler(ctx, "slow", func(ctx context.Context, job Job) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	go func() {
+		_ = q.Start(ctx)
+	}()
+	defer q.Close(ctx)
+
+	// result of the timed out attempt is saved, so the job gets into jobs_dead
+	require.Eventually(t, func() bool {
+		row, err := target.QueryRow(ctx, "SELECT last_error FROM jobs_dead WHERE id = $1", id)
+		if err != nil {
+			return false
+		}
+		var lastError string
+		return row.Scan(&lastError) == nil && lastError == context.DeadlineExceeded.Error()
+	}, time.Second*10, time.Millisecond*100)
+}
+
+func TestIntegrationJobQueue_LateWorker(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	const queue = "test_queue_late"
+
+	id, err := target.Enqueue(ctx, "late", nil, WithJobQueue(queue))
+	require.NoError(t, err)
+	q, err := NewJobQueue(ctx, target, JobQueueConfig{Queues: []string{queue}, JobTimeout: time.Millisecond})
+	require.NoError(t, err)
+
+	late, err := q.fetch(ctx, "w1")
+	require.NoError(t, err)
+	require.Equal(t, id, late.ID)
+	time.Sleep(time.Millisecond * 10)
+	q.rescue(ctx)
+	job, err := q.fetch(ctx, "w2")
+	require.NoError(t, err)
+	require.Equal(t, id, job.ID)
+
+	// late worker doesn't change the job taken by another worker
+	require.NoError(t, target.Execute(ctx, completeJobStatement, late.ID, "w1", late.Attempt))
+	require.NoError(t, target.Execute(ctx, retryJobStatement, late.ID, 0, "late error", "w1", late.Attempt))
+	row, err := target.QueryRow(ctx, "SELECT status, locked_by FROM jobs WHERE id = $1", id)
+	require.NoError(t, err)
+	var status, lockedBy string
+	require.NoError(t, row.Scan(&status, &lockedBy))
+	assert.Equal(t, "running", status)
+	assert.Equal(t, "w2", lockedBy)
+
+	require.NoError(t, target.Execute(ctx, completeJobStatement, job.ID, "w2", job.Attempt))
+	row, err = target.QueryRow(ctx, "SELECT id FROM jobs WHERE id = $1", id)
+	require.NoError(t, err)
+	var found int64
+	assert.ErrorIs(t, row.Scan(&found), basedbhandler.ErrNotFound)
+}
+
+func TestIntegrationJobQueue_Tenant(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	const queue = "test_queue_tenant"
+	require.NoError(t, target.Execute(ctx, "CREATE SCHEMA IF NOT EXISTS tenant_t1"))
+
+	handler, err := NewPostgresqlHandlerTX(ctx, dsn, PgPoolConfig{MaxConns: 2, TenantSchemas: true, TenantSchemaPrefix: "tenant_"})
+	require.NoError(t, err)
+	defer handler.Close(ctx)
+
+	// job is enqueued in the request of the tenant, but stored in the shared jobs table
+	id, err := handler.Enqueue(infrastructure.WithTenant(ctx, "t1"), "tenant", nil, WithJobQueue(queue))
+	require.NoError(t, err)
+
+	q, err := NewJobQueue(ctx, handler, JobQueueConfig{Queues: []string{queue}, Workers: 1, PollInterval: time.Millisecond * 50})
+	require.NoError(t, err)
+	tenants := make(chan string, 1)
+	require.NoError(t, q.AddHandler(ctx, "tenant", func(ctx context.Context, job Job) error {
+		assert.Equal(t, id, job.ID)
+		tenant, _ := infrastructure.TenantFromContext(ctx)
+		assert.Equal(t, job.Tenant, tenant)
+		tenants <- tenant
+		return nil
+	}))
+	go func() {
+		_ = q.Start(ctx)
+	}()
+	defer q.Close(ctx)
+
+	select {
+	case tenant := <-tenants:
+		assert.Equal(t, "t1", tenant)
+	case <-ctx.Done():
+		require.FailNow(t, "job of the tenant wasn't processed")
+	}
+}
+
+func TestIntegrationJobQueue_RescueLastAttempt(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	const queue = "test_queue_rescue"
+
+	id, err := target.Enqueue(ctx, "crash", nil, WithJobQueue(queue), WithJobMaxAttempts(1))
+	require.NoError(t, err)
+	q, err := NewJobQueue(ctx, target, JobQueueConfig{Queues: []string{queue}, JobTimeout: time.Millisecond})
+	require.NoError(t, err)
+
+	// the worker crashed during the last attempt
+	job, err := q.fetch(ctx, "w1")
+	require.NoError(t, err)
+	require.Equal(t, id, job.ID)
+	time.Sleep(time.Millisecond * 10)
+	q.rescue(ctx)
+
+	_, err = q.fetch(ctx, "w2")
+	assert.ErrorIs(t, err, basedbhandler.ErrNotFound)
+	row, err := target.QueryRow(ctx, "SELECT attempt, last_error FROM jobs_dead WHERE id = $1", id)
+	require.NoError(t, err)
+	var (
+		attempt   int32
+		lastError string
+	)
+	require.NoError(t, row.Scan(&attempt, &lastError))
+	assert.Equal(t, int32(1), attempt)
+	assert.Equal(t, "job was abandoned by worker w1", lastError)
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// ErrLeaderElectorBadParam - "bad leader elector param" error
+var ErrLeaderElectorBadParam = errors.New("bad leader elector param")
+
+const defaultLeaderCheckInterval = time.Second * 5
+
+// LeaderElectionConfig - struct for leader election params
+type LeaderElectionConfig struct {
+	// Name - name of the election. Only one instance among all instances with the same name becomes a leader
+	Name string `validate:"required"`
+
+	// CheckInterval - the duration between attempts to become a leader and between checks of the leadership
+	CheckInterval time.Duration
+}
+
+// LeaderCallbacks - callbacks for leadership changes
+type LeaderCallbacks struct {
+	// OnStartedLeading - called when leadership is gained. ctx is canceled when leadership is lost
+	OnStartedLeading func(ctx context.Context)
+
+	// OnStoppedLeading - called when leadership is lost or released
+	OnStoppedLeading func()
+}
+
+// LeaderElector - leader election based on session level advisory lock.
+// LeaderElector holds a dedicated connection, because lock belongs to the session
+type LeaderElector struct {
+	infrastructure.SugarLogger
+	db        *PostgresqlHandlerTX
+	cfg       LeaderElectionConfig
+	callbacks LeaderCallbacks
+	key       int64
+
+	mu           sync.Mutex
+	conn         *pgx.Conn
+	leader       bool
+	cancel       context.CancelFunc
+	done         chan struct{}
+	started      bool
+	leaderCancel context.CancelFunc
+}
+
+// NewLeaderElector returns new LeaderElector
+func NewLeaderElector(ctx context.Context, db *PostgresqlHandlerTX, cfg LeaderElectionConfig, callbacks LeaderCallbacks) (*LeaderElector, error) {
+	var target LeaderElector
+	target.db = db
+	target.cfg = cfg
+	target.callbacks = callbacks
+
+	if err := target.Init(ctx); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Init - func for initialisation
+func (e *LeaderElector) Init(ctx context.Context) error {
+	if e.db == nil || e.cfg.Name == "" {
+		e.LogError(ctx, "db handler or election name is not set", ErrLeaderElectorBadParam)
+		return ErrLeaderElectorBadParam
+	}
+	if e.cfg.CheckInterval <= 0 {
+		e.cfg.CheckInterval = defaultLeaderCheckInterval
+	}
+	e.key = LockKey(e.cfg.Name)
+	e.done = make(chan struct{})
+	return nil
+}
+
+// IsLeader - returns true if current instance is a leader
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// Start - starts election. Blocks until ctx is canceled or Close is called
+func (e *LeaderElector) Start(ctx context.Context) error {
+	e.mu.Lock()
+	if e.started {
+		e.mu.Unlock()
+		return ErrLeaderElectorBadParam
+	}
+	e.started = true
+	ctx, e.cancel = context.WithCancel(ctx)
+	e.mu.Unlock()
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		e.tick(ctx)
+		select {
+		case <-ctx.Done():
+			e.release()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick - tries to become a leader or checks that leadership is still held
+func (e *LeaderElector) tick(ctx context.Context) {
+	if e.IsLeader() {
+		if err := e.conn.Ping(ctx); err != nil && ctx.Err() == nil {
+			e.Log(ctx).Warn().Err(err).Str("election", e.cfg.Name).Msg("leader connection lost")
+			e.release()
+		}
+		return
+	}
+
+	if e.conn == nil || e.conn.IsClosed() {
+		conn, err := pgx.ConnectConfig(ctx, e.db.pool.Config().ConnConfig)
+		if err != nil {
+			e.Log(ctx).Error().Err(err).Str("election", e.cfg.Name).Msg("can't connect to db for leader election")
+			return
+		}
+		e.conn = conn
+	}
+
+	var locked bool
+	if err := e.conn.QueryRow(ctx, tryAdvisoryLockStatement, e.key).Scan(&locked); err != nil {
+		e.Log(ctx).Error().Err(err).Str("election", e.cfg.Name).Msg("can't try leader lock")
+		e.closeConn()
+		return
+	}
+	if !locked {
+		return
+	}
+
+	leaderCtx, leaderCancel := context.WithCancel(ctx)
+	e.mu.Lock()
+	e.leader = true
+	e.leaderCancel = leaderCancel
+	e.mu.Unlock()
+	e.Log(ctx).Info().Str("election", e.cfg.Name).Msg("leadership acquired")
+	if e.callbacks.OnStartedLeading != nil {
+		go e.callbacks.OnStartedLeading(leaderCtx)
+	}
+}
+
+// release - releases leadership and closes connection. Closing connection releases lock on the server side
+func (e *LeaderElector) release() {
+	e.mu.Lock()
+	wasLeader := e.leader
+	e.leader = false
+	if e.leaderCancel != nil {
+		e.leaderCancel()
+		e.leaderCancel = nil
+	}
+	e.mu.Unlock()
+
+	e.closeConn()
+	if wasLeader {
+		e.Log(context.Background()).Info().Str("election", e.cfg.Name).Msg("leadership released")
+		if e.callbacks.OnStoppedLeading != nil {
+			e.callbacks.OnStoppedLeading()
+		}
+	}
+}
+
+func (e *LeaderElector) closeConn() {
+	if e.conn == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.CheckInterval)
+	defer cancel()
+	if !e.conn.IsClosed() {
+		// unlock explicitly. If it fails, lock is released on connection close anyway
+		_, _ = e.conn.Exec(ctx, advisoryUnlockStatement, e.key)
+	}
+	_ = e.conn.Close(ctx)
+	e.conn = nil
+}
+
+// Close - stops election and releases leadership
+func (e *LeaderElector) Close(ctx context.Context) error {
+	e.mu.Lock()
+	started := e.started
+	cancel := e.cancel
+	e.mu.Unlock()
+	if !started {
+		return nil
+	}
+	cancel()
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		e.LogWarn(ctx, "leader elector wasn't stopped in time")
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"go-service-template/internal/app/infrastructure"
+)
+
+var (
+	// ErrListenerBadParam - "bad listener param" error
+	ErrListenerBadParam = errors.New("bad listener param")
+
+	// ErrListenerStarted - "listener is already started" error
+	ErrListenerStarted = errors.New("listener is already started")
+)
+
+const (
+	defaultListenerReconnectInterval    = time.Second
+	defaultListenerMaxReconnectInterval = time.Minute
+)
+
+// NotificationHandleFunc - func type for notification handlers
+type NotificationHandleFunc func(ctx context.Context, notification pgconn.Notification) error
+
+// NotificationMiddlewareFunc - func type for listener middleware
+type NotificationMiddlewareFunc func(next NotificationHandleFunc) NotificationHandleFunc
+
+// ListenerConfig - struct for listener params
+type ListenerConfig struct {
+	// Channels - list of channels for listening
+	Channels []string `env:"PG_LISTENER_CHANNELS" envSeparator:"," yaml:"channels"`
+
+	// ReconnectInterval - initial delay between reconnection attempts. Delay is doubled after each failed attempt
+	ReconnectInterval time.Duration `yaml:"reconnectInterval"`
+
+	// MaxReconnectInterval - max delay between reconnection attempts
+	MaxReconnectInterval time.Duration `yaml:"maxReconnectInterval"`
+}
+
+// Listener - subscriber for postgres notifications (LISTEN/NOTIFY).
+// Listener holds a dedicated connection, so it doesn't consume connections from the pool
+type Listener struct {
+	infrastructure.SugarLogger
+	db         *PostgresqlHandlerTX
+	cfg        ListenerConfig
+	handlers   map[string]NotificationHandleFunc
+	middleware []NotificationMiddlewareFunc
+
+	mu      sync.Mutex
+	ready   bool
+	started bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewListener returns new Listener
+func NewListener(ctx context.Context, db *PostgresqlHandlerTX, cfg ListenerConfig) (*Listener, error) {
+	var target Listener
+	target.db = db
+	target.cfg = cfg
+
+	if err := target.Init(ctx); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Init - func for initialisation
+func (l *Listener) Init(ctx context.Context) error {
+	if l.db == nil {
+		l.LogError(ctx, "db handler is not set", ErrListenerBadParam)
+		return ErrListenerBadParam
+	}
+	if l.cfg.ReconnectInterval <= 0 {
+		l.cfg.ReconnectInterval = defaultListenerReconnectInterval
+	}
+	if l.cfg.MaxReconnectInterval < l.cfg.ReconnectInterval {
+		l.cfg.MaxReconnectInterval = defaultListenerMaxReconnectInterval
+	}
+	l.handlers = make(map[string]NotificationHandleFunc)
+	l.done = make(chan struct{})
+
+	l.Use(prepareNotificationLoggerMiddleware)
+	l.Use(logNotificationMiddleware)
+	return nil
+}
+
+// AddHandler - adds handler for channel. Must be called before Start
+func (l *Listener) AddHandler(ctx context.Context, channel string, h NotificationHandleFunc) error {
+	if channel == "" {
+		l.LogError(ctx, "channel name is empty", ErrListenerBadParam)
+		return ErrListenerBadParam
+	}
+	if h == nil {
+		l.LogError(ctx, "can't find any handler", ErrListenerBadParam)
+		return ErrListenerBadParam
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.started {
+		l.LogError(ctx, "can't add handler", ErrListenerStarted)
+		return ErrListenerStarted
+	}
+	l.handlers[channel] = h
+	return nil
+}
+
+// Use - adds middleware for notification handlers
+func (l *Listener) Use(h NotificationMiddlewareFunc) {
+	l.middleware = append(l.middleware, h)
+}
+
+// Ready - returns true if listener is subscribed to all channels
+func (l *Listener) Ready() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ready
+}
+
+// Start - starts listening. Blocks until ctx is canceled or Close is called.
+// If connection is lost, listener reconnects and subscribes to the channels again
+func (l *Listener) Start(ctx context.Context) error {
+	l.mu.Lock()
+	if l.started {
+		l.mu.Unlock()
+		return ErrListenerStarted
+	}
+	l.started = true
+	ctx, l.cancel = context.WithCancel(ctx)
+	l.mu.Unlock()
+	defer close(l.done)
+
+	delay := l.cfg.ReconnectInterval
+	for {
+		connected, err := l.listen(ctx)
+		if ctx.Err() != nil {
+			l.LogInfo(ctx, "postgres listener terminating: context canceled")
+			return nil
+		}
+		if connected {
+			// connection was established before failure. Start reconnecting from initial delay
+			delay = l.cfg.ReconnectInterval
+		}
+		l.Log(ctx).Error().Err(err).Dur("delay", delay).Msg("postgres listener connection lost. reconnecting")
+
+		select {
+		case <-ctx.Done():
+			l.LogInfo(ctx, "postgres listener terminating: context canceled")
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > l.cfg.MaxReconnectInterval {
+			delay = l.cfg.MaxReconnectInterval
+		}
+	}
+}
+
+// listen - connects to db, subscribes to the channels and dispatches notifications until error occurs.
+// connected is true if subscription was successful before the error
+func (l *Listener) listen(ctx context.Context) (connected bool, err error) {
+	conn, err := pgx.ConnectConfig(ctx, l.db.pool.Config().ConnConfig)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		l.setReady(false)
+		_ = conn.Close(context.Background()) //nolint:contextcheck
+	}()
+
+	for channel := range l.handlers {
+		if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			l.LogError(ctx, "can't subscribe to channel "+channel, err)
+			return false, err
+		}
+	}
+	l.setReady(true)
+	l.LogInfo(ctx, "postgres listener up and running")
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		l.dispatch(*notification)
+	}
+}
+
+func (l *Listener) dispatch(notification pgconn.Notification) {
+	h, ok := l.handlers[notification.Channel]
+	if !ok {
+		return
+	}
+	// pass new context to the handler!
+	err := l.applyMiddleware(h)(context.Background(), notification)
+	if err != nil {
+		l.LogError(context.Background(), "error while notification processing", err)
+	}
+}
+
+func (l *Listener) applyMiddleware(hf NotificationHandleFunc) NotificationHandleFunc {
+	for i := len(l.middleware) - 1; i >= 0; i-- {
+		hf = l.middleware[i](hf)
+	}
+	return hf
+}
+
+func (l *Listener) setReady(ready bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ready = ready
+}
+
+// Close - stops listening and closes connection
+func (l *Listener) Close(ctx context.Context) error {
+	l.mu.Lock()
+	started := l.started
+	cancel := l.cancel
+	l.mu.Unlock()
+	if !started {
+		return nil
+	}
+	cancel()
+	select {
+	case <-l.done:
+	case <-ctx.Done():
+		l.LogWarn(ctx, "postgres listener wasn't stopped in time")
+		return ctx.Err()
+	}
+	return nil
+}
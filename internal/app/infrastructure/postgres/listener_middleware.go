@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"go-service-template/internal/app/infrastructure"
+)
+
+func logNotificationMiddleware(next NotificationHandleFunc) NotificationHandleFunc {
+	return func(ctx context.Context, notification pgconn.Notification) error {
+		log := infrastructure.GetBaseLogger(ctx).
+			With().
+			Str("channel", notification.Channel).
+			Uint32("pid", notification.PID).
+			Logger()
+		log.Info().Msg("Incoming notification")
+		start := time.Now()
+		status := "success"
+		res := next(ctx, notification)
+		if res != nil {
+			status = "error"
+		}
+		latency := time.Since(start)
+		log = infrastructure.GetBaseLogger(ctx).
+			With().
+			Str("channel", notification.Channel).
+			Uint32("pid", notification.PID).
+			Str("status", status).
+			Dur("latency", latency).
+			Logger()
+		log.Info().Send()
+		return res
+	}
+}
+
+// prepareNotificationLoggerMiddleware - adds requestID and logger into context.
+// Notifications don't have headers, so new requestID is generated for every notification
+func prepareNotificationLoggerMiddleware(next NotificationHandleFunc) NotificationHandleFunc {
+	return func(ctx context.Context, notification pgconn.Notification) error {
+		requestID := infrastructure.GenerateID()
+		lc := infrastructure.GetComponentLogger(infrastructure.ComponentPostgresListener).With()
+		lc = lc.Str(infrastructure.RequestIDField, requestID)
+
+		l := lc.Logger()
+		newCtx := context.WithValue(ctx, infrastructure.CtxKeyLogger{}, &l)
+		newCtx = context.WithValue(newCtx, infrastructure.CtxKeyRequestID{}, requestID)
+		return next(newCtx, notification)
+	}
+}
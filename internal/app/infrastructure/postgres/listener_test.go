@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/infrastructure"
+)
+
+func TestListener_AddHandler(t *testing.T) {
+	h := func(ctx context.Context, notification pgconn.Notification) error { return nil }
+	type args struct {
+		channel string
+		h       NotificationHandleFunc
+		started bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "Listener. AddHandler. Case #1. Positive",
+			args:    args{channel: "test_channel", h: h},
+			wantErr: nil,
+		},
+		{
+			name:    "Listener. AddHandler. Case #2. Empty channel",
+			args:    args{channel: "", h: h},
+			wantErr: ErrListenerBadParam,
+		},
+		{
+			name:    "Listener. AddHandler. Case #3. Empty handler",
+			args:    args{channel: "test_channel", h: nil},
+			wantErr: ErrListenerBadParam,
+		},
+		{
+			name:    "Listener. AddHandler. Case #4. Listener is started",
+			args:    args{channel: "test_channel", h: h, started: true},
+			wantErr: ErrListenerStarted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewListener(context.Background(), &PostgresqlHandlerTX{}, ListenerConfig{})
+			require.NoError(t, err)
+			l.started = tt.args.started
+			err = l.AddHandler(context.Background(), tt.args.channel, tt.args.h)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestListener_prepareNotificationLoggerMiddleware(t *testing.T) {
+	var requestID string
+	h := prepareNotificationLoggerMiddleware(func(ctx context.Context, notification pgconn.Notification) error {
+		requestID, _ = ctx.Value(infrastructure.CtxKeyRequestID{}).(string)
+		assert.NotNil(t, ctx.Value(infrastructure.CtxKeyLogger{}))
+		return nil
+	})
+	assert.NoError(t, h(context.Background(), pgconn.Notification{Channel: "test_channel"}))
+	assert.NotEmpty(t, requestID)
+}
+
+func TestIntegrationListener_Notify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	const channel = "test_channel"
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	received := make(chan pgconn.Notification, 1)
+	l, err := NewListener(ctx, target, ListenerConfig{})
+	require.NoError(t, err)
+	require.NoError(t, l.AddHandler(ctx, channel, func(ctx context.Context, notification pgconn.Notification) error {
+		received <- notification
+		return nil
+	}))
+	go func() {
+		_ = l.Start(ctx)
+	}()
+	defer func() {
+		assert.NoError(t, l.Close(context.Background()))
+	}()
+
+	for !l.Ready() {
+		select {
+		case <-ctx.Done():
+			assert.FailNow(t, "context expired")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	require.NoError(t, target.Execute(ctx, "select pg_notify($1, $2)", channel, "payload"))
+	select {
+	case <-ctx.Done():
+		assert.FailNow(t, "context expired")
+	case n := <-received:
+		assert.Equal(t, channel, n.Channel)
+		assert.Equal(t, "payload", n.Payload)
+	}
+}
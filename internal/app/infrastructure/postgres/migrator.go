@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	pgxMigrate "github.com/golang-migrate/migrate/v4/database/pgx"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4/stdlib"
+	"go-service-template/internal/app/infrastructure"
+)
+
+// ErrNilMigrationVersion - "no migration" error. Returned by Migrator.Version if there are no applied migrations
+var ErrNilMigrationVersion = migrate.ErrNilVersion
+
+const (
+	migrationLockName           = "schema_migrations"
+	defaultMigrationLockTimeout = time.Minute * 5
+
+	// addNilVersionHistory - the trigger on schema_migrations doesn't see the version removed by TRUNCATE
+	addNilVersionHistory = `INSERT INTO schema_migrations_history (id, update_time, operation_type, version, dirty)
+	VALUES (nextval('schema_migrations_history_sq'), now(), $1, $2, false)`
+)
+
+// Migrator - applies migrations from fs.FS. Every command is executed under advisory lock.
+// Version changes are registered in the schema_migrations_history table by the trigger on schema_migrations,
+// removal of the version is registered by Migrator
+type Migrator struct {
+	infrastructure.SugarLogger
+	db          *PostgresqlHandlerTX
+	dataSource  string
+	source      fs.FS
+	path        string
+	lockTimeout time.Duration
+	sqlDB       *sql.DB
+	m           *migrate.Migrate
+}
+
+// NewMigrator returns new Migrator. dataSource must contain credentials of the schema owner
+func NewMigrator(ctx context.Context, dataSource string, source fs.FS, path string, lockTimeout time.Duration) (*Migrator, error) {
+	var target Migrator
+	target.dataSource = dataSource
+	target.source = source
+	target.path = path
+	target.lockTimeout = lockTimeout
+
+	if err := target.Init(ctx); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Init - func for initialisation
+func (m *Migrator) Init(ctx context.Context) error {
+	var err error
+	if m.lockTimeout <= 0 {
+		m.lockTimeout = defaultMigrationLockTimeout
+	}
+	m.db, err = NewPostgresqlHandlerTX(ctx, m.dataSource, PgPoolConfig{MaxConns: 2, MinConns: 1})
+	if err != nil {
+		m.LogError(ctx, "can't create db handler for migrations", err)
+		return err
+	}
+
+	src, err := iofs.New(m.source, m.path)
+	if err != nil {
+		m.LogError(ctx, "can't open migrations source", err)
+		_ = m.db.Close(ctx)
+		return err
+	}
+	m.sqlDB = stdlib.OpenDB(*m.db.pool.Config().ConnConfig)
+	driver, err := pgxMigrate.WithInstance(m.sqlDB, &pgxMigrate.Config{})
+	if err != nil {
+		m.LogError(ctx, "can't create migrate db driver", err)
+		_ = m.Close(ctx)
+		return err
+	}
+	m.m, err = migrate.NewWithInstance("iofs", src, "pgx", driver)
+	if err != nil {
+		m.LogError(ctx, "can't create migrate instance", err)
+		_ = m.Close(ctx)
+		return err
+	}
+	return nil
+}
+
+// Up - applies all available migrations
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, "UP", m.m.Up)
+}
+
+// Down - reverts all applied migrations
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.run(ctx, "DOWN", m.m.Down)
+}
+
+// Steps - applies n migrations if n > 0 or reverts -n migrations if n < 0
+func (m *Migrator) Steps(ctx context.Context, n int) error {
+	operation := "UP"
+	if n < 0 {
+		operation = "DOWN"
+	}
+	return m.run(ctx, operation, func() error {
+		return m.m.Steps(n)
+	})
+}
+
+// Goto - migrates up or down to the version
+func (m *Migrator) Goto(ctx context.Context, version uint) error {
+	return m.run(ctx, "GOTO", func() error {
+		return m.m.Migrate(version)
+	})
+}
+
+// Force - sets version without running migrations and resets dirty state. -1 means no version
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	return m.run(ctx, "FORCE", func() error {
+		return m.m.Force(version)
+	})
+}
+
+// Version - returns current version. If there is no applied migrations, ErrNilMigrationVersion is returned
+func (m *Migrator) Version(_ context.Context) (uint, bool, error) {
+	return m.m.Version()
+}
+
+// run - executes migration command under advisory lock and logs the result
+func (m *Migrator) run(ctx context.Context, operation string, command func() error) error {
+	lock, err := m.db.Lock(ctx, migrationLockName, m.lockTimeout)
+	if err != nil {
+		m.LogError(ctx, "can't acquire migration lock", err)
+		return err
+	}
+	defer func() {
+		if err := lock.Unlock(ctx); err != nil {
+			m.LogError(ctx, "can't release migration lock", err)
+		}
+	}()
+
+	err = command()
+	if errors.Is(err, migrate.ErrNoChange) {
+		m.Log(ctx).Info().Str("operation", operation).Msg("no migrations to apply")
+		return nil
+	}
+	if err != nil {
+		m.Log(ctx).Error().Err(err).Str("operation", operation).Msg("can't apply migrations")
+		return err
+	}
+	m.logResult(ctx, operation)
+	return nil
+}
+
+// logResult - logs migration command and the version after it. The version is registered
+// in schema_migrations_history by the trigger, only the removed version is written here
+func (m *Migrator) logResult(ctx context.Context, operation string) {
+	version, dirty, err := m.m.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		m.Log(ctx).Info().Str("operation", operation).Msg("migrations applied. no version")
+		m.writeNilVersion(ctx, operation)
+		return
+	case err != nil:
+		m.LogError(ctx, "can't get migration version", err)
+		return
+	}
+	m.Log(ctx).Info().Str("operation", operation).Uint("version", version).Bool("dirty", dirty).Msg("migrations applied")
+}
+
+// writeNilVersion - registers removal of the version (e.g. force -1). SetVersion truncates schema_migrations
+// without insert, so the trigger isn't fired. After full down migration the history table doesn't exist
+func (m *Migrator) writeNilVersion(ctx context.Context, operation string) {
+	err := m.db.Execute(ctx, addNilVersionHistory, operation, int64(database.NilVersion))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UndefinedTable {
+		m.LogDebug(ctx, "schema_migrations_history doesn't exist. history isn't written")
+		return
+	}
+	if err != nil {
+		m.LogError(ctx, "can't write migration history", err)
+	}
+}
+
+// Close - free resources
+func (m *Migrator) Close(ctx context.Context) error {
+	if m.m != nil {
+		srcErr, dbErr := m.m.Close()
+		if srcErr != nil {
+			m.LogError(ctx, "can't close migrations source", srcErr)
+		}
+		if dbErr != nil {
+			m.LogError(ctx, "can't close migrations db driver", dbErr)
+		}
+	} else if m.sqlDB != nil {
+		_ = m.sqlDB.Close()
+	}
+	return m.db.Close(ctx)
+}
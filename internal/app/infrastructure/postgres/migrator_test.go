@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/scripts/database"
+)
+
+func TestIntegrationMigrator(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := context.Background()
+	migrator, err := NewMigrator(ctx, dsn, database.Migrations, database.MigrationsPath, time.Minute)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, migrator.Close(ctx))
+	}()
+
+	// migrations are already applied by test container
+	version, dirty, err := migrator.Version(ctx)
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	assert.NoError(t, migrator.Up(ctx))
+
+	countHistory := func(version int64) int {
+		row, err := target.QueryRow(ctx, "select count(*) from schema_migrations_history where version = $1", version)
+		require.NoError(t, err)
+		var cnt int
+		require.NoError(t, row.Scan(&cnt))
+		return cnt
+	}
+	before := countHistory(int64(version))
+	require.NoError(t, migrator.Force(ctx, int(version)))
+	// the forced version is registered once by the trigger on schema_migrations
+	assert.Equal(t, before+1, countHistory(int64(version)))
+
+	// removed version isn't seen by the trigger and is registered by migrator
+	beforeNil := countHistory(-1)
+	require.NoError(t, migrator.Force(ctx, -1))
+	assert.Equal(t, beforeNil+1, countHistory(-1))
+	_, _, err = migrator.Version(ctx)
+	assert.ErrorIs(t, err, ErrNilMigrationVersion)
+	require.NoError(t, migrator.Force(ctx, int(version)))
+}
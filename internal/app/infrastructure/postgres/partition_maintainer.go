@@ -0,0 +1,284 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"regexp"
+	"sync"
+	"time"
+
+	"go-service-template/internal/app/infrastructure"
+)
+
+var (
+	// ErrPartitionMaintainerBadParam - "bad partition maintainer param" error
+	ErrPartitionMaintainerBadParam = errors.New("bad partition maintainer param")
+
+	// ErrPartitionMaintainerStarted - "partition maintainer is already started" error
+	ErrPartitionMaintainerStarted = errors.New("partition maintainer is already started")
+)
+
+const (
+	defaultPartitionCheckInterval = time.Hour
+	defaultPartitionPremake       = 3
+
+	partitionMaintenanceLock = "partition_maintenance"
+	partitionSuffixLayout    = "200601"
+
+	createPartitionStatement = "SELECT partition_create_month($1, $2::date)"
+	removePartitionStatement = "SELECT partition_remove($1, $2, $3)"
+	listPartitionsStatement  = `SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = $1::regclass`
+	tableSizeStatement = `SELECT count(*), coalesce(sum(pg_total_relation_size(i.inhrelid)), 0) FROM pg_inherits i
+		WHERE i.inhparent = $1::regclass`
+)
+
+var (
+	// tableSizeMetric - total size of partitioned tables in bytes. Published by expvar as db_table_size_bytes
+	tableSizeMetric = expvar.NewMap("db_table_size_bytes")
+
+	// tablePartitionsMetric - count of partitions of partitioned tables. Published by expvar as db_table_partitions
+	tablePartitionsMetric = expvar.NewMap("db_table_partitions")
+)
+
+// PartitionMaintenanceConfig - struct for partition maintenance params.
+// Tables must be partitioned by month with partition_create_month function (see migrations).
+// Partitions are created and removed by SECURITY DEFINER functions, so the service user needs only EXECUTE on them
+type PartitionMaintenanceConfig struct {
+	// Enabled - run partition maintenance job
+	Enabled bool `env:"PG_PARTITION_MAINTENANCE_ENABLED" yaml:"enabled"`
+
+	// Tables - list of partitioned tables
+	Tables []string `yaml:"tables"`
+
+	// CheckInterval - the duration between maintenance runs
+	CheckInterval time.Duration `yaml:"checkInterval"`
+
+	// Premake - count of monthly partitions created ahead, including the current month
+	Premake int `yaml:"premake"`
+
+	// Retention - partitions with data older than retention are dropped. 0 - partitions are kept forever
+	Retention time.Duration `env:"PG_PARTITION_RETENTION" yaml:"retention"`
+
+	// ArchiveSchema - if it is set, expired partitions are detached and moved into this schema instead of being dropped
+	ArchiveSchema string `yaml:"archiveSchema"`
+}
+
+// PartitionMaintainer - background job for monthly partitioned tables. It creates future partitions,
+// drops (or archives) expired ones and publishes table size metrics.
+// Only one instance performs maintenance at a time (session level advisory lock is used)
+type PartitionMaintainer struct {
+	infrastructure.SugarLogger
+	db  *PostgresqlHandlerTX
+	cfg PartitionMaintenanceConfig
+
+	mu      sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewPartitionMaintainer returns new PartitionMaintainer
+func NewPartitionMaintainer(ctx context.Context, db *PostgresqlHandlerTX, cfg PartitionMaintenanceConfig) (*PartitionMaintainer, error) {
+	var target PartitionMaintainer
+	target.db = db
+	target.cfg = cfg
+
+	if err := target.Init(ctx); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Init - func for initialisation
+func (m *PartitionMaintainer) Init(ctx context.Context) error {
+	if m.db == nil {
+		m.LogError(ctx, "db handler is not set", ErrPartitionMaintainerBadParam)
+		return ErrPartitionMaintainerBadParam
+	}
+	if m.cfg.CheckInterval <= 0 {
+		m.cfg.CheckInterval = defaultPartitionCheckInterval
+	}
+	if m.cfg.Premake <= 0 {
+		m.cfg.Premake = defaultPartitionPremake
+	}
+	m.done = make(chan struct{})
+	return nil
+}
+
+// Start - starts maintenance. Blocks until ctx is canceled or Close is called
+func (m *PartitionMaintainer) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.started {
+		m.mu.Unlock()
+		return ErrPartitionMaintainerStarted
+	}
+	m.started = true
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.mu.Unlock()
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		m.Maintain(ctx)
+		m.collectMetrics(ctx)
+		select {
+		case <-ctx.Done():
+			m.LogInfo(ctx, "partition maintainer terminating: context canceled")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Maintain - performs one maintenance run for all tables. Run is skipped if another instance holds the lock
+func (m *PartitionMaintainer) Maintain(ctx context.Context) {
+	lock, err := m.db.TryLock(ctx, partitionMaintenanceLock)
+	if err != nil || lock == nil {
+		return
+	}
+	defer func() {
+		_ = lock.Unlock(context.Background()) //nolint:contextcheck
+	}()
+
+	now := time.Now().UTC()
+	for _, table := range m.cfg.Tables {
+		if err := m.maintainTable(ctx, table, now); err != nil {
+			m.Log(ctx).Error().Err(err).Str("table", table).Msg("partition maintenance failed")
+		}
+	}
+}
+
+// maintainTable - creates partitions for the next Premake months and removes expired partitions of the table
+func (m *PartitionMaintainer) maintainTable(ctx context.Context, table string, now time.Time) error {
+	month := monthStart(now)
+	for i := 0; i < m.cfg.Premake; i++ {
+		if err := m.db.Execute(ctx, createPartitionStatement, table, month.AddDate(0, i, 0)); err != nil {
+			return err
+		}
+	}
+	if m.cfg.Retention <= 0 {
+		return nil
+	}
+
+	partitions, err := m.partitions(ctx, table)
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		partitionMonth, ok := parsePartitionMonth(table, partition)
+		if !ok || !partitionExpired(partitionMonth, now, m.cfg.Retention) {
+			continue
+		}
+		if err = m.removePartition(ctx, table, partition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partitions - returns names of the table partitions
+func (m *PartitionMaintainer) partitions(ctx context.Context, table string) ([]string, error) {
+	rows, err := m.db.Query(ctx, listPartitionsStatement, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []string
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		res = append(res, name)
+	}
+	return res, rows.Err()
+}
+
+// removePartition - drops partition or moves it into ArchiveSchema. DDL is executed by partition_remove
+// with privileges of the schema owner
+func (m *PartitionMaintainer) removePartition(ctx context.Context, table, partition string) error {
+	if err := m.db.Execute(ctx, removePartitionStatement, table, partition, m.cfg.ArchiveSchema); err != nil {
+		return err
+	}
+	if m.cfg.ArchiveSchema == "" {
+		m.Log(ctx).Info().Str("table", table).Str("partition", partition).Msg("expired partition dropped")
+		return nil
+	}
+	m.Log(ctx).Info().Str("table", table).Str("partition", partition).Str("schema", m.cfg.ArchiveSchema).Msg("expired partition archived")
+	return nil
+}
+
+// collectMetrics - publishes size and partition count of each table
+func (m *PartitionMaintainer) collectMetrics(ctx context.Context) {
+	for _, table := range m.cfg.Tables {
+		row, err := m.db.QueryRow(ctx, tableSizeStatement, table)
+		if err != nil {
+			continue
+		}
+		var count, size int64
+		if err = row.Scan(&count, &size); err != nil {
+			m.Log(ctx).Error().Err(err).Str("table", table).Msg("can't get table size")
+			continue
+		}
+		tableSizeMetric.Set(table, intVar(size))
+		tablePartitionsMetric.Set(table, intVar(count))
+		m.Log(ctx).Debug().Str("table", table).Int64("size", size).Int64("partitions", count).Msg("table size collected")
+	}
+}
+
+// Close - stops maintenance
+func (m *PartitionMaintainer) Close(ctx context.Context) error {
+	m.mu.Lock()
+	started := m.started
+	cancel := m.cancel
+	m.mu.Unlock()
+	if !started {
+		return nil
+	}
+	cancel()
+	select {
+	case <-m.done:
+	case <-ctx.Done():
+		m.LogWarn(ctx, "partition maintainer wasn't stopped in time")
+		return ctx.Err()
+	}
+	return nil
+}
+
+var partitionSuffix = regexp.MustCompile(`^_p(\d{6})$`)
+
+// parsePartitionMonth - returns the month of the partition created by partition_create_month.
+// ok is false for partitions with other names (e.g. default partition)
+func parsePartitionMonth(table, partition string) (month time.Time, ok bool) {
+	if len(partition) <= len(table) || partition[:len(table)] != table {
+		return time.Time{}, false
+	}
+	match := partitionSuffix.FindStringSubmatch(partition[len(table):])
+	if match == nil {
+		return time.Time{}, false
+	}
+	month, err := time.Parse(partitionSuffixLayout, match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+// partitionExpired - returns true if the whole month of the partition is older than retention
+func partitionExpired(month, now time.Time, retention time.Duration) bool {
+	return !month.AddDate(0, 1, 0).After(now.Add(-retention))
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func intVar(v int64) *expvar.Int {
+	res := new(expvar.Int)
+	res.Set(v)
+	return res
+}
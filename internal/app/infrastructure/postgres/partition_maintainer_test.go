@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionMaintainer_parsePartitionMonth(t *testing.T) {
+	tests := []struct {
+		name      string
+		partition string
+		wantMonth time.Time
+		wantOk    bool
+	}{
+		{
+			name:      "PartitionMaintainer. parsePartitionMonth. Case #1. Monthly partition",
+			partition: "kafka_in_error_messages_p202301",
+			wantMonth: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantOk:    true,
+		},
+		{
+			name:      "PartitionMaintainer. parsePartitionMonth. Case #2. Default partition",
+			partition: "kafka_in_error_messages_default",
+		},
+		{
+			name:      "PartitionMaintainer. parsePartitionMonth. Case #3. Partition of another table",
+			partition: "kafka_out_error_messages_p202301",
+		},
+		{
+			name:      "PartitionMaintainer. parsePartitionMonth. Case #4. Bad month",
+			partition: "kafka_in_error_messages_p202313",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			month, ok := parsePartitionMonth("kafka_in_error_messages", tt.partition)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantMonth, month)
+		})
+	}
+}
+
+func TestPartitionMaintainer_partitionExpired(t *testing.T) {
+	now := time.Date(2023, 4, 15, 12, 0, 0, 0, time.UTC)
+	const retention = time.Hour * 24 * 30
+	tests := []struct {
+		name  string
+		month time.Time
+		want  bool
+	}{
+		{
+			name:  "PartitionMaintainer. partitionExpired. Case #1. Old partition",
+			month: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name:  "PartitionMaintainer. partitionExpired. Case #2. Partition ends before retention boundary",
+			month: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name:  "PartitionMaintainer. partitionExpired. Case #3. Partition contains retention boundary",
+			month: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:  false,
+		},
+		{
+			name:  "PartitionMaintainer. partitionExpired. Case #4. Current partition",
+			month: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, partitionExpired(tt.month, now, retention))
+		})
+	}
+}
+
+func TestPartitionMaintainer_Init(t *testing.T) {
+	_, err := NewPartitionMaintainer(context.Background(), nil, PartitionMaintenanceConfig{})
+	assert.ErrorIs(t, err, ErrPartitionMaintainerBadParam)
+
+	m, err := NewPartitionMaintainer(context.Background(), &PostgresqlHandlerTX{}, PartitionMaintenanceConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultPartitionCheckInterval, m.cfg.CheckInterval)
+	assert.Equal(t, defaultPartitionPremake, m.cfg.Premake)
+}
+
+func TestIntegrationPartitionMaintainer_Maintain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := context.Background()
+	const table = "kafka_in_error_messages"
+	now := time.Now().UTC()
+	old := monthStart(now).AddDate(0, -6, 0)
+	require.NoError(t, target.Execute(ctx, createPartitionStatement, table, old))
+
+	m, err := NewPartitionMaintainer(ctx, target, PartitionMaintenanceConfig{
+		Tables:    []string{table},
+		Premake:   2,
+		Retention: time.Hour * 24 * 60,
+	})
+	require.NoError(t, err)
+	m.Maintain(ctx)
+
+	partitions, err := m.partitions(ctx, table)
+	require.NoError(t, err)
+	assert.Contains(t, partitions, partitionName(table, monthStart(now)))
+	assert.Contains(t, partitions, partitionName(table, monthStart(now).AddDate(0, 1, 0)))
+	assert.NotContains(t, partitions, partitionName(table, old))
+
+	m.collectMetrics(ctx)
+	assert.NotNil(t, tablePartitionsMetric.Get(table))
+}
+
+func TestIntegrationPartitionMaintainer_MaintainByServiceUser(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := context.Background()
+	const table = "kafka_out_error_messages"
+	now := time.Now().UTC()
+	old := monthStart(now).AddDate(0, -6, 0)
+	require.NoError(t, target.Execute(ctx, createPartitionStatement, table, old))
+
+	db, err := NewPostgresqlHandlerTX(ctx, serviceDSN, PgPoolConfig{})
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	m, err := NewPartitionMaintainer(ctx, db, PartitionMaintenanceConfig{
+		Tables:    []string{table},
+		Premake:   5,
+		Retention: time.Hour * 24 * 60,
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.maintainTable(ctx, table, now), "service user has no DDL privileges, functions are used")
+
+	partitions, err := m.partitions(ctx, table)
+	require.NoError(t, err)
+	assert.Contains(t, partitions, partitionName(table, monthStart(now).AddDate(0, 4, 0)))
+	assert.NotContains(t, partitions, partitionName(table, old))
+
+	err = db.Execute(ctx, removePartitionStatement, table, table+"_default", "")
+	assert.Error(t, err, "default partition isn't removed")
+}
+
+func partitionName(table string, month time.Time) string {
+	return table + "_p" + month.Format(partitionSuffixLayout)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -26,9 +27,17 @@ type TxFunc func(ctx context.Context, f InFunc) error
 // PostgresqlHandlerTX struct for interactions with RDBMS Postgres.
 type PostgresqlHandlerTX struct {
 	infrastructure.SugarLogger
-	pool         *pgxpool.Pool
-	dataSource   string
-	pgPoolConfig PgPoolConfig
+	pool               *pgxpool.Pool
+	dataSource         string
+	replicaDataSources []string
+	replicas           *replicaSet
+	ids                *IDAllocator
+	pgPoolConfig       PgPoolConfig
+	tenantConns        sync.Map
+
+	ready         int32
+	monitorCancel context.CancelFunc
+	monitorDone   chan struct{}
 }
 
 // PgPoolConfig - struct for pgpool params
@@ -56,6 +65,58 @@ type PgPoolConfig struct {
 
 	// LogLevel - set log level for pgx. Available values are trace, debug, info, warn, error, none
 	LogLevel string `env:"PGX_LOG_LEVEL" yaml:"pgxLogLevel"`
+
+	// ReplicaHealthCheckPeriod - the duration between checks of the read replicas availability
+	ReplicaHealthCheckPeriod time.Duration `yaml:"replicaHealthCheckPeriod"`
+
+	// ReplicaHealthCheckTimeout - timeout for one replica availability check
+	ReplicaHealthCheckTimeout time.Duration `yaml:"replicaHealthCheckTimeout"`
+
+	// IDBlockSize - count of ids reserved from sequence per one round trip by GetNextID
+	IDBlockSize int32 `yaml:"idBlockSize"`
+
+	// SlowQueryThreshold - statements executed longer than threshold are logged with warn level. 0 disables slow query logging
+	SlowQueryThreshold time.Duration `env:"PG_SLOW_QUERY_THRESHOLD" yaml:"slowQueryThreshold"`
+
+	// LogArgValues - log statement args values in slow and failed statements. By default only types and lengths are logged
+	LogArgValues bool `env:"PG_LOG_ARG_VALUES" yaml:"logArgValues"`
+
+	// TraceStatements - create OpenTelemetry span for each statement
+	TraceStatements bool `env:"PG_TRACE_STATEMENTS" yaml:"traceStatements"`
+
+	// ReadTimeout - default timeout for Query and QueryRow. Can be overridden by WithStatementTimeout. 0 - no timeout
+	ReadTimeout time.Duration `env:"PG_READ_TIMEOUT" yaml:"readTimeout"`
+
+	// WriteTimeout - default timeout for Execute, ExecuteBatch and GetNextID. Can be overridden by WithStatementTimeout. 0 - no timeout
+	WriteTimeout time.Duration `env:"PG_WRITE_TIMEOUT" yaml:"writeTimeout"`
+
+	// StatementTimeout - statement_timeout session setting. It's applied by the server, so it limits statements executed without context deadline too. 0 - server default
+	StatementTimeout time.Duration `env:"PG_STATEMENT_TIMEOUT" yaml:"statementTimeout"`
+
+	// LockTimeout - lock_timeout session setting. Max time of waiting for a lock by statement. 0 - server default
+	LockTimeout time.Duration `env:"PG_LOCK_TIMEOUT" yaml:"lockTimeout"`
+
+	// CursorFetchSize - default count of rows fetched from cursor per one round trip
+	CursorFetchSize int32 `yaml:"cursorFetchSize"`
+
+	// TenantSchemas - multi-tenant mode. search_path of each acquired connection is set to the schema of the tenant from the context
+	TenantSchemas bool `env:"PG_TENANT_SCHEMAS" yaml:"tenantSchemas"`
+
+	// TenantSchemaPrefix - tenant schema name is TenantSchemaPrefix + tenant
+	TenantSchemaPrefix string `yaml:"tenantSchemaPrefix"`
+
+	// AuditContext - pass caller identity and requestID from the context into app.user and app.request_id
+	// transaction settings (SET LOCAL). They are used by audit_log triggers
+	AuditContext bool `env:"PG_AUDIT_CONTEXT" yaml:"auditContext"`
+
+	// ReadinessCheckPeriod - the duration between health checks of the primary pool. Pool isn't ready if the check fails
+	ReadinessCheckPeriod time.Duration `yaml:"readinessCheckPeriod"`
+
+	// ReconnectInterval - initial delay between health checks after the database is lost. Delay is doubled after each failed check
+	ReconnectInterval time.Duration `yaml:"reconnectInterval"`
+
+	// MaxReconnectInterval - max delay between health checks after the database is lost
+	MaxReconnectInterval time.Duration `yaml:"maxReconnectInterval"`
 }
 
 // DatabaseConfig - struct for db params
@@ -74,14 +135,19 @@ type DatabaseConfig struct {
 
 	// Password - password for username
 	Password string `env:"DB_PASSWORD" yaml:"password" validate:"required"`
+
+	// Replicas - list of read replicas DSNs. Optional
+	Replicas []string `env:"DB_REPLICAS" envSeparator:"," yaml:"replicas"`
 }
 
-// NewPostgresqlHandlerTX return new PostgresqlHandlerTX
-func NewPostgresqlHandlerTX(ctx context.Context, dataSource string, pgPoolConfig PgPoolConfig) (*PostgresqlHandlerTX, error) {
+// NewPostgresqlHandlerTX return new PostgresqlHandlerTX.
+// replicaDataSources is optional. If it is set, read queries outside a transaction are routed to the healthy replicas
+func NewPostgresqlHandlerTX(ctx context.Context, dataSource string, pgPoolConfig PgPoolConfig, replicaDataSources ...string) (*PostgresqlHandlerTX, error) {
 	// Format DSN
 	//("postgresql://%s:%s@%s:%s/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Dbname)
 	postgresqlHandler := new(PostgresqlHandlerTX)
 	postgresqlHandler.dataSource = dataSource
+	postgresqlHandler.replicaDataSources = replicaDataSources
 	postgresqlHandler.pgPoolConfig = pgPoolConfig
 	if err := postgresqlHandler.Init(ctx); err != nil {
 		return nil, err
@@ -91,6 +157,60 @@ func NewPostgresqlHandlerTX(ctx context.Context, dataSource string, pgPoolConfig
 
 // Init -  func for initialisation
 func (handler *PostgresqlHandlerTX) Init(ctx context.Context) error {
+	log := infrastructure.GetBaseLogger(ctx)
+	poolConfig, err := handler.buildPoolConfig(ctx, handler.dataSource)
+	if err != nil {
+		log.Error().Err(err).Msg("can't parse datasource")
+		return err
+	}
+	pool, err := handler.connectPool(ctx, poolConfig)
+	if err != nil {
+		log.Error().Err(err).Msg("can't start connection pool")
+		return err
+	}
+	handler.pool = pool
+	handler.ids = newIDAllocator(handler.fetchIDs, int(handler.pgPoolConfig.IDBlockSize))
+
+	if len(handler.replicaDataSources) > 0 {
+		if err = handler.initReplicas(ctx); err != nil {
+			pool.Close()
+			return err
+		}
+	}
+	handler.startMonitor()
+	return nil
+}
+
+// initReplicas - creates pools for read replicas and starts their health checking
+func (handler *PostgresqlHandlerTX) initReplicas(ctx context.Context) error {
+	log := infrastructure.GetBaseLogger(ctx)
+	replicas := newReplicaSet(handler.pgPoolConfig.ReplicaHealthCheckPeriod, handler.pgPoolConfig.ReplicaHealthCheckTimeout)
+	for ind, dataSource := range handler.replicaDataSources {
+		poolConfig, err := handler.buildPoolConfig(ctx, dataSource)
+		if err != nil {
+			log.Error().Err(err).Msgf("can't parse datasource for replica #%d", ind)
+			replicas.close()
+			return err
+		}
+		// Replica may be unavailable at the moment. It shouldn't prevent service from start,
+		// so the pool connects lazily and the replica state is defined by health check
+		poolConfig.LazyConnect = true
+		pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+		if err != nil {
+			log.Error().Err(err).Msgf("can't start connection pool for replica #%d", ind)
+			replicas.close()
+			return err
+		}
+		replicas.add(fmt.Sprintf("replica#%d", ind), pool)
+	}
+	replicas.checkAll(ctx)
+	replicas.start()
+	handler.replicas = replicas
+	return nil
+}
+
+// buildPoolConfig - prepare pool config for dataSource according to pgPoolConfig
+func (handler *PostgresqlHandlerTX) buildPoolConfig(ctx context.Context, dataSource string) (*pgxpool.Config, error) {
 	log := infrastructure.GetBaseLogger(ctx)
 	internalLog := zerologadapter.NewLogger(*log, zerologadapter.WithContextFunc(func(ctx context.Context, logWith zerolog.Context) zerolog.Context {
 		// You can use zerolog.hlog.IDFromCtx(ctx) or even
@@ -106,10 +226,9 @@ func (handler *PostgresqlHandlerTX) Init(ctx context.Context) error {
 		}
 		return logWith
 	}))
-	poolConfig, err := pgxpool.ParseConfig(handler.dataSource)
+	poolConfig, err := pgxpool.ParseConfig(dataSource)
 	if err != nil {
-		log.Error().Err(err).Msg("can't parse datasource")
-		return err
+		return nil, err
 	}
 
 	if handler.pgPoolConfig.MaxConns > 0 {
@@ -131,6 +250,13 @@ func (handler *PostgresqlHandlerTX) Init(ctx context.Context) error {
 	}
 
 	poolConfig.LazyConnect = handler.pgPoolConfig.LazyConnect
+	for param, value := range handler.sessionTimeouts() {
+		poolConfig.ConnConfig.RuntimeParams[param] = value
+	}
+	if handler.pgPoolConfig.TenantSchemas {
+		poolConfig.BeforeAcquire = handler.setTenantSearchPath
+		poolConfig.AfterRelease = handler.resetSearchPath
+	}
 	if handler.pgPoolConfig.LogPGX {
 		poolConfig.ConnConfig.Logger = internalLog
 		switch handler.pgPoolConfig.LogLevel {
@@ -150,13 +276,7 @@ func (handler *PostgresqlHandlerTX) Init(ctx context.Context) error {
 			poolConfig.ConnConfig.LogLevel = pgx.LogLevelInfo
 		}
 	}
-	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
-	if err != nil {
-		log.Error().Err(err).Msg("can't start connection pool")
-		return err
-	}
-	handler.pool = pool
-	return nil
+	return poolConfig, nil
 }
 
 // Commit - call commit for transaction from context
@@ -169,7 +289,8 @@ func (handler *PostgresqlHandlerTX) Commit(ctx context.Context) error {
 	err = tx.Commit(ctx)
 	if err != nil {
 		handler.LogError(ctx, "Can't commit transaction", err)
-		return err
+		// serialization failure is reported on commit
+		return TranslateError(err)
 	}
 	return err
 }
@@ -203,6 +324,13 @@ func (handler *PostgresqlHandlerTX) NewTx(ctx *context.Context) error {
 		handler.LogError(*ctx, "PostgresqlHandlerTX: can't create tx", err)
 		return err
 	}
+	if handler.pgPoolConfig.AuditContext {
+		if err = handler.setAuditContext(*ctx, newTx); err != nil {
+			_ = newTx.Rollback(*ctx)
+			handler.LogError(*ctx, "PostgresqlHandlerTX: can't set audit context", err)
+			return err
+		}
+	}
 
 	// 3. New context with transaction
 	newCtx := context.WithValue(*ctx, infrastructure.CtxKeyTransaction{}, newTx)
@@ -250,8 +378,9 @@ func (handler *PostgresqlHandlerTX) WithTx(_ context.Context) TxFunc {
 		// 2. Execute logic under transaction
 		err = f(ctx)
 		if err != nil {
-			if err = handler.Rollback(ctx); err != nil {
-				handler.LogPanic(ctx, "Can't rollback transaction", err)
+			// error of f is returned, so callers can distinguish e.g. basedbhandler.ErrNotFound
+			if rbErr := handler.Rollback(ctx); rbErr != nil {
+				handler.LogPanic(ctx, "Can't rollback transaction", rbErr)
 			}
 		} else {
 			if err = handler.Commit(ctx); err != nil {
@@ -264,30 +393,33 @@ func (handler *PostgresqlHandlerTX) WithTx(_ context.Context) TxFunc {
 
 // Execute - method for statement execution
 func (handler *PostgresqlHandlerTX) Execute(ctx context.Context, statement string, args ...interface{}) error {
+	var ct pgconn.CommandTag
 	tx, err := handler.getTx(ctx)
 	statement = handler.clearStatement(statement)
+	ctx, st := handler.startTrace(ctx, "Execute", handler.pgPoolConfig.WriteTimeout, statement, args)
 
 	if err == nil { //nolint:nestif
 		if len(args) > 0 {
-			_, err = tx.Exec(ctx, statement, args...)
+			ct, err = tx.Exec(ctx, statement, args...)
 		} else {
-			_, err = tx.Exec(ctx, statement)
+			ct, err = tx.Exec(ctx, statement)
 		}
 	} else {
 		conn, e := handler.pool.Acquire(ctx)
 		if e != nil {
 			handler.LogError(ctx, "Can't acquire connection from pool", err)
-			return e
+			return st.finish(unknownRows, e)
 		}
 		defer conn.Release()
 
 		if len(args) > 0 {
-			_, e = conn.Exec(ctx, statement, args...)
+			ct, e = conn.Exec(ctx, statement, args...)
 		} else {
-			_, e = conn.Exec(ctx, statement)
+			ct, e = conn.Exec(ctx, statement)
 		}
 		err = e
 	}
+	err = st.finish(ct.RowsAffected(), err)
 	if err != nil {
 		handler.LogError(ctx, "Can't execute statement", err)
 		return err
@@ -313,6 +445,7 @@ func (handler *PostgresqlHandlerTX) ExecuteBatch(ctx context.Context, statement
 		return nil
 	}
 	tx, err := handler.getTx(ctx)
+	ctx, st := handler.startTrace(ctx, "ExecuteBatch", handler.pgPoolConfig.WriteTimeout, statement, []interface{}{fmt.Sprintf("batch(size=%d)", len(args))})
 
 	if err == nil {
 		br = tx.SendBatch(ctx, batch)
@@ -320,27 +453,31 @@ func (handler *PostgresqlHandlerTX) ExecuteBatch(ctx context.Context, statement
 		conn, err2 := handler.pool.Acquire(ctx)
 		if err2 != nil {
 			handler.LogError(ctx, "Can't acquire connection from pool", err)
-			return err2
+			return st.finish(unknownRows, err2)
 		}
 		defer conn.Release()
 		br = conn.SendBatch(ctx, batch)
 	}
 	ct, err = br.Exec()
-	defer br.Close() //nolint:errcheck
+	// results must be closed before statement timeout is released by finish
+	if closeErr := br.Close(); err == nil {
+		err = closeErr
+	}
+	err = st.finish(ct.RowsAffected(), err)
 	if err != nil {
 		handler.LogError(ctx, "Can't execute batch statement", err)
 		return err
 	}
-	fmt.Println(ct.RowsAffected())
 	return nil
 }
 
-// QueryRow -  method for  one row SELECT statement
+// QueryRow -  method for  one row SELECT statement. Outside a transaction connection is held until Scan is called
 func (handler *PostgresqlHandlerTX) QueryRow(ctx context.Context, statement string, args ...interface{}) (basedbhandler.Row, error) {
 	var row pgx.Row
 
 	statement = handler.clearStatement(statement)
 	tx, err := handler.getTx(ctx)
+	ctx, st := handler.startTrace(ctx, "QueryRow", handler.pgPoolConfig.ReadTimeout, statement, args)
 	if err == nil { //nolint:nestif
 		if len(args) > 0 {
 			row = tx.QueryRow(ctx, statement, args...)
@@ -348,27 +485,30 @@ func (handler *PostgresqlHandlerTX) QueryRow(ctx context.Context, statement stri
 			row = tx.QueryRow(ctx, statement)
 		}
 	} else {
-		conn, err := handler.pool.Acquire(ctx)
+		conn, err := handler.acquireForRead(ctx)
 		if err != nil {
 			handler.LogError(ctx, "Can't acquire connection from pool", err)
-			return nil, err
+			return nil, st.finish(unknownRows, err)
 		}
-		defer conn.Release()
+		// connection is held until the row is scanned
+		st.release = conn.Release
 		if len(args) > 0 {
 			row = conn.QueryRow(ctx, statement, args...)
 		} else {
 			row = conn.QueryRow(ctx, statement)
 		}
 	}
-	return row, nil
+	return &tracedRow{Row: row, trace: st}, nil
 }
 
-// Query -  method for arbitrary SELECT statement
+// Query -  method for arbitrary SELECT statement. Outside a transaction connection is held until rows are read or closed.
+// For large result sets use OpenCursor
 func (handler *PostgresqlHandlerTX) Query(ctx context.Context, statement string, args ...interface{}) (basedbhandler.Rows, error) {
 	var rows pgx.Rows
 
 	statement = handler.clearStatement(statement)
 	tx, err := handler.getTx(ctx)
+	ctx, st := handler.startTrace(ctx, "Query", handler.pgPoolConfig.ReadTimeout, statement, args)
 
 	if err == nil { //nolint:nestif
 		if len(args) > 0 {
@@ -377,12 +517,13 @@ func (handler *PostgresqlHandlerTX) Query(ctx context.Context, statement string,
 			rows, err = tx.Query(ctx, statement)
 		}
 	} else {
-		conn, e := handler.pool.Acquire(ctx)
+		conn, e := handler.acquireForRead(ctx)
 		if e != nil {
 			handler.LogError(ctx, "Can't acquire connection from pool", err)
-			return nil, e
+			return nil, st.finish(unknownRows, e)
 		}
-		defer conn.Release()
+		// connection is held until the rows are read or closed
+		st.release = conn.Release
 		if len(args) > 0 {
 			rows, e = conn.Query(ctx, statement, args...)
 		} else {
@@ -391,42 +532,49 @@ func (handler *PostgresqlHandlerTX) Query(ctx context.Context, statement string,
 		err = e
 	}
 	if err != nil {
+		err = st.finish(unknownRows, err)
 		handler.LogError(ctx, "Can't execute query", err)
 		return nil, err
 	}
-	return rows, nil
+	return &tracedRows{Rows: rows, trace: st}, nil
 }
 
-// GetNextID - get next value from sequence
+// GetNextID - get next value from sequence. Values are reserved by blocks of PgPoolConfig.IDBlockSize size
+// and are handed out from memory, so ids are unique but not gapless
 func (handler *PostgresqlHandlerTX) GetNextID(ctx context.Context, sequenceName string) (int64, error) {
-	var row pgx.Row
-	var id int64
-
-	statement := fmt.Sprintf("SELECT nextval('%s')", sequenceName)
-
-	row, err := handler.QueryRow(ctx, statement)
-	if err != nil {
-		handler.LogError(ctx, "can't get next value from sequence", err)
-		return 0, err
-	}
-
-	err = row.Scan(&id)
-	if err != nil {
-		handler.LogError(ctx, "can't scan value from response", err)
-		return 0, err
-	}
-
-	return id, nil
+	return handler.ids.NextID(ctx, sequenceName)
 }
 
 // Close - close connection pool
 func (handler *PostgresqlHandlerTX) Close(_ context.Context) error {
 	if handler != nil {
+		handler.stopMonitor()
 		handler.pool.Close()
+		if handler.replicas != nil {
+			handler.replicas.close()
+		}
 	}
 	return nil
 }
 
+// acquireForRead - acquire connection for read only statement. Connection is taken from a healthy replica if
+// replicas are configured and the primary isn't forced by WithPrimary. Otherwise, connection is taken from the primary pool
+func (handler *PostgresqlHandlerTX) acquireForRead(ctx context.Context) (*pgxpool.Conn, error) {
+	if handler.replicas == nil || IsPrimaryForced(ctx) {
+		return handler.pool.Acquire(ctx)
+	}
+	if r := handler.replicas.next(); r != nil {
+		conn, err := r.pool.Acquire(ctx)
+		if err == nil {
+			return conn, nil
+		}
+		// replica is unavailable. It will be checked again by the health check. Fallback to primary
+		handler.Log(ctx).Warn().Err(err).Str("replica", r.name).Msg("can't acquire connection from replica. fallback to primary")
+		r.setHealthy(false)
+	}
+	return handler.pool.Acquire(ctx)
+}
+
 func (handler *PostgresqlHandlerTX) clearStatement(query string) string {
 	buf := strings.ReplaceAll(query, "\n", " ")
 	buf = strings.ReplaceAll(buf, "\t", " ")
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
 )
 
 func TestIntegrationPostgresqlHandlerTX_getTx(t *testing.T) {
@@ -140,6 +141,33 @@ func TestIntegrationPostgresqlHandlerTX_Transaction(t *testing.T) {
 	}
 }
 
+func TestIntegrationPostgresqlHandlerTX_TransactionError(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := context.Background()
+	name := "PostgresqlHandlerTX. Rollback with error"
+	err := target.WithTx(ctx)(ctx, func(ctx context.Context) error {
+		if err := target.Execute(ctx, "insert into test_table (a,b) values ($1, $2)", 10, name); err != nil {
+			return err
+		}
+		row, err := target.QueryRow(ctx, "select a from test_table where b = $1", "absent")
+		if err != nil {
+			return err
+		}
+		var a int
+		return row.Scan(&a)
+	})
+	// error of the function is returned after rollback
+	assert.ErrorIs(t, err, basedbhandler.ErrNotFound)
+
+	row, err := target.QueryRow(ctx, "select count(*) from test_table where b = $1", name)
+	assert.NoError(t, err)
+	var cnt int
+	assert.NoError(t, row.Scan(&cnt))
+	assert.Equal(t, 0, cnt)
+}
+
 func TestIntegrationPostgresqlHandlerTX_ExecuteBatch(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skip integration tests in short mode")
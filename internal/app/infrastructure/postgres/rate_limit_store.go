@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	// rateLimitCleanupInterval - buckets not used for this interval are deleted
+	rateLimitCleanupInterval = time.Hour
+
+	// tokens are added to the bucket for the time since the last request, then one token is taken if available
+	takeRateLimitStatement = `INSERT INTO rate_limits AS r (key, tokens, allowed) VALUES ($1, $3::float8 - 1, true)
+		ON CONFLICT (key) DO UPDATE SET
+			allowed = least($3::float8, r.tokens + extract(epoch FROM now() - r.update_time) * $2::float8) >= 1,
+			tokens = least($3::float8, r.tokens + extract(epoch FROM now() - r.update_time) * $2::float8) -
+				CASE WHEN least($3::float8, r.tokens + extract(epoch FROM now() - r.update_time) * $2::float8) >= 1
+				THEN 1 ELSE 0 END,
+			update_time = now()
+		RETURNING tokens, allowed`
+	cleanupRateLimitsStatement = `DELETE FROM rate_limits WHERE update_time < now() - $1 * interval '1 millisecond'`
+)
+
+// RateLimitStore - token buckets in rate_limits table. Limits are shared by all instances of the service
+type RateLimitStore struct {
+	infrastructure.SugarLogger
+	db          *PostgresqlHandlerTX
+	lastCleanup int64
+}
+
+// NewRateLimitStore returns new RateLimitStore
+func NewRateLimitStore(db *PostgresqlHandlerTX) *RateLimitStore {
+	var target RateLimitStore
+	target.db = db
+	target.lastCleanup = time.Now().UnixNano()
+	return &target
+}
+
+// Take - takes one token from the bucket of the key
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit infrastructure.RateLimit) (infrastructure.RateLimitResult, error) {
+	s.cleanup(ctx)
+	row, err := s.db.QueryRow(WithPrimary(ctx), takeRateLimitStatement, key, limit.Rate, limit.Burst)
+	if err != nil {
+		return infrastructure.RateLimitResult{}, err
+	}
+	var (
+		tokens  float64
+		allowed bool
+	)
+	if err = row.Scan(&tokens, &allowed); err != nil {
+		return infrastructure.RateLimitResult{}, err
+	}
+	return infrastructure.NewRateLimitResult(limit, tokens, allowed), nil
+}
+
+// cleanup - deletes unused buckets in background once per rateLimitCleanupInterval
+func (s *RateLimitStore) cleanup(ctx context.Context) {
+	last := atomic.LoadInt64(&s.lastCleanup)
+	now := time.Now().UnixNano()
+	if time.Duration(now-last) < rateLimitCleanupInterval || !atomic.CompareAndSwapInt64(&s.lastCleanup, last, now) {
+		return
+	}
+	l := s.Log(ctx)
+	go func() {
+		ctx := context.WithValue(context.Background(), infrastructure.CtxKeyLogger{}, l)
+		if err := s.db.Execute(ctx, cleanupRateLimitsStatement, rateLimitCleanupInterval.Milliseconds()); err != nil {
+			l.Warn().Err(err).Msg("can't delete unused rate limits")
+		}
+	}()
+}
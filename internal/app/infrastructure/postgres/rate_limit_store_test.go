@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/infrastructure"
+)
+
+func TestIntegrationRateLimitStore_Take(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	store := NewRateLimitStore(target)
+	limit := infrastructure.RateLimit{Rate: 0.1, Burst: 2}
+	key := "test:" + time.Now().String()
+
+	res, err := store.Take(ctx, key, limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+
+	res, err = store.Take(ctx, key, limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	res, err = store.Take(ctx, key, limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Greater(t, res.RetryAfter, time.Second*9)
+
+	// other instance shares the bucket
+	res, err = NewRateLimitStore(target).Take(ctx, key, limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	defaultReplicaHealthCheckPeriod  = time.Second * 10
+	defaultReplicaHealthCheckTimeout = time.Second * 3
+)
+
+// WithPrimary - returns context which forces read queries to the primary database
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, infrastructure.CtxKeyPrimary{}, true)
+}
+
+// IsPrimaryForced - checks if read queries must be executed on the primary database
+func IsPrimaryForced(ctx context.Context) bool {
+	forced, ok := ctx.Value(infrastructure.CtxKeyPrimary{}).(bool)
+	return ok && forced
+}
+
+// replica - pool for one read replica with its state
+type replica struct {
+	name    string
+	pool    *pgxpool.Pool
+	healthy int32
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
+
+func (r *replica) setHealthy(healthy bool) {
+	var val int32
+	if healthy {
+		val = 1
+	}
+	atomic.StoreInt32(&r.healthy, val)
+}
+
+// replicaSet - set of read replicas. Replicas are selected by round-robin among healthy ones
+type replicaSet struct {
+	infrastructure.SugarLogger
+	replicas     []*replica
+	counter      uint32
+	checkPeriod  time.Duration
+	checkTimeout time.Duration
+	stop         chan struct{}
+	wg           sync.WaitGroup
+	closeOnce    sync.Once
+}
+
+func newReplicaSet(checkPeriod time.Duration, checkTimeout time.Duration) *replicaSet {
+	if checkPeriod <= 0 {
+		checkPeriod = defaultReplicaHealthCheckPeriod
+	}
+	if checkTimeout <= 0 {
+		checkTimeout = defaultReplicaHealthCheckTimeout
+	}
+	return &replicaSet{
+		checkPeriod:  checkPeriod,
+		checkTimeout: checkTimeout,
+		stop:         make(chan struct{}),
+	}
+}
+
+// add - adds replica into the set. New replica is considered unhealthy until the first successful check
+func (s *replicaSet) add(name string, pool *pgxpool.Pool) {
+	s.replicas = append(s.replicas, &replica{name: name, pool: pool})
+}
+
+// next - returns next healthy replica. If there are no healthy replicas, nil is returned
+func (s *replicaSet) next() *replica {
+	cnt := uint32(len(s.replicas))
+	if cnt == 0 {
+		return nil
+	}
+	start := atomic.AddUint32(&s.counter, 1)
+	for i := uint32(0); i < cnt; i++ {
+		r := s.replicas[(start+i)%cnt]
+		if r.isHealthy() {
+			return r
+		}
+	}
+	return nil
+}
+
+// start - starts background health checking
+func (s *replicaSet) start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.checkPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.checkAll(context.Background())
+			}
+		}
+	}()
+}
+
+// checkAll - checks availability of all replicas and updates their state
+func (s *replicaSet) checkAll(ctx context.Context) {
+	for _, r := range s.replicas {
+		checkCtx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+		err := r.pool.Ping(checkCtx)
+		cancel()
+
+		wasHealthy := r.isHealthy()
+		r.setHealthy(err == nil)
+		switch {
+		case err != nil && wasHealthy:
+			s.Log(ctx).Warn().Err(err).Str("replica", r.name).Msg("replica is unavailable")
+		case err != nil:
+			s.Log(ctx).Debug().Err(err).Str("replica", r.name).Msg("replica is still unavailable")
+		case !wasHealthy:
+			s.Log(ctx).Info().Str("replica", r.name).Msg("replica is available")
+		}
+	}
+}
+
+// close - stops health checking and closes replicas pools
+func (s *replicaSet) close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		s.wg.Wait()
+		for _, r := range s.replicas {
+			r.pool.Close()
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaSet_next(t *testing.T) {
+	type args struct {
+		healthy []bool
+		calls   int
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "replicaSet. next. Case #1. Empty set",
+			args: args{healthy: nil, calls: 2},
+			want: []string{"", ""},
+		},
+		{
+			name: "replicaSet. next. Case #2. Round-robin among all replicas",
+			args: args{healthy: []bool{true, true, true}, calls: 4},
+			want: []string{"r1", "r2", "r0", "r1"},
+		},
+		{
+			name: "replicaSet. next. Case #3. Unhealthy replica is skipped",
+			args: args{healthy: []bool{true, false, true}, calls: 3},
+			want: []string{"r2", "r2", "r0"},
+		},
+		{
+			name: "replicaSet. next. Case #4. No healthy replicas",
+			args: args{healthy: []bool{false, false}, calls: 2},
+			want: []string{"", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newReplicaSet(0, 0)
+			for ind, healthy := range tt.args.healthy {
+				s.add("r"+string(rune('0'+ind)), nil)
+				s.replicas[ind].setHealthy(healthy)
+			}
+			var got []string
+			for i := 0; i < tt.args.calls; i++ {
+				name := ""
+				if r := s.next(); r != nil {
+					name = r.name
+				}
+				got = append(got, name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithPrimary(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, IsPrimaryForced(ctx))
+	assert.True(t, IsPrimaryForced(WithPrimary(ctx)))
+}
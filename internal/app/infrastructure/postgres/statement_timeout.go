@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+// WithStatementTimeout - returns context which overrides default timeout for statements executed with it.
+// timeout <= 0 disables default timeout, so only ctx deadline is applied
+func WithStatementTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, infrastructure.CtxKeyStatementTimeout{}, timeout)
+}
+
+// StatementTimeoutError - statement is canceled because of timeout. errors.Is(err, basedbhandler.ErrQueryTimeout) is true
+type StatementTimeoutError struct {
+	Operation string
+	Err       error
+}
+
+// Error - implementation of error interface
+func (e *StatementTimeoutError) Error() string {
+	return e.Operation + ": " + basedbhandler.ErrQueryTimeout.Error() + ": " + e.Err.Error()
+}
+
+// Unwrap - returns original error
+func (e *StatementTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is - matches basedbhandler.ErrQueryTimeout
+func (e *StatementTimeoutError) Is(target error) bool {
+	return target == basedbhandler.ErrQueryTimeout //nolint:errorlint,goerr113
+}
+
+// withTimeout - applies timeout to ctx. Timeout from WithStatementTimeout has priority over defaultTimeout.
+// Returned cancel func must be called when statement results are read
+func (handler *PostgresqlHandlerTX) withTimeout(ctx context.Context, defaultTimeout time.Duration) (context.Context, context.CancelFunc) {
+	timeout := defaultTimeout
+	if override, ok := ctx.Value(infrastructure.CtxKeyStatementTimeout{}).(time.Duration); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timeoutError - converts errors caused by statement timeout (see withTimeout) or statement_timeout into
+// StatementTimeoutError. ctx is the context of the caller without statement timeout: if it is expired or canceled,
+// the statement was interrupted by the caller, so the error isn't a statement timeout. Other errors are returned as is
+func timeoutError(ctx context.Context, operation string, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	var timeoutErr *StatementTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return err
+	}
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), pgconn.Timeout(err):
+	case errors.As(err, &pgErr) && pgErr.Code == pgerrcode.QueryCanceled:
+	default:
+		return err
+	}
+	return &StatementTimeoutError{Operation: operation, Err: err}
+}
+
+// sessionTimeouts - returns runtime params for statement_timeout and lock_timeout session settings
+func (handler *PostgresqlHandlerTX) sessionTimeouts() map[string]string {
+	params := make(map[string]string)
+	if handler.pgPoolConfig.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(handler.pgPoolConfig.StatementTimeout.Milliseconds(), 10)
+	}
+	if handler.pgPoolConfig.LockTimeout > 0 {
+		params["lock_timeout"] = strconv.FormatInt(handler.pgPoolConfig.LockTimeout.Milliseconds(), 10)
+	}
+	return params
+}
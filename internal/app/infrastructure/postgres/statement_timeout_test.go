@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+func TestTimeoutError(t *testing.T) {
+	errSome := errors.New("some error")
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		err         error
+		wantTimeout bool
+	}{
+		{
+			name:        "timeoutError. Case #1. Nil error",
+			err:         nil,
+			wantTimeout: false,
+		},
+		{
+			name:        "timeoutError. Case #2. Other error",
+			err:         errSome,
+			wantTimeout: false,
+		},
+		{
+			name:        "timeoutError. Case #3. Context deadline",
+			err:         fmt.Errorf("timeout: %w", context.DeadlineExceeded),
+			wantTimeout: true,
+		},
+		{
+			name:        "timeoutError. Case #4. statement_timeout",
+			err:         &pgconn.PgError{Code: pgerrcode.QueryCanceled},
+			wantTimeout: true,
+		},
+		{
+			name:        "timeoutError. Case #5. lock_timeout is retryable, not timeout",
+			err:         &pgconn.PgError{Code: pgerrcode.LockNotAvailable},
+			wantTimeout: false,
+		},
+		{
+			name:        "timeoutError. Case #6. Other postgres error",
+			err:         &pgconn.PgError{Code: pgerrcode.UniqueViolation},
+			wantTimeout: false,
+		},
+		{
+			name:        "timeoutError. Case #7. Deadline of the caller isn't statement timeout",
+			ctx:         expired,
+			err:         fmt.Errorf("timeout: %w", context.DeadlineExceeded),
+			wantTimeout: false,
+		},
+		{
+			name:        "timeoutError. Case #8. Statement canceled by the caller isn't statement timeout",
+			ctx:         expired,
+			err:         &pgconn.PgError{Code: pgerrcode.QueryCanceled},
+			wantTimeout: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			got := timeoutError(ctx, "Query", tt.err)
+			assert.Equal(t, tt.wantTimeout, errors.Is(got, basedbhandler.ErrQueryTimeout))
+			if tt.err != nil {
+				assert.True(t, errors.Is(got, tt.err))
+			}
+		})
+	}
+}
+
+func TestPostgresqlHandlerTX_withTimeout(t *testing.T) {
+	handler := &PostgresqlHandlerTX{}
+
+	ctx, cancel := handler.withTimeout(context.Background(), time.Minute)
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	cancel()
+
+	ctx, cancel = handler.withTimeout(WithStatementTimeout(context.Background(), time.Second), time.Minute)
+	deadline, ok = ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Second/2)
+	cancel()
+
+	ctx, cancel = handler.withTimeout(WithStatementTimeout(context.Background(), 0), time.Minute)
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+	cancel()
+}
+
+func TestPostgresqlHandlerTX_sessionTimeouts(t *testing.T) {
+	handler := &PostgresqlHandlerTX{pgPoolConfig: PgPoolConfig{StatementTimeout: time.Minute, LockTimeout: time.Second * 5}}
+	assert.Equal(t, map[string]string{"statement_timeout": "60000", "lock_timeout": "5000"}, handler.sessionTimeouts())
+
+	handler = &PostgresqlHandlerTX{}
+	assert.Empty(t, handler.sessionTimeouts())
+}
+
+func TestIntegrationPostgresqlHandlerTX_StatementTimeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := WithStatementTimeout(context.Background(), time.Millisecond*100)
+
+	err := target.Execute(ctx, "SELECT pg_sleep(1)")
+	assert.ErrorIs(t, err, basedbhandler.ErrQueryTimeout)
+
+	// pool is usable after canceled statement
+	var val int
+	row, err := target.QueryRow(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	assert.NoError(t, row.Scan(&val))
+	assert.Equal(t, 1, val)
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"go-service-template/internal/app/infrastructure"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "go-service-template/internal/app/infrastructure/postgres"
+
+	// maxArgLength - max length of argument value in logs
+	maxArgLength = 64
+
+	// unknownRows - row count isn't known (e.g. rows weren't read)
+	unknownRows = -1
+)
+
+// statementTrace - measures one statement execution. Statements slower than PgPoolConfig.SlowQueryThreshold
+// are logged with warn level. If PgPoolConfig.TraceStatements is set, OpenTelemetry span is created for each statement.
+// parent is the context of the caller, ctx is the context with statement timeout
+type statementTrace struct {
+	handler   *PostgresqlHandlerTX
+	parent    context.Context
+	ctx       context.Context
+	operation string
+	statement string
+	args      []interface{}
+	start     time.Time
+	span      trace.Span
+	cancel    context.CancelFunc
+	release   func()
+	finished  bool
+}
+
+// startTrace - starts statement measuring and applies statement timeout (see withTimeout).
+// Returned context contains span (if tracing is enabled) and deadline
+func (handler *PostgresqlHandlerTX) startTrace(ctx context.Context, operation string, timeout time.Duration, statement string, args []interface{}) (context.Context, *statementTrace) {
+	t := &statementTrace{
+		handler:   handler,
+		parent:    ctx,
+		operation: operation,
+		statement: statement,
+		args:      args,
+		start:     time.Now(),
+	}
+	ctx, t.cancel = handler.withTimeout(ctx, timeout)
+	if handler.pgPoolConfig.TraceStatements {
+		ctx, t.span = otel.Tracer(tracerName).Start(ctx, operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "postgresql"),
+				attribute.String("db.operation", operation),
+				attribute.String("db.statement", statement),
+			))
+	}
+	t.ctx = ctx
+	return ctx, t
+}
+
+// finish - stops measuring, releases statement timeout and connection (if release is set).
+// rows - count of returned or affected rows, unknownRows if it isn't known. Returns err converted by translateError
+func (t *statementTrace) finish(rows int64, err error) error {
+	err = translateError(t.parent, t.operation, err)
+	if t.finished {
+		return err
+	}
+	t.finished = true
+	latency := time.Since(t.start)
+	t.cancel()
+	if t.release != nil {
+		t.release()
+	}
+
+	if t.span != nil {
+		t.span.SetAttributes(attribute.Int64("db.rows", rows))
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			t.span.RecordError(err)
+			t.span.SetStatus(codes.Error, err.Error())
+		}
+		t.span.End()
+	}
+
+	threshold := t.handler.pgPoolConfig.SlowQueryThreshold
+	log := infrastructure.GetBaseLogger(t.ctx)
+	event := log.Trace()
+	if threshold > 0 && latency >= threshold {
+		event = log.Warn()
+	}
+	if !event.Enabled() {
+		return err
+	}
+	if requestID, ok := t.ctx.Value(infrastructure.CtxKeyRequestID{}).(string); ok {
+		event = event.Str(infrastructure.RequestIDField, requestID)
+	}
+	if correlationID, ok := t.ctx.Value(infrastructure.CtxKeyCorrelationID{}).(string); ok {
+		event = event.Str(infrastructure.CorrelationIDField, correlationID)
+	}
+	if err != nil {
+		event = event.Err(err)
+	}
+	event.
+		Str("operation", t.operation).
+		Str("statement", t.statement).
+		Strs("args", sanitizeArgs(t.args, t.handler.pgPoolConfig.LogArgValues)).
+		Int64("rows", rows).
+		Dur("latency", latency).
+		Msg("statement executed")
+	return err
+}
+
+// sanitizeArgs - converts statement args for logging. Only types and lengths are logged unless withValues is set,
+// then long values are truncated. Binary values are always replaced by their length
+func sanitizeArgs(args []interface{}, withValues bool) []string {
+	res := make([]string, len(args))
+	for ind, arg := range args {
+		var val string
+		switch v := arg.(type) {
+		case nil:
+			val = "NULL"
+		case []byte:
+			val = fmt.Sprintf("[]byte(len=%d)", len(v))
+		case string:
+			if !withValues {
+				val = fmt.Sprintf("string(len=%d)", len(v))
+				break
+			}
+			val = v
+		default:
+			if !withValues {
+				val = fmt.Sprintf("%T", v)
+				break
+			}
+			switch v := v.(type) {
+			case time.Time:
+				val = v.Format(time.RFC3339Nano)
+			case fmt.Stringer:
+				val = v.String()
+			default:
+				val = fmt.Sprintf("%v", v)
+			}
+		}
+		if len(val) > maxArgLength {
+			val = val[:maxArgLength] + "..."
+		}
+		res[ind] = val
+	}
+	return res
+}
+
+// tracedRows - wrapper for pgx.Rows. Counts read rows and finishes trace when rows are exhausted or closed
+type tracedRows struct {
+	pgx.Rows
+	trace *statementTrace
+	count int64
+}
+
+// Next - implementation of pgx.Rows
+func (r *tracedRows) Next() bool {
+	if r.Rows.Next() {
+		r.count++
+		return true
+	}
+	_ = r.trace.finish(r.count, r.Rows.Err())
+	return false
+}
+
+// Close - implementation of pgx.Rows
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+	_ = r.trace.finish(r.count, r.Rows.Err())
+}
+
+// Err - implementation of pgx.Rows. Errors are translated by translateError
+func (r *tracedRows) Err() error {
+	return translateError(r.trace.parent, r.trace.operation, r.Rows.Err())
+}
+
+// Scan - implementation of pgx.Rows. Errors are translated by translateError
+func (r *tracedRows) Scan(dest ...interface{}) error {
+	return translateError(r.trace.parent, r.trace.operation, r.Rows.Scan(dest...))
+}
+
+// tracedRow - wrapper for pgx.Row. Finishes trace on Scan
+type tracedRow struct {
+	pgx.Row
+	trace *statementTrace
+}
+
+// Scan - implementation of pgx.Row
+func (r *tracedRow) Scan(dest ...interface{}) error {
+	err := r.Row.Scan(dest...)
+	var rows int64 = 1
+	if err != nil {
+		rows = 0
+	}
+	return r.trace.finish(rows, err)
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeArgs(t *testing.T) {
+	tm := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	long := make([]byte, 0, maxArgLength+10)
+	for i := 0; i < maxArgLength+10; i++ {
+		long = append(long, 'a')
+	}
+	tests := []struct {
+		name       string
+		args       []interface{}
+		withValues bool
+		want       []string
+	}{
+		{
+			name: "sanitizeArgs. Case #1. Empty args",
+			args: nil,
+			want: []string{},
+		},
+		{
+			name: "sanitizeArgs. Case #2. Only types and lengths are logged by default",
+			args: []interface{}{1, "secret@mail.com", nil, tm},
+			want: []string{"int", "string(len=15)", "NULL", "time.Time"},
+		},
+		{
+			name:       "sanitizeArgs. Case #3. Simple values",
+			args:       []interface{}{1, "str", nil, tm},
+			withValues: true,
+			want:       []string{"1", "str", "NULL", "2022-01-02T03:04:05Z"},
+		},
+		{
+			name:       "sanitizeArgs. Case #4. Binary value is replaced by length",
+			args:       []interface{}{[]byte{1, 2, 3}},
+			withValues: true,
+			want:       []string{"[]byte(len=3)"},
+		},
+		{
+			name:       "sanitizeArgs. Case #5. Long value is truncated",
+			args:       []interface{}{string(long)},
+			withValues: true,
+			want:       []string{string(long[:maxArgLength]) + "..."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeArgs(tt.args, tt.withValues))
+		})
+	}
+}
+
+type fakeRows struct {
+	pgx.Rows
+	left   int
+	closed bool
+}
+
+func (r *fakeRows) Next() bool {
+	if r.left == 0 {
+		return false
+	}
+	r.left--
+	return true
+}
+
+func (r *fakeRows) Err() error {
+	return nil
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+type fakeRow struct {
+	err error
+}
+
+func (r fakeRow) Scan(_ ...interface{}) error {
+	return r.err
+}
+
+func TestTracedRows(t *testing.T) {
+	handler := &PostgresqlHandlerTX{}
+
+	_, st := handler.startTrace(context.Background(), "Query", 0, "SELECT 1", nil)
+	rows := &tracedRows{Rows: &fakeRows{left: 3}, trace: st}
+	for rows.Next() {
+	}
+	assert.True(t, st.finished)
+	assert.Equal(t, int64(3), rows.count)
+
+	_, st = handler.startTrace(context.Background(), "Query", 0, "SELECT 1", nil)
+	inner := &fakeRows{left: 3}
+	rows = &tracedRows{Rows: inner, trace: st}
+	rows.Next()
+	assert.False(t, st.finished)
+	rows.Close()
+	assert.True(t, st.finished)
+	assert.True(t, inner.closed)
+}
+
+func TestTracedRow(t *testing.T) {
+	handler := &PostgresqlHandlerTX{}
+
+	_, st := handler.startTrace(context.Background(), "QueryRow", 0, "SELECT 1", nil)
+	row := &tracedRow{Row: fakeRow{}, trace: st}
+	assert.NoError(t, row.Scan())
+	assert.True(t, st.finished)
+
+	_, st = handler.startTrace(context.Background(), "QueryRow", 0, "SELECT 1", nil)
+	row = &tracedRow{Row: fakeRow{err: pgx.ErrNoRows}, trace: st}
+	assert.True(t, errors.Is(row.Scan(), pgx.ErrNoRows))
+	assert.True(t, st.finished)
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	setSearchPathStatement   = "SELECT set_config('search_path', $1, false)"
+	resetSearchPathStatement = "RESET search_path"
+	resetSearchPathTimeout   = time.Second * 3
+)
+
+// tenantSchema - returns schema name for the tenant
+func (handler *PostgresqlHandlerTX) tenantSchema(tenant string) string {
+	return handler.pgPoolConfig.TenantSchemaPrefix + tenant
+}
+
+// withoutTenant - returns ctx without tenant, so the statement is executed in the default search_path.
+// It is used for tables shared by all tenants
+func withoutTenant(ctx context.Context) context.Context {
+	if _, ok := infrastructure.TenantFromContext(ctx); !ok {
+		return ctx
+	}
+	return infrastructure.WithTenant(ctx, "")
+}
+
+// setTenantSearchPath - pgxpool BeforeAcquire hook. Sets search_path to the schema of the tenant from ctx.
+// If there is no tenant in ctx, connection keeps default search_path (see 02.schema.sql).
+// Returning false destroys the connection, so connection with unknown search_path is never used
+func (handler *PostgresqlHandlerTX) setTenantSearchPath(ctx context.Context, conn *pgx.Conn) bool {
+	tenant, ok := infrastructure.TenantFromContext(ctx)
+	if !ok {
+		return true
+	}
+	if err := infrastructure.ValidateTenant(tenant); err != nil {
+		handler.LogError(ctx, "can't set search_path for tenant "+tenant, err)
+		return false
+	}
+	schema := pgx.Identifier{handler.tenantSchema(tenant)}.Sanitize()
+	if _, err := conn.Exec(ctx, setSearchPathStatement, schema); err != nil {
+		handler.LogError(ctx, "can't set search_path for tenant "+tenant, err)
+		return false
+	}
+	handler.pruneTenantConns()
+	handler.tenantConns.Store(conn, tenant)
+	return true
+}
+
+// pruneTenantConns - forgets closed connections. Connection destroyed by the pool (e.g. after canceled query)
+// isn't passed to AfterRelease hook
+func (handler *PostgresqlHandlerTX) pruneTenantConns() {
+	handler.tenantConns.Range(func(key, _ interface{}) bool {
+		if conn, ok := key.(*pgx.Conn); ok && conn.IsClosed() {
+			handler.tenantConns.Delete(key)
+		}
+		return true
+	})
+}
+
+// resetSearchPath - pgxpool AfterRelease hook. Resets search_path set by setTenantSearchPath,
+// so the connection doesn't leak tenant schema to the next user
+func (handler *PostgresqlHandlerTX) resetSearchPath(conn *pgx.Conn) bool {
+	if _, ok := handler.tenantConns.LoadAndDelete(conn); !ok {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resetSearchPathTimeout)
+	defer cancel()
+	if _, err := conn.Exec(ctx, resetSearchPathStatement); err != nil {
+		handler.LogError(ctx, "can't reset search_path", err)
+		return false
+	}
+	return true
+}
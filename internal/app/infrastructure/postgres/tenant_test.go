@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/infrastructure"
+)
+
+func TestPostgresqlHandlerTX_setTenantSearchPath(t *testing.T) {
+	handler := &PostgresqlHandlerTX{pgPoolConfig: PgPoolConfig{TenantSchemas: true, TenantSchemaPrefix: "tenant_"}}
+	assert.Equal(t, "tenant_t1", handler.tenantSchema("t1"))
+
+	// no tenant - connection is used as is
+	assert.True(t, handler.setTenantSearchPath(context.Background(), nil))
+	// bad tenant - connection is rejected before any I/O
+	assert.False(t, handler.setTenantSearchPath(infrastructure.WithTenant(context.Background(), "Bad Tenant"), nil))
+}
+
+func TestIntegrationPostgresqlHandlerTX_TenantSchemas(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip integration tests in short mode")
+	}
+	ctx := context.Background()
+	for _, schema := range []string{"tenant_t1", "tenant_t2"} {
+		require.NoError(t, target.Execute(ctx, "CREATE SCHEMA IF NOT EXISTS "+schema))
+		require.NoError(t, target.Execute(ctx, "CREATE TABLE IF NOT EXISTS "+schema+".tenant_info (name text)"))
+		require.NoError(t, target.Execute(ctx, "DELETE FROM "+schema+".tenant_info"))
+		require.NoError(t, target.Execute(ctx, "INSERT INTO "+schema+".tenant_info (name) VALUES ($1)", schema))
+	}
+
+	handler, err := NewPostgresqlHandlerTX(ctx, dsn, PgPoolConfig{MaxConns: 1, TenantSchemas: true, TenantSchemaPrefix: "tenant_"})
+	require.NoError(t, err)
+	defer handler.Close(ctx)
+
+	for _, tenant := range []string{"t1", "t2", "t1"} {
+		var name string
+		row, err := handler.QueryRow(infrastructure.WithTenant(ctx, tenant), "SELECT name FROM tenant_info")
+		require.NoError(t, err)
+		require.NoError(t, row.Scan(&name))
+		assert.Equal(t, "tenant_"+tenant, name)
+	}
+
+	// search_path is reset after release
+	var searchPath string
+	row, err := handler.QueryRow(ctx, "SHOW search_path")
+	require.NoError(t, err)
+	require.NoError(t, row.Scan(&searchPath))
+	assert.NotContains(t, searchPath, "tenant_")
+}
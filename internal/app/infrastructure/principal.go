@@ -0,0 +1,49 @@
+package infrastructure
+
+import "context"
+
+// Principal - authenticated caller
+type Principal struct {
+	// Subject - caller identity (sub claim)
+	Subject string
+
+	// Roles - roles of the caller
+	Roles []string
+
+	// Scopes - scopes granted to the caller
+	Scopes []string
+
+	// Claims - all claims of the token
+	Claims map[string]interface{}
+}
+
+// HasRole - returns true if the caller has the role
+func (p *Principal) HasRole(role string) bool {
+	return contains(p.Roles, role)
+}
+
+// HasScope - returns true if the scope is granted to the caller
+func (p *Principal) HasScope(scope string) bool {
+	return contains(p.Scopes, scope)
+}
+
+// WithPrincipal - returns context with authenticated caller. Subject is used as caller identity (see WithUser)
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	ctx = context.WithValue(ctx, CtxKeyPrincipal{}, p)
+	return WithUser(ctx, p.Subject)
+}
+
+// PrincipalFromContext - returns authenticated caller from context. ok is false if request isn't authenticated
+func PrincipalFromContext(ctx context.Context) (p *Principal, ok bool) {
+	p, ok = ctx.Value(CtxKeyPrincipal{}).(*Principal)
+	return p, ok && p != nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
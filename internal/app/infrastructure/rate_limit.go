@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const memoryRateLimitSweepInterval = time.Minute
+
+// RateLimit - token bucket params. Rate tokens are added per second up to Burst, each request takes one token
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitResult - result of token taking
+type RateLimitResult struct {
+	// Allowed - request is allowed
+	Allowed bool
+
+	// Remaining - count of requests allowed immediately after this one
+	Remaining int
+
+	// RetryAfter - delay until the next token is available. It is zero if request is allowed
+	RetryAfter time.Duration
+
+	// Reset - delay until the bucket is full
+	Reset time.Duration
+}
+
+// NewRateLimitResult - returns result for tokens left in the bucket after the request
+func NewRateLimitResult(limit RateLimit, tokens float64, allowed bool) RateLimitResult {
+	res := RateLimitResult{
+		Allowed:   allowed,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     time.Duration((float64(limit.Burst) - tokens) / limit.Rate * float64(time.Second)),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	}
+	return res
+}
+
+// RateLimitStore - storage of token buckets
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+type tokenBucket struct {
+	limit      RateLimit
+	tokens     float64
+	updateTime time.Time
+}
+
+// MemoryRateLimitStore - token buckets of the service instance. Buckets which are full again are removed periodically
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimitStore returns new MemoryRateLimitStore
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	var target MemoryRateLimitStore
+	target.buckets = make(map[string]*tokenBucket)
+	target.now = time.Now
+	target.lastSweep = target.now()
+	return &target
+}
+
+// Take - takes one token from the bucket of the key
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{limit: limit, tokens: float64(limit.Burst), updateTime: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updateTime).Seconds()*limit.Rate)
+	b.updateTime = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return NewRateLimitResult(limit, b.tokens, allowed), nil
+}
+
+// sweep - removes buckets which would be full by now. Must be called under lock
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryRateLimitSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.updateTime).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimitStore_Take(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+	limit := RateLimit{Rate: 2, Burst: 3}
+
+	// burst is allowed at once
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+	res, err := store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitResult{Allowed: false, RetryAfter: time.Millisecond * 500, Reset: time.Millisecond * 1500}, res)
+
+	// other keys have own buckets
+	res, err = store.Take(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// tokens are added with rate
+	now = now.Add(time.Millisecond * 500)
+	res, err = store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	// full buckets are removed
+	now = now.Add(memoryRateLimitSweepInterval)
+	_, err = store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.Len(t, store.buckets, 1)
+}
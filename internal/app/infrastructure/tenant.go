@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// ErrBadTenant - "bad tenant name" error
+var ErrBadTenant = errors.New("bad tenant name")
+
+// tenantRe - tenant is used as a part of schema name, so only lowercase letters, digits and underscore are allowed
+var tenantRe = regexp.MustCompile(`^[a-z0-9_]{1,48}$`)
+
+// ValidateTenant - checks that tenant name can be used as a part of db schema name
+func ValidateTenant(tenant string) error {
+	if !tenantRe.MatchString(tenant) {
+		return ErrBadTenant
+	}
+	return nil
+}
+
+// WithTenant - returns context with tenant. Tenant must be validated by ValidateTenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, CtxKeyTenant{}, tenant)
+}
+
+// TenantFromContext - returns tenant from context. ok is false if tenant isn't set
+func TenantFromContext(ctx context.Context) (tenant string, ok bool) {
+	tenant, ok = ctx.Value(CtxKeyTenant{}).(string)
+	return tenant, ok && tenant != ""
+}
@@ -0,0 +1,41 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTenant(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  string
+		wantErr bool
+	}{
+		{name: "ValidateTenant. Case #1. Valid tenant", tenant: "tenant_01", wantErr: false},
+		{name: "ValidateTenant. Case #2. Empty tenant", tenant: "", wantErr: true},
+		{name: "ValidateTenant. Case #3. Upper case", tenant: "Tenant", wantErr: true},
+		{name: "ValidateTenant. Case #4. Injection", tenant: "t1; drop table users", wantErr: true},
+		{name: "ValidateTenant. Case #5. Too long", tenant: "t123456789012345678901234567890123456789012345678", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTenant(tt.tenant)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrBadTenant)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTenantFromContext(t *testing.T) {
+	_, ok := TenantFromContext(context.Background())
+	assert.False(t, ok)
+
+	tenant, ok := TenantFromContext(WithTenant(context.Background(), "t1"))
+	assert.True(t, ok)
+	assert.Equal(t, "t1", tenant)
+}
@@ -0,0 +1,17 @@
+package infrastructure
+
+import "context"
+
+// WithUser - returns context with caller identity
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, CtxKeyUser{}, user)
+}
+
+// UserFromContext - returns caller identity from context. If it isn't set, SystemUser is returned
+func UserFromContext(ctx context.Context) string {
+	user, ok := ctx.Value(CtxKeyUser{}).(string)
+	if !ok || user == "" {
+		return SystemUser
+	}
+	return user
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/infrastructure/postgres"
+)
+
+func jobQueueModule() Module {
+	return Module{
+		Name:      "job-queue",
+		DependsOn: []string{"postgres"},
+		Init:      initJobQueue,
+		Start:     startJobQueue,
+	}
+}
+
+func initJobQueue(ctx context.Context, a *App) error {
+	var err error
+	a.JobQueue, err = postgres.NewJobQueue(ctx, a.DB, a.Config.JobQueue)
+	if err != nil {
+		return fmt.Errorf("can't create job queue: %w", err)
+	}
+	a.addResource("job-queue", infrastructure.PhaseInput, a.JobQueue)
+	return nil
+}
+
+// prepareJobHandlers - registers handlers of background jobs. Jobs are added by dbHandler.Enqueue
+func prepareJobHandlers(_ context.Context, _ *App) error {
+	// Add new handler, e.g.
+	// postgres.AddJobHandler(ctx, a.JobQueue, "send_email", emailService.Send)
+	return nil
+}
+
+func startJobQueue(ctx context.Context, a *App) error {
+	if err := prepareJobHandlers(ctx, a); err != nil {
+		return err
+	}
+	if !a.Config.JobQueue.Enabled {
+		a.LogInfo(ctx, "job queue is disabled")
+		return nil
+	}
+
+	go func() {
+		err := a.JobQueue.Start(ctx)
+		if err != nil {
+			a.LogError(ctx, "can't start job queue", err)
+		}
+	}()
+	return nil
+}
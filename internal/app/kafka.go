@@ -2,65 +2,68 @@ package app
 
 import (
 	"context"
-	"time"
+	"fmt"
 
 	kafka2 "go-service-template/internal/app/handler/kafka"
+	"go-service-template/internal/app/infrastructure"
 	"go-service-template/internal/app/infrastructure/kafka"
 )
 
-func initProducer(ctx context.Context) {
-	var err error
+func producerModule() Module {
+	return Module{Name: "kafka-producer", DependsOn: []string{"postgres"}, Init: initProducer}
+}
 
-	for ind := 0; ind < attemptsCount; ind++ {
-		select {
-		case <-ctx.Done():
-			logger.Info().Msg("kafka producer initialization aborted due to canceled context")
-			return
-		default:
-			logger.Info().Msgf("try to initialize kafka producer: attempt #%d", ind+1)
-			producer, err = kafka.NewMessageProducer(ctx, appConfig.Kafka, dbHandler)
-			if err != nil {
-				logger.Error().Err(err).Msg("can't create kafka message producer")
-			} else {
-				resources = append(resources, producer)
-				return
-			}
-			time.Sleep(attemptInterval)
-		}
+// initProducer - creates kafka producer. If brokers are unavailable, producer reconnects in background
+func initProducer(ctx context.Context, a *App) error {
+	var err error
+	a.Producer, err = kafka.NewMessageProducer(ctx, a.Config.Kafka, a.DB)
+	if err != nil {
+		return fmt.Errorf("can't create kafka message producer: %w", err)
 	}
-	if producer == nil {
-		logger.Fatal().Err(err).Msgf("can't create kafka producer after %d attempts", attemptsCount)
+	a.addResource("kafka-producer", infrastructure.PhaseOutput, a.Producer)
+	return nil
+}
+
+func consumerModule() Module {
+	return Module{
+		Name:      "kafka-consumer",
+		DependsOn: []string{"postgres"},
+		Init:      initConsumer,
+		Start:     startConsumer,
 	}
 }
 
-func initConsumer(ctx context.Context) {
+// initConsumer - creates kafka consumer. If brokers are unavailable, consumer reconnects in background after Start
+func initConsumer(ctx context.Context, a *App) error {
 	var err error
-	for ind := 0; ind < attemptsCount; ind++ {
-		select {
-		case <-ctx.Done():
-			logger.Info().Msg("kafka consumer initialization aborted due to canceled context")
-			return
-		default:
-			logger.Info().Msgf("try to initialize kafka consumer: attempt #%d", ind+1)
-			consumer, err = kafka.NewConsumer(ctx, appConfig.Passport.ServiceName, appConfig.Kafka, dbHandler)
-			if err != nil {
-				logger.Error().Err(err).Msg("can't create kafka consumer")
-			} else {
-				resources = append(resources, consumer)
-				return
-			}
-			time.Sleep(attemptInterval)
-		}
-	}
-	if consumer == nil {
-		logger.Fatal().Err(err).Msgf("can't create kafka producer after %d attempts", attemptsCount)
+	a.Consumer, err = kafka.NewConsumer(ctx, a.Config.Passport.ServiceName, a.Config.Kafka, a.DB)
+	if err != nil {
+		return fmt.Errorf("can't create kafka consumer: %w", err)
 	}
+	a.addResource("kafka-consumer", infrastructure.PhaseInput, a.Consumer)
+	return nil
 }
 
-func prepareConsumerHandlers(ctx context.Context) {
+func prepareConsumerHandlers(ctx context.Context, a *App) error {
 	// Add new handler
-	err := consumer.AddHandler(ctx, "territory.all.health-check", kafka2.DefaultMessageHandler)
+	err := a.Consumer.AddHandler(ctx, "territory.all.health-check", kafka2.DefaultMessageHandler)
 	if err != nil {
-		logger.Error().Msg("can't add DefaultMessageHandler to kafka consumer")
+		return fmt.Errorf("can't add DefaultMessageHandler to kafka consumer: %w", err)
 	}
+	return nil
+}
+
+func startConsumer(ctx context.Context, a *App) error {
+	// Add handler for incoming messages processing
+	if err := prepareConsumerHandlers(ctx, a); err != nil {
+		return err
+	}
+
+	go func() {
+		err := a.Consumer.Start(ctx)
+		if err != nil {
+			a.LogError(ctx, "can't start consumer service", err)
+		}
+	}()
+	return nil
 }
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	pgHandler "go-service-template/internal/app/handler/postgres"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/infrastructure/postgres"
+)
+
+func listenerModule() Module {
+	return Module{
+		Name:      "postgres-listener",
+		DependsOn: []string{"postgres"},
+		Init:      initListener,
+		Start:     startListener,
+	}
+}
+
+func initListener(ctx context.Context, a *App) error {
+	var err error
+	a.Listener, err = postgres.NewListener(ctx, a.DB, a.Config.PgListener)
+	if err != nil {
+		return fmt.Errorf("can't create postgres listener: %w", err)
+	}
+	a.addResource("postgres-listener", infrastructure.PhaseInput, a.Listener)
+	return nil
+}
+
+func prepareListenerHandlers(ctx context.Context, a *App) {
+	for _, channel := range a.Config.PgListener.Channels {
+		err := a.Listener.AddHandler(ctx, channel, pgHandler.DefaultNotificationHandler)
+		if err != nil {
+			a.Log(ctx).Error().Err(err).Str("channel", channel).Msg("can't add DefaultNotificationHandler to postgres listener")
+		}
+	}
+}
+
+func startListener(ctx context.Context, a *App) error {
+	// Add handlers for incoming notifications processing
+	prepareListenerHandlers(ctx, a)
+	if len(a.Config.PgListener.Channels) == 0 {
+		a.LogInfo(ctx, "no channels for postgres listener. listener isn't started")
+		return nil
+	}
+
+	go func() {
+		err := a.Listener.Start(ctx)
+		if err != nil {
+			a.LogError(ctx, "can't start postgres listener", err)
+		}
+	}()
+	return nil
+}
+
+func partitionMaintainerModule() Module {
+	return Module{
+		Name:      "partition-maintainer",
+		DependsOn: []string{"postgres"},
+		Init:      initPartitionMaintainer,
+		Start:     startPartitionMaintainer,
+	}
+}
+
+func initPartitionMaintainer(ctx context.Context, a *App) error {
+	var err error
+	a.PartitionMaintainer, err = postgres.NewPartitionMaintainer(ctx, a.DB, a.Config.PartitionMaintenance)
+	if err != nil {
+		return fmt.Errorf("can't create partition maintainer: %w", err)
+	}
+	a.addResource("partition-maintainer", infrastructure.PhaseBackground, a.PartitionMaintainer)
+	return nil
+}
+
+func startPartitionMaintainer(ctx context.Context, a *App) error {
+	if !a.Config.PartitionMaintenance.Enabled {
+		a.LogInfo(ctx, "partition maintenance is disabled")
+		return nil
+	}
+
+	go func() {
+		err := a.PartitionMaintainer.Start(ctx)
+		if err != nil {
+			a.LogError(ctx, "can't start partition maintainer", err)
+		}
+	}()
+	return nil
+}
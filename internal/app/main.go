@@ -5,7 +5,6 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	_ "go-service-template/docs/swagger"
 	"go-service-template/internal/app/infrastructure"
@@ -21,25 +20,38 @@ import (
 func Main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	// resources are stopped by App.Stop in order, so they run with their own context
+	appCtx, cancelApp := context.WithCancel(context.Background())
+	defer cancelApp()
 
 	// Waiting for a shutdown signal from os. When it received cancel the context
 	go func() {
 		quit := make(chan os.Signal, 1)
 		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 		s := <-quit
-		logger = infrastructure.GetBaseLogger(ctx)
-		logger.Warn().Msgf("Signal '%s' was caught. Exiting", s)
+		infrastructure.GetBaseLogger(ctx).Warn().Msgf("Signal '%s' was caught. Exiting", s)
 		cancel()
 	}()
 
-	PrepareApp(ctx)
-
-	StartApp(ctx)
+	config := loadConfig()
+	logger := infrastructure.GetBaseLogger(ctx)
+	a := NewApp(config)
+	a.Register(DefaultModules()...)
+	if err := a.Build(appCtx); err != nil {
+		logger.Fatal().Err(err).Msg("can't build app")
+	}
+	if err := a.Start(appCtx); err != nil {
+		logger.Fatal().Err(err).Msg("can't start app")
+	}
 
 	<-ctx.Done()
-	shutdownCtx, cancelTerminator := context.WithTimeout(context.Background(), 60*time.Second)
+	shutdownCtx, cancelTerminator := context.WithTimeout(context.Background(), config.Shutdown.Timeout)
 	defer func() {
 		cancelTerminator()
 	}()
-	ShutdownApp(shutdownCtx)
+	if err := a.Stop(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("shutdown completed with errors")
+		return
+	}
+	logger.Info().Msg("shutdown completed")
 }
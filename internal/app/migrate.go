@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	cfg "go-service-template/internal/app/config"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/infrastructure/postgres"
+	"go-service-template/scripts/database"
+)
+
+const migrateUsage = "usage: migrate up [N] | down [N] | goto V | force V | version"
+
+// migrationDataSource - returns dsn for migrations. Migrations are applied on behalf of the schema owner
+func migrationDataSource(config *cfg.AppConfig) string {
+	username, password := config.Migration.Username, config.Migration.Password
+	if username == "" {
+		username, password = config.Database.Username, config.Database.Password
+	}
+	return fmt.Sprintf("postgresql://%s:%s@%s:%d/%s", username, password, config.Database.Address, config.Database.Port, config.Database.DB) //nolint:nosprintfhostport
+}
+
+func newMigrator(ctx context.Context, config *cfg.AppConfig) (*postgres.Migrator, error) {
+	return postgres.NewMigrator(ctx, migrationDataSource(config), database.Migrations, database.MigrationsPath, config.Migration.LockTimeout)
+}
+
+// migrateOnStart - applies embedded migrations before repositories are built.
+// If the database is unavailable, start is blocked until it is available and migrated,
+// so repositories, consumers and http server never work with the old schema
+func migrateOnStart(ctx context.Context, a *App) error {
+	if !a.DB.Ready() {
+		a.LogWarn(ctx, "database is unavailable. waiting for it to apply migrations")
+		if err := a.DB.WaitReady(ctx); err != nil {
+			return fmt.Errorf("database isn't available for migrations: %w", err)
+		}
+	}
+	return migrate(ctx, a.Config)
+}
+
+// migrate - applies embedded migrations
+func migrate(ctx context.Context, config *cfg.AppConfig) error {
+	migrator, err := newMigrator(ctx, config)
+	if err != nil {
+		return fmt.Errorf("can't create migrator: %w", err)
+	}
+	defer func() {
+		_ = migrator.Close(ctx)
+	}()
+	if err = migrator.Up(ctx); err != nil {
+		return fmt.Errorf("can't apply migrations: %w", err)
+	}
+	return nil
+}
+
+// MigrateMain - entry point for migrate subcommand
+func MigrateMain(args []string) {
+	ctx := context.Background()
+	config := loadConfig()
+	logger := infrastructure.GetBaseLogger(ctx)
+
+	if len(args) == 0 {
+		logger.Fatal().Msg(migrateUsage)
+	}
+	migrator, err := newMigrator(ctx, config)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("can't create migrator")
+	}
+	defer func() {
+		_ = migrator.Close(ctx)
+	}()
+
+	switch args[0] {
+	case "up":
+		if n, ok := migrateArg(args); ok {
+			err = migrator.Steps(ctx, n)
+		} else {
+			err = migrator.Up(ctx)
+		}
+	case "down":
+		if n, ok := migrateArg(args); ok {
+			err = migrator.Steps(ctx, -n)
+		} else {
+			err = migrator.Down(ctx)
+		}
+	case "goto":
+		n, ok := migrateArg(args)
+		if !ok || n < 0 {
+			logger.Fatal().Msg(migrateUsage)
+		}
+		err = migrator.Goto(ctx, uint(n))
+	case "force":
+		n, ok := migrateArg(args)
+		if !ok {
+			logger.Fatal().Msg(migrateUsage)
+		}
+		err = migrator.Force(ctx, n)
+	case "version":
+		version, dirty, e := migrator.Version(ctx)
+		switch {
+		case errors.Is(e, postgres.ErrNilMigrationVersion):
+			fmt.Println("no migrations applied")
+		case e == nil:
+			fmt.Printf("version: %d, dirty: %t\n", version, dirty)
+		default:
+			err = e
+		}
+	default:
+		logger.Fatal().Msg(migrateUsage)
+	}
+	if err != nil {
+		logger.Error().Err(err).Msgf("migrate %s failed", args[0])
+		_ = migrator.Close(ctx)
+		logger.Fatal().Msg("migration failed")
+	}
+}
+
+// migrateArg - returns numeric argument of the subcommand
+func migrateArg(args []string) (int, bool) {
+	if len(args) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil {
+		infrastructure.GetBaseLogger(context.Background()).Fatal().Err(err).Msg(migrateUsage)
+	}
+	return n, true
+}
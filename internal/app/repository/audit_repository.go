@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+const getEntityHistory = `SELECT id, change_time, table_name, coalesce(entity_id, ''), operation, old_data, new_data,
+	coalesce(app_user, ''), coalesce(request_id, '')
+	FROM audit_log
+	WHERE table_name = $1 AND entity_id = $2
+	ORDER BY change_time, id`
+
+// AuditRepository - repository for change history written by audit_log triggers
+type AuditRepository struct {
+	infrastructure.SugarLogger
+	h basedbhandler.DBHandler
+}
+
+// NewAuditRepository returns new AuditRepository
+func NewAuditRepository(dbHandler basedbhandler.DBHandler) *AuditRepository {
+	var target AuditRepository
+	target.h = dbHandler
+	return &target
+}
+
+// History - returns changes of the entity in chronological order. table - table name without schema
+func (r *AuditRepository) History(ctx context.Context, table string, entityID string) ([]dto.AuditRecord, error) {
+	rows, err := r.h.Query(ctx, getEntityHistory, table, entityID)
+	if err != nil {
+		r.LogError(ctx, "Can't get entity history", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]dto.AuditRecord, 0)
+	for rows.Next() {
+		var (
+			record           dto.AuditRecord
+			oldData, newData []byte
+		)
+		err = rows.Scan(&record.ID, &record.ChangeTime, &record.Table, &record.EntityID, &record.Operation,
+			&oldData, &newData, &record.User, &record.RequestID)
+		if err != nil {
+			r.LogError(ctx, "Can't read entity history", err)
+			return nil, err
+		}
+		record.OldData = json.RawMessage(oldData)
+		record.NewData = json.RawMessage(newData)
+		res = append(res, record)
+	}
+	if err = rows.Err(); err != nil {
+		r.LogError(ctx, "Can't read entity history", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -42,6 +42,10 @@ type Transactioner interface {
 type Rows interface {
 	Scan(dest ...interface{}) error
 	Next() bool
+	// Err - returns error occurred during reading
+	Err() error
+	// Close - closes rows. Rows must be closed if they aren't read to the end
+	Close()
 }
 
 // Row  - interface for working with singe row
@@ -0,0 +1,53 @@
+package basedbhandler
+
+import "errors"
+
+var (
+	// ErrQueryTimeout - "database query timeout" error. Statement is canceled because of timeout
+	ErrQueryTimeout = errors.New("database query timeout")
+
+	// ErrNotFound - "entity not found" error. Query returned no rows
+	ErrNotFound = errors.New("entity not found")
+
+	// ErrConflict - "entity already exists" error. Unique constraint is violated
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrVersionConflict - "entity was modified concurrently" error. Optimistic lock check failed
+	ErrVersionConflict = errors.New("entity was modified concurrently")
+
+	// ErrConstraintViolation - "constraint violation" error. Foreign key, check or not null constraint is violated
+	ErrConstraintViolation = errors.New("constraint violation")
+
+	// ErrRetryable - "temporary database error" error. Statement can be retried (serialization failure, deadlock, lock timeout, connection loss)
+	ErrRetryable = errors.New("temporary database error")
+)
+
+// DBError - database error translated into one of domain errors above. errors.Is(err, Kind) is true
+type DBError struct {
+	// Kind - domain error
+	Kind error
+
+	// Constraint - name of violated constraint (if any)
+	Constraint string
+
+	// Err - original error
+	Err error
+}
+
+// Error - implementation of error interface
+func (e *DBError) Error() string {
+	if e.Constraint != "" {
+		return e.Kind.Error() + " (" + e.Constraint + "): " + e.Err.Error()
+	}
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+// Unwrap - returns original error
+func (e *DBError) Unwrap() error {
+	return e.Err
+}
+
+// Is - matches Kind
+func (e *DBError) Is(target error) bool {
+	return target == e.Kind //nolint:errorlint,goerr113
+}
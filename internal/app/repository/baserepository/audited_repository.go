@@ -0,0 +1,196 @@
+package baserepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/infrastructure/postgres"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+// ErrBadColumn - "bad table or column name" error
+var ErrBadColumn = errors.New("bad table or column name")
+
+// AuditColumnsList - audit columns in the order of AuditColumns.ScanDest. Can be used in SELECT statements
+const AuditColumnsList = "version, created_at, updated_at, created_by, updated_by"
+
+// identifierRe - table and column names are inserted into statements as is, so only plain identifiers are allowed
+var identifierRe = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}(\.[a-z_][a-z0-9_]{0,62})?$`)
+
+// AuditColumns - columns maintained by AuditedRepository
+type AuditColumns struct {
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedBy string    `json:"createdBy"`
+	UpdatedBy string    `json:"updatedBy"`
+}
+
+// ScanDest - returns destinations for scanning of AuditColumnsList
+func (a *AuditColumns) ScanDest() []interface{} {
+	return []interface{}{&a.Version, &a.CreatedAt, &a.UpdatedAt, &a.CreatedBy, &a.UpdatedBy}
+}
+
+// VersionConflictError - row isn't changed because its version differs from the expected one or row doesn't exist.
+// errors.Is(err, basedbhandler.ErrVersionConflict) is true
+type VersionConflictError struct {
+	Table   string
+	ID      interface{}
+	Version int64
+}
+
+// Error - implementation of error interface
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: %s id=%v version=%d", basedbhandler.ErrVersionConflict.Error(), e.Table, e.ID, e.Version)
+}
+
+// Is - matches basedbhandler.ErrVersionConflict
+func (e *VersionConflictError) Is(target error) bool {
+	return target == basedbhandler.ErrVersionConflict //nolint:errorlint,goerr113
+}
+
+// AuditedRepository - base helper for repositories of entities with audit columns (see AuditColumnsList).
+// Insert and Update maintain audit columns: version is incremented on each update, *_at are set by the db,
+// *_by are filled from the caller identity in the context (infrastructure.WithUser).
+// Update and Delete check version (optimistic locking) and return VersionConflictError if row isn't changed.
+// Statements are executed on the primary database, replicas are read only
+type AuditedRepository struct {
+	infrastructure.SugarLogger
+	h        basedbhandler.DBHandler
+	table    string
+	idColumn string
+}
+
+// NewAuditedRepository returns new AuditedRepository
+func NewAuditedRepository(h basedbhandler.DBHandler, table string, idColumn string) (*AuditedRepository, error) {
+	var target AuditedRepository
+	target.h = h
+	target.table = table
+	target.idColumn = idColumn
+	if err := target.Init(context.Background()); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Init - func for initialisation
+func (r *AuditedRepository) Init(ctx context.Context) error {
+	if err := validateIdentifiers(r.table, r.idColumn); err != nil {
+		r.LogError(ctx, "can't create audited repository for "+r.table, err)
+		return err
+	}
+	return nil
+}
+
+// Insert - inserts row with columns and values. Audit columns are set automatically and returned
+func (r *AuditedRepository) Insert(ctx context.Context, columns []string, values ...interface{}) (AuditColumns, error) {
+	var audit AuditColumns
+	if err := r.validateColumns(columns, values); err != nil {
+		r.LogError(ctx, "can't insert into "+r.table, err)
+		return audit, err
+	}
+	user := infrastructure.UserFromContext(ctx)
+	placeholders := make([]string, 0, len(columns))
+	for ind := range columns {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", ind+1))
+	}
+	userArg := fmt.Sprintf("$%d", len(values)+1)
+	statement := fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (%s, 1, now(), now(), %s, %s) RETURNING %s",
+		r.table, strings.Join(columns, ", "), AuditColumnsList, strings.Join(placeholders, ", "), userArg, userArg, AuditColumnsList)
+
+	row, err := r.h.QueryRow(postgres.WithPrimary(ctx), statement, args(values, user)...)
+	if err == nil {
+		err = row.Scan(audit.ScanDest()...)
+	}
+	if err != nil {
+		r.LogError(ctx, "can't insert into "+r.table, err)
+		return audit, err
+	}
+	return audit, nil
+}
+
+// Update - updates columns of the row with id if its version equals to version. Version is incremented,
+// updated_at and updated_by are set. If row isn't updated, VersionConflictError is returned
+func (r *AuditedRepository) Update(ctx context.Context, id interface{}, version int64, columns []string, values ...interface{}) (AuditColumns, error) {
+	var audit AuditColumns
+	if err := r.validateColumns(columns, values); err != nil {
+		r.LogError(ctx, "can't update "+r.table, err)
+		return audit, err
+	}
+	user := infrastructure.UserFromContext(ctx)
+	set := make([]string, 0, len(columns))
+	for ind, column := range columns {
+		set = append(set, fmt.Sprintf("%s = $%d", column, ind+1))
+	}
+	n := len(values)
+	statement := fmt.Sprintf("UPDATE %s SET %s, version = version + 1, updated_at = now(), updated_by = $%d WHERE %s = $%d AND version = $%d RETURNING %s",
+		r.table, strings.Join(set, ", "), n+1, r.idColumn, n+2, n+3, AuditColumnsList)
+
+	row, err := r.h.QueryRow(postgres.WithPrimary(ctx), statement, args(values, user, id, version)...)
+	if err == nil {
+		err = row.Scan(audit.ScanDest()...)
+	}
+	if err != nil {
+		return audit, r.conflictError(ctx, "can't update "+r.table, id, version, err)
+	}
+	return audit, nil
+}
+
+// Delete - deletes the row with id if its version equals to version. If row isn't deleted, VersionConflictError is returned
+func (r *AuditedRepository) Delete(ctx context.Context, id interface{}, version int64) error {
+	statement := fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND version = $2 RETURNING version", r.table, r.idColumn)
+	row, err := r.h.QueryRow(postgres.WithPrimary(ctx), statement, id, version)
+	if err == nil {
+		var deleted int64
+		err = row.Scan(&deleted)
+	}
+	if err != nil {
+		return r.conflictError(ctx, "can't delete from "+r.table, id, version, err)
+	}
+	return nil
+}
+
+// conflictError - converts "no rows" error into VersionConflictError
+func (r *AuditedRepository) conflictError(ctx context.Context, msg string, id interface{}, version int64, err error) error {
+	if errors.Is(err, basedbhandler.ErrNotFound) {
+		err = &VersionConflictError{Table: r.table, ID: id, Version: version}
+		r.Log(ctx).Warn().Err(err).Msg(msg)
+		return err
+	}
+	r.LogError(ctx, msg, err)
+	return err
+}
+
+// validateColumns - checks columns names. Audit columns can't be set explicitly
+func (r *AuditedRepository) validateColumns(columns []string, values []interface{}) error {
+	if len(columns) == 0 || len(columns) != len(values) {
+		return ErrBadColumn
+	}
+	for _, column := range columns {
+		if strings.Contains(", "+AuditColumnsList+", ", ", "+column+", ") {
+			return ErrBadColumn
+		}
+	}
+	return validateIdentifiers(columns...)
+}
+
+// args - returns new slice with values and extra args. values aren't modified
+func args(values []interface{}, extra ...interface{}) []interface{} {
+	res := make([]interface{}, 0, len(values)+len(extra))
+	res = append(res, values...)
+	return append(res, extra...)
+}
+
+func validateIdentifiers(names ...string) error {
+	for _, name := range names {
+		if !identifierRe.MatchString(name) {
+			return ErrBadColumn
+		}
+	}
+	return nil
+}
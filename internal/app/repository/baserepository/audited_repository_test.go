@@ -0,0 +1,130 @@
+package baserepository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+func TestMain(m *testing.M) {
+	infrastructure.InitGlobalLogger("default", "go-service-template", "")
+	os.Exit(m.Run())
+}
+
+type fakeRow struct {
+	err error
+}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	if v, ok := dest[0].(*int64); ok {
+		*v = 2
+	}
+	return nil
+}
+
+// fakeDB - records the last statement
+type fakeDB struct {
+	basedbhandler.DBHandler
+	statement string
+	args      []interface{}
+	primary   bool
+	err       error
+}
+
+func (db *fakeDB) QueryRow(ctx context.Context, statement string, args ...interface{}) (basedbhandler.Row, error) {
+	db.statement = statement
+	db.args = args
+	db.primary, _ = ctx.Value(infrastructure.CtxKeyPrimary{}).(bool)
+	return fakeRow{err: db.err}, nil
+}
+
+func TestNewAuditedRepository(t *testing.T) {
+	_, err := NewAuditedRepository(&fakeDB{}, "orders; drop table users", "id")
+	assert.ErrorIs(t, err, ErrBadColumn)
+
+	_, err = NewAuditedRepository(&fakeDB{}, "test.orders", "id")
+	assert.NoError(t, err)
+}
+
+func TestAuditedRepository_Insert(t *testing.T) {
+	db := &fakeDB{}
+	r, err := NewAuditedRepository(db, "orders", "id")
+	require.NoError(t, err)
+
+	ctx := infrastructure.WithUser(context.Background(), "alice")
+	audit, err := r.Insert(ctx, []string{"id", "amount"}, 1, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), audit.Version)
+	assert.Equal(t, "INSERT INTO orders (id, amount, version, created_at, updated_at, created_by, updated_by) "+
+		"VALUES ($1, $2, 1, now(), now(), $3, $3) RETURNING version, created_at, updated_at, created_by, updated_by", db.statement)
+	assert.Equal(t, []interface{}{1, 100, "alice"}, db.args)
+	assert.True(t, db.primary, "statement with RETURNING is executed on the primary")
+
+	_, err = r.Insert(ctx, []string{"id", "version"}, 1, 100)
+	assert.ErrorIs(t, err, ErrBadColumn)
+	_, err = r.Insert(ctx, []string{"id"}, 1, 100)
+	assert.ErrorIs(t, err, ErrBadColumn)
+}
+
+func TestAuditedRepository_Update(t *testing.T) {
+	errSome := errors.New("some error")
+	tests := []struct {
+		name    string
+		dbErr   error
+		wantErr error
+	}{
+		{
+			name: "AuditedRepository. Update. Case #1. Success",
+		},
+		{
+			name:    "AuditedRepository. Update. Case #2. Version conflict",
+			dbErr:   &basedbhandler.DBError{Kind: basedbhandler.ErrNotFound, Err: errSome},
+			wantErr: basedbhandler.ErrVersionConflict,
+		},
+		{
+			name:    "AuditedRepository. Update. Case #3. Other error",
+			dbErr:   errSome,
+			wantErr: errSome,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{err: tt.dbErr}
+			r, err := NewAuditedRepository(db, "orders", "id")
+			require.NoError(t, err)
+
+			_, err = r.Update(context.Background(), 1, 5, []string{"amount"}, 200)
+			assert.Equal(t, "UPDATE orders SET amount = $1, version = version + 1, updated_at = now(), updated_by = $2 "+
+				"WHERE id = $3 AND version = $4 RETURNING version, created_at, updated_at, created_by, updated_by", db.statement)
+			assert.Equal(t, []interface{}{200, infrastructure.SystemUser, 1, int64(5)}, db.args)
+			assert.True(t, db.primary)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAuditedRepository_Delete(t *testing.T) {
+	db := &fakeDB{err: &basedbhandler.DBError{Kind: basedbhandler.ErrNotFound, Err: errors.New("no rows")}}
+	r, err := NewAuditedRepository(db, "orders", "id")
+	require.NoError(t, err)
+
+	err = r.Delete(context.Background(), 1, 5)
+	var conflict *VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, int64(5), conflict.Version)
+	assert.Equal(t, "DELETE FROM orders WHERE id = $1 AND version = $2 RETURNING version", db.statement)
+	assert.True(t, db.primary)
+}
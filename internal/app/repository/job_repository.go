@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/repository/basedbhandler"
+)
+
+const (
+	jobStatusDead = "dead"
+
+	listJobs = `SELECT id, queue, kind, payload, coalesce(unique_key, ''), status, attempt, max_attempts, run_at,
+	coalesce(last_error, ''), create_time
+	FROM jobs
+	WHERE ($1 = '' OR queue = $1) AND ($2 = '' OR kind = $2) AND ($3 = '' OR status = $3)
+	ORDER BY run_at, id
+	LIMIT $4`
+	listDeadJobs = `SELECT id, queue, kind, payload, coalesce(unique_key, ''), attempt, max_attempts,
+	coalesce(last_error, ''), create_time, failed_time
+	FROM jobs_dead
+	WHERE ($1 = '' OR queue = $1) AND ($2 = '' OR kind = $2)
+	ORDER BY failed_time DESC, id
+	LIMIT $3`
+	retryJob = `UPDATE jobs SET run_at = now()
+	WHERE id = $1 AND status = 'pending'
+	RETURNING id`
+	retryDeadJob = `WITH d AS (DELETE FROM jobs_dead WHERE id = $1 RETURNING *)
+	INSERT INTO jobs (id, queue, kind, payload, unique_key, max_attempts, last_error, create_time, tenant)
+	SELECT id, queue, kind, payload, unique_key, max_attempts, last_error, create_time, tenant FROM d
+	RETURNING id`
+)
+
+// JobRepository - repository for administration of background jobs (see postgres.JobQueue)
+type JobRepository struct {
+	infrastructure.SugarLogger
+	h basedbhandler.DBHandler
+}
+
+// NewJobRepository returns new JobRepository
+func NewJobRepository(dbHandler basedbhandler.DBHandler) *JobRepository {
+	var target JobRepository
+	target.h = dbHandler
+	return &target
+}
+
+// List - returns jobs waiting in the queue or running
+func (r *JobRepository) List(ctx context.Context, filter dto.JobFilter) ([]dto.Job, error) {
+	rows, err := r.h.Query(ctx, listJobs, filter.Queue, filter.Kind, filter.Status, filter.Limit)
+	if err != nil {
+		r.LogError(ctx, "Can't get jobs", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]dto.Job, 0)
+	for rows.Next() {
+		var (
+			job     dto.Job
+			payload []byte
+			runAt   time.Time
+		)
+		err = rows.Scan(&job.ID, &job.Queue, &job.Kind, &payload, &job.UniqueKey, &job.Status, &job.Attempt,
+			&job.MaxAttempts, &runAt, &job.LastError, &job.CreateTime)
+		if err != nil {
+			r.LogError(ctx, "Can't read jobs", err)
+			return nil, err
+		}
+		job.Payload = json.RawMessage(payload)
+		job.RunAt = &runAt
+		res = append(res, job)
+	}
+	if err = rows.Err(); err != nil {
+		r.LogError(ctx, "Can't read jobs", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListDead - returns jobs failed after all attempts. The most recent failures go first
+func (r *JobRepository) ListDead(ctx context.Context, filter dto.JobFilter) ([]dto.Job, error) {
+	rows, err := r.h.Query(ctx, listDeadJobs, filter.Queue, filter.Kind, filter.Limit)
+	if err != nil {
+		r.LogError(ctx, "Can't get dead jobs", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]dto.Job, 0)
+	for rows.Next() {
+		var (
+			job        dto.Job
+			payload    []byte
+			failedTime time.Time
+		)
+		err = rows.Scan(&job.ID, &job.Queue, &job.Kind, &payload, &job.UniqueKey, &job.Attempt, &job.MaxAttempts,
+			&job.LastError, &job.CreateTime, &failedTime)
+		if err != nil {
+			r.LogError(ctx, "Can't read dead jobs", err)
+			return nil, err
+		}
+		job.Payload = json.RawMessage(payload)
+		job.Status = jobStatusDead
+		job.FailedTime = &failedTime
+		res = append(res, job)
+	}
+	if err = rows.Err(); err != nil {
+		r.LogError(ctx, "Can't read dead jobs", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// Retry - runs pending job immediately. basedbhandler.ErrNotFound is returned if there is no pending job with the id
+func (r *JobRepository) Retry(ctx context.Context, id int64) error {
+	return r.returning(ctx, retryJob, id)
+}
+
+// RetryDead - returns dead job into the queue with a fresh count of attempts.
+// basedbhandler.ErrNotFound is returned if there is no dead job with the id
+func (r *JobRepository) RetryDead(ctx context.Context, id int64) error {
+	return r.returning(ctx, retryDeadJob, id)
+}
+
+func (r *JobRepository) returning(ctx context.Context, statement string, id int64) error {
+	row, err := r.h.QueryRow(ctx, statement, id)
+	if err != nil {
+		return err
+	}
+	if err = row.Scan(&id); err != nil {
+		r.LogError(ctx, "Can't retry job", err)
+		return err
+	}
+	return nil
+}
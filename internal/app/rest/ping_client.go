@@ -61,3 +61,9 @@ func (c *PingClient) Ping(ctx context.Context) (*dto.Ping, error) {
 
 	return &res, nil
 }
+
+// HealthCheck проверка доступности сервиса
+func (c *PingClient) HealthCheck(ctx context.Context) error {
+	_, err := c.Ping(ctx)
+	return err
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime/pprof"
+
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+	"gopkg.in/yaml.v3"
+)
+
+// ConsumerController - interface of message consumer controlled by admin API
+//
+//go:generate mockgen -destination=mocks/mock_consumer_controller.go -package=mocks . ConsumerController
+type ConsumerController interface {
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
+	Ready() bool
+	Paused() bool
+	Topics() []string
+}
+
+// RouteProvider - returns registered http routes
+type RouteProvider func() []dto.Route
+
+// AdminService - service for runtime administration: log levels, config inspection, consumer control
+type AdminService struct {
+	infrastructure.SugarLogger
+	config   interface{}
+	consumer ConsumerController
+	routes   RouteProvider
+}
+
+// NewAdminService returns new AdminService. config must be masked (without secrets), it's returned as is
+func NewAdminService(config interface{}, consumer ConsumerController, routes RouteProvider) *AdminService {
+	var target AdminService
+	target.config = config
+	target.consumer = consumer
+	target.routes = routes
+	return &target
+}
+
+// LogLevels - returns global log level and overridden levels of components
+func (s *AdminService) LogLevels(_ context.Context) dto.LogLevels {
+	level, components := infrastructure.LogLevels()
+	return dto.LogLevels{Level: level, Components: components, Supported: infrastructure.LogLevelNames()}
+}
+
+// SetLogLevel - changes global log level. If component isn't empty, log level of the component is changed
+func (s *AdminService) SetLogLevel(ctx context.Context, component, level string) error {
+	var err error
+	if component == "" {
+		err = infrastructure.SetLogLevel(level)
+	} else {
+		err = infrastructure.SetComponentLogLevel(component, level)
+	}
+	if errors.Is(err, infrastructure.ErrBadLogLevel) {
+		return fmt.Errorf("%w: %s", dto.ErrValidation, err)
+	}
+	if err != nil {
+		return err
+	}
+	s.Log(ctx).Warn().Str(infrastructure.ComponentField, component).Str("level", level).Msg("log level changed")
+	return nil
+}
+
+// ResetLogLevel - removes override of the component log level
+func (s *AdminService) ResetLogLevel(ctx context.Context, component string) {
+	infrastructure.ResetComponentLogLevel(component)
+	s.Log(ctx).Warn().Str(infrastructure.ComponentField, component).Msg("log level reset")
+}
+
+// Config - returns effective config in yaml
+func (s *AdminService) Config(_ context.Context) ([]byte, error) {
+	return yaml.Marshal(s.config)
+}
+
+// ConsumerState - returns state of the consumer and topics with registered handlers
+func (s *AdminService) ConsumerState(_ context.Context) dto.ConsumerState {
+	if s.consumer == nil {
+		return dto.ConsumerState{Topics: []string{}}
+	}
+	return dto.ConsumerState{Ready: s.consumer.Ready(), Paused: s.consumer.Paused(), Topics: s.consumer.Topics()}
+}
+
+// PauseConsumer - pauses consumption
+func (s *AdminService) PauseConsumer(ctx context.Context) error {
+	if s.consumer == nil {
+		return fmt.Errorf("%w: consumer isn't configured", dto.ErrUnavailable)
+	}
+	if err := s.consumer.Pause(ctx); err != nil {
+		return fmt.Errorf("%w: %s", dto.ErrUnavailable, err)
+	}
+	s.LogWarn(ctx, "consumer paused by admin")
+	return nil
+}
+
+// ResumeConsumer - resumes consumption
+func (s *AdminService) ResumeConsumer(ctx context.Context) error {
+	if s.consumer == nil {
+		return fmt.Errorf("%w: consumer isn't configured", dto.ErrUnavailable)
+	}
+	if err := s.consumer.Resume(ctx); err != nil {
+		return fmt.Errorf("%w: %s", dto.ErrUnavailable, err)
+	}
+	s.LogWarn(ctx, "consumer resumed by admin")
+	return nil
+}
+
+// Routes - returns registered http routes
+func (s *AdminService) Routes(_ context.Context) []dto.Route {
+	if s.routes == nil {
+		return []dto.Route{}
+	}
+	return s.routes()
+}
+
+// Goroutines - returns stacks of all goroutines
+func (s *AdminService) Goroutines(_ context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
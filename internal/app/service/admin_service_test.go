@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+	"go-service-template/internal/app/service/mocks"
+)
+
+func TestAdminService_SetLogLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		component string
+		level     string
+		wantErr   error
+	}{
+		{name: "AdminService.SetLogLevel Case#1 Global level", level: "info"},
+		{name: "AdminService.SetLogLevel Case#2 Component level", component: infrastructure.ComponentHTTP, level: "debug"},
+		{name: "AdminService.SetLogLevel Case#3 Bad level", level: "loud", wantErr: dto.ErrValidation},
+		{name: "AdminService.SetLogLevel Case#4 Unknown component", component: "redis", level: "debug", wantErr: dto.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := NewAdminService(nil, nil, nil)
+			defer target.ResetLogLevel(context.Background(), tt.component)
+
+			err := target.SetLogLevel(context.Background(), tt.component, tt.level)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+			levels := target.LogLevels(context.Background())
+			if tt.component == "" {
+				assert.Equal(t, tt.level, levels.Level)
+			} else {
+				assert.Equal(t, tt.level, levels.Components[tt.component])
+			}
+		})
+	}
+}
+
+func TestAdminService_PauseConsumer(t *testing.T) {
+	errNotRunning := errors.New("consumer isn't running")
+	tests := []struct {
+		name     string
+		consumer bool
+		prepare  func(m *mocks.MockConsumerController)
+		wantErr  error
+	}{
+		{
+			name:     "AdminService.PauseConsumer Case#1 Positive",
+			consumer: true,
+			prepare: func(m *mocks.MockConsumerController) {
+				m.EXPECT().Pause(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "AdminService.PauseConsumer Case#2 Consumer isn't running",
+			consumer: true,
+			prepare: func(m *mocks.MockConsumerController) {
+				m.EXPECT().Pause(gomock.Any()).Return(errNotRunning)
+			},
+			wantErr: dto.ErrUnavailable,
+		},
+		{
+			name:    "AdminService.PauseConsumer Case#3 Consumer isn't configured",
+			prepare: func(m *mocks.MockConsumerController) {},
+			wantErr: dto.ErrUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			consumer := mocks.NewMockConsumerController(mockCtrl)
+			tt.prepare(consumer)
+			target := NewAdminService(nil, nil, nil)
+			if tt.consumer {
+				target = NewAdminService(nil, consumer, nil)
+			}
+
+			err := target.PauseConsumer(context.Background())
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAdminService_Config(t *testing.T) {
+	config := struct {
+		Database struct {
+			Password string `yaml:"password"`
+		} `yaml:"database"`
+	}{}
+	config.Database.Password = "******"
+	target := NewAdminService(config, nil, nil)
+
+	res, err := target.Config(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "database:\n    password: '******'\n", string(res))
+}
+
+func TestAdminService_Goroutines(t *testing.T) {
+	target := NewAdminService(nil, nil, nil)
+
+	res, err := target.Goroutines(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(res), "TestAdminService_Goroutines"))
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	// DependencyPostgres - type of postgres dependencies
+	DependencyPostgres = "postgres"
+
+	// DependencyKafkaBroker - type of kafka broker dependencies
+	DependencyKafkaBroker = "kafka-broker"
+
+	// DependencyHTTP - type of downstream http services
+	DependencyHTTP = "http"
+
+	defaultDependencyCheckInterval = time.Second * 30
+	defaultDependencyCheckTimeout  = time.Second * 5
+	defaultDependencyHistorySize   = 100
+)
+
+// ErrDependencyMonitorStarted - "dependency monitor is already started" error
+var ErrDependencyMonitorStarted = errors.New("dependency monitor is already started")
+
+// DependencyMonitorConfig - struct for dependency monitor params
+type DependencyMonitorConfig struct {
+	// Enabled - check dependencies periodically in background
+	Enabled bool `env:"DEPENDENCY_MONITOR_ENABLED" yaml:"enabled"`
+
+	// CheckInterval - the duration between checks
+	CheckInterval time.Duration `yaml:"checkInterval"`
+
+	// CheckTimeout - timeout of one check
+	CheckTimeout time.Duration `yaml:"checkTimeout"`
+
+	// HistorySize - count of recent results kept for each dependency
+	HistorySize int `yaml:"historySize"`
+}
+
+type dependency struct {
+	name    string
+	kind    string
+	checker HealthChecker
+
+	mu      sync.Mutex
+	status  dto.DependencyStatus
+	history *checkHistory
+}
+
+// DependencyMonitor - checks dependencies (postgres, kafka brokers, downstream services) periodically and keeps
+// their latency, last success and failure times and a history of recent results
+type DependencyMonitor struct {
+	infrastructure.SugarLogger
+	cfg DependencyMonitorConfig
+
+	mu           sync.RWMutex
+	dependencies []*dependency
+	started      bool
+	cancel       context.CancelFunc
+	done         chan struct{}
+}
+
+// NewDependencyMonitor returns new DependencyMonitor
+func NewDependencyMonitor(ctx context.Context, cfg DependencyMonitorConfig) (*DependencyMonitor, error) {
+	var target DependencyMonitor
+	target.cfg = cfg
+
+	if err := target.Init(ctx); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Init - func for initialisation
+func (m *DependencyMonitor) Init(_ context.Context) error {
+	if m.cfg.CheckInterval <= 0 {
+		m.cfg.CheckInterval = defaultDependencyCheckInterval
+	}
+	if m.cfg.CheckTimeout <= 0 {
+		m.cfg.CheckTimeout = defaultDependencyCheckTimeout
+	}
+	if m.cfg.HistorySize <= 0 {
+		m.cfg.HistorySize = defaultDependencyHistorySize
+	}
+	m.done = make(chan struct{})
+	return nil
+}
+
+// Register - adds dependency. kind is one of Dependency* constants
+func (m *DependencyMonitor) Register(name, kind string, checker HealthChecker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dependencies = append(m.dependencies, &dependency{
+		name:    name,
+		kind:    kind,
+		checker: checker,
+		status:  dto.DependencyStatus{Name: name, Type: kind, Status: dto.HealthStatusUnknown},
+		history: newCheckHistory(m.cfg.HistorySize),
+	})
+}
+
+// Start - starts periodic checks. Blocks until ctx is canceled or Close is called
+func (m *DependencyMonitor) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.started {
+		m.mu.Unlock()
+		return ErrDependencyMonitorStarted
+	}
+	m.started = true
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.mu.Unlock()
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			m.LogInfo(ctx, "dependency monitor terminating: context canceled")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check - checks all dependencies concurrently
+func (m *DependencyMonitor) Check(ctx context.Context) {
+	m.mu.RLock()
+	dependencies := m.dependencies
+	m.mu.RUnlock()
+
+	wg := &sync.WaitGroup{}
+	for _, d := range dependencies {
+		wg.Add(1)
+		go func(d *dependency) {
+			defer wg.Done()
+			m.check(ctx, d)
+		}(d)
+	}
+	wg.Wait()
+}
+
+// check - runs the check of dependency with CheckTimeout and saves the result
+func (m *DependencyMonitor) check(ctx context.Context, d *dependency) {
+	checkCtx, cancel := context.WithTimeout(ctx, m.cfg.CheckTimeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- d.checker.HealthCheck(checkCtx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	res := dto.DependencyCheck{
+		CheckTime: start.UTC(),
+		Status:    dto.HealthStatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = dto.HealthStatusDown
+		res.Error = err.Error()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil && d.status.Status != dto.HealthStatusDown {
+		m.Log(ctx).Warn().Err(err).Str("dependency", d.name).Msg("dependency is unavailable")
+	}
+	if err == nil && d.status.Status == dto.HealthStatusDown {
+		m.Log(ctx).Info().Str("dependency", d.name).Msg("dependency is available again")
+	}
+	checkTime := res.CheckTime
+	d.status.Status = res.Status
+	d.status.LatencyMs = res.LatencyMs
+	d.status.Error = res.Error
+	d.status.LastCheck = &checkTime
+	if err != nil {
+		d.status.LastFailure = &checkTime
+	} else {
+		d.status.LastSuccess = &checkTime
+	}
+	d.history.add(res)
+}
+
+// Report - returns the state of all dependencies. Status is DOWN if any dependency is unavailable
+func (m *DependencyMonitor) Report(_ context.Context) dto.DependencyReport {
+	m.mu.RLock()
+	dependencies := m.dependencies
+	m.mu.RUnlock()
+
+	res := dto.DependencyReport{Status: dto.HealthStatusUp, Dependencies: make([]dto.DependencyStatus, 0, len(dependencies))}
+	for _, d := range dependencies {
+		d.mu.Lock()
+		status := d.status
+		d.mu.Unlock()
+		if status.Status == dto.HealthStatusDown {
+			res.Status = dto.HealthStatusDown
+		}
+		res.Dependencies = append(res.Dependencies, status)
+	}
+	return res
+}
+
+// History - returns up to limit recent results of the dependency checks, newest first.
+// If limit <= 0, all kept results are returned
+func (m *DependencyMonitor) History(_ context.Context, name string, limit int) ([]dto.DependencyCheck, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, d := range m.dependencies {
+		if d.name != name {
+			continue
+		}
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.history.list(limit), nil
+	}
+	return nil, dto.ErrEntityNotFound
+}
+
+// Close - stops periodic checks
+func (m *DependencyMonitor) Close(ctx context.Context) error {
+	m.mu.Lock()
+	started := m.started
+	cancel := m.cancel
+	m.mu.Unlock()
+	if !started {
+		return nil
+	}
+	cancel()
+	select {
+	case <-m.done:
+	case <-ctx.Done():
+		m.LogWarn(ctx, "dependency monitor wasn't stopped in time")
+		return ctx.Err()
+	}
+	return nil
+}
+
+// checkHistory - ring buffer of recent check results
+type checkHistory struct {
+	items []dto.DependencyCheck
+	next  int
+	full  bool
+}
+
+func newCheckHistory(size int) *checkHistory {
+	return &checkHistory{items: make([]dto.DependencyCheck, size)}
+}
+
+// add - adds the result overwriting the oldest one if the buffer is full
+func (h *checkHistory) add(item dto.DependencyCheck) {
+	h.items[h.next] = item
+	h.next = (h.next + 1) % len(h.items)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list - returns up to limit results, newest first
+func (h *checkHistory) list(limit int) []dto.DependencyCheck {
+	count := h.next
+	if h.full {
+		count = len(h.items)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+	res := make([]dto.DependencyCheck, 0, count)
+	for i := 1; i <= count; i++ {
+		res = append(res, h.items[(h.next-i+len(h.items))%len(h.items)])
+	}
+	return res
+}
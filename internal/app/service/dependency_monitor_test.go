@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-service-template/internal/app/dto"
+)
+
+func TestDependencyMonitor_Check(t *testing.T) {
+	errDown := errors.New("connection refused")
+	var postgresErr error
+	target, err := NewDependencyMonitor(context.Background(), DependencyMonitorConfig{
+		CheckTimeout: time.Millisecond * 50,
+		HistorySize:  2,
+	})
+	require.NoError(t, err)
+	target.Register("postgres", DependencyPostgres, HealthCheckFunc(func(ctx context.Context) error { return postgresErr }))
+	target.Register("localhost:9092", DependencyKafkaBroker, HealthCheckFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	report := target.Report(context.Background())
+	assert.Equal(t, dto.HealthStatusUp, report.Status)
+	assert.Equal(t, dto.HealthStatusUnknown, report.Dependencies[0].Status)
+
+	target.Check(context.Background())
+	report = target.Report(context.Background())
+	assert.Equal(t, dto.HealthStatusDown, report.Status)
+	postgres, broker := report.Dependencies[0], report.Dependencies[1]
+	assert.Equal(t, dto.HealthStatusUp, postgres.Status)
+	assert.NotNil(t, postgres.LastSuccess)
+	assert.Nil(t, postgres.LastFailure)
+	assert.Equal(t, dto.HealthStatusDown, broker.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), broker.Error)
+	assert.NotNil(t, broker.LastFailure)
+	assert.GreaterOrEqual(t, broker.LatencyMs, float64(50))
+
+	postgresErr = errDown
+	target.Check(context.Background())
+	postgres = target.Report(context.Background()).Dependencies[0]
+	assert.Equal(t, dto.HealthStatusDown, postgres.Status)
+	assert.Equal(t, errDown.Error(), postgres.Error)
+	assert.NotNil(t, postgres.LastSuccess)
+	assert.NotNil(t, postgres.LastFailure)
+
+	history, err := target.History(context.Background(), "postgres", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, dto.HealthStatusDown, history[0].Status)
+	assert.Equal(t, dto.HealthStatusUp, history[1].Status)
+
+	_, err = target.History(context.Background(), "redis", 0)
+	assert.ErrorIs(t, err, dto.ErrEntityNotFound)
+}
+
+func TestCheckHistory_list(t *testing.T) {
+	check := func(n int) dto.DependencyCheck {
+		return dto.DependencyCheck{LatencyMs: float64(n)}
+	}
+	tests := []struct {
+		name  string
+		added int
+		limit int
+		want  []dto.DependencyCheck
+	}{
+		{name: "checkHistory.list Case#1 Empty", want: []dto.DependencyCheck{}},
+		{name: "checkHistory.list Case#2 Not full", added: 2, want: []dto.DependencyCheck{check(2), check(1)}},
+		{name: "checkHistory.list Case#3 Oldest are overwritten", added: 5, want: []dto.DependencyCheck{check(5), check(4), check(3)}},
+		{name: "checkHistory.list Case#4 Limit", added: 5, limit: 2, want: []dto.DependencyCheck{check(5), check(4)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newCheckHistory(3)
+			for i := 1; i <= tt.added; i++ {
+				h.add(check(i))
+			}
+			assert.Equal(t, tt.want, h.list(tt.limit))
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	defaultHealthCheckTimeout = time.Second * 2
+	defaultHealthCacheTTL     = time.Second
+)
+
+var (
+	// ErrShuttingDown - "service is shutting down" error
+	ErrShuttingDown = errors.New("service is shutting down")
+
+	// ErrNotStarted - "service isn't started yet" error
+	ErrNotStarted = errors.New("service isn't started yet")
+)
+
+// HealthChecker - interface for resources contributing into readiness. HealthCheck must be cheap,
+// it's called by probes (results are cached for HealthConfig.CacheTTL)
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthCheckFunc - adapter for using ordinary functions as HealthChecker
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthCheck - implementation of HealthChecker
+func (f HealthCheckFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthConfig - struct for health probes params
+type HealthConfig struct {
+	// CheckTimeout - default timeout of one check
+	CheckTimeout time.Duration `yaml:"checkTimeout"`
+
+	// CacheTTL - check results are cached for CacheTTL, so frequent probes don't load dependencies
+	CacheTTL time.Duration `yaml:"cacheTTL"` //nolint:tagliatelle
+
+	// ShutdownDelay - delay between readiness going false and the start of shutdown.
+	// It gives the balancer time to stop sending new requests
+	ShutdownDelay time.Duration `yaml:"shutdownDelay"`
+}
+
+type healthCheck struct {
+	name    string
+	checker HealthChecker
+	timeout time.Duration
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// HealthService - registry of dependency checks for liveness, readiness and startup probes
+type HealthService struct {
+	infrastructure.SugarLogger
+	cfg HealthConfig
+
+	mu           sync.RWMutex
+	checks       []*healthCheck
+	started      int32
+	shuttingDown int32
+}
+
+// NewHealthService returns new HealthService
+func NewHealthService(cfg HealthConfig) *HealthService {
+	var target HealthService
+	target.cfg = cfg
+	if target.cfg.CheckTimeout <= 0 {
+		target.cfg.CheckTimeout = defaultHealthCheckTimeout
+	}
+	if target.cfg.CacheTTL <= 0 {
+		target.cfg.CacheTTL = defaultHealthCacheTTL
+	}
+	return &target
+}
+
+// Register - adds readiness check. If timeout <= 0, HealthConfig.CheckTimeout is used
+func (s *HealthService) Register(name string, checker HealthChecker, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = s.cfg.CheckTimeout
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = append(s.checks, &healthCheck{name: name, checker: checker, timeout: timeout})
+}
+
+// MarkStarted - marks the end of service start. Startup probe succeeds after it
+func (s *HealthService) MarkStarted() {
+	atomic.StoreInt32(&s.started, 1)
+}
+
+// MarkShuttingDown - makes readiness false. Is called before the http server starts draining
+func (s *HealthService) MarkShuttingDown() {
+	atomic.StoreInt32(&s.shuttingDown, 1)
+}
+
+// ShutdownDelay - returns delay between MarkShuttingDown and the start of shutdown
+func (s *HealthService) ShutdownDelay() time.Duration {
+	return s.cfg.ShutdownDelay
+}
+
+// Live - liveness. Service is alive while it can answer
+func (s *HealthService) Live(_ context.Context) dto.Health {
+	return dto.Health{Status: dto.HealthStatusUp}
+}
+
+// Startup - succeeds when the service is started
+func (s *HealthService) Startup(_ context.Context) dto.Health {
+	if atomic.LoadInt32(&s.started) == 0 {
+		return downHealth("startup", ErrNotStarted)
+	}
+	return dto.Health{Status: dto.HealthStatusUp}
+}
+
+// Ready - readiness. Service is ready if it is started, isn't shutting down and all checks succeed
+func (s *HealthService) Ready(ctx context.Context) dto.Health {
+	if atomic.LoadInt32(&s.shuttingDown) == 1 {
+		return downHealth("shutdown", ErrShuttingDown)
+	}
+	if atomic.LoadInt32(&s.started) == 0 {
+		return downHealth("startup", ErrNotStarted)
+	}
+
+	s.mu.RLock()
+	checks := s.checks
+	s.mu.RUnlock()
+
+	res := dto.Health{Status: dto.HealthStatusUp, Checks: make(map[string]dto.HealthCheck, len(checks))}
+	results := make([]error, len(checks))
+	wg := &sync.WaitGroup{}
+	for ind, check := range checks {
+		wg.Add(1)
+		go func(ind int, check *healthCheck) {
+			defer wg.Done()
+			results[ind] = s.check(ctx, check)
+		}(ind, check)
+	}
+	wg.Wait()
+
+	for ind, check := range checks {
+		if err := results[ind]; err != nil {
+			res.Status = dto.HealthStatusDown
+			res.Checks[check.name] = dto.HealthCheck{Status: dto.HealthStatusDown, Error: err.Error()}
+			continue
+		}
+		res.Checks[check.name] = dto.HealthCheck{Status: dto.HealthStatusUp}
+	}
+	return res
+}
+
+// check - returns cached result of the check or runs the check if the result is expired
+func (s *HealthService) check(ctx context.Context, check *healthCheck) error {
+	check.mu.Lock()
+	defer check.mu.Unlock()
+	if !check.checkedAt.IsZero() && time.Since(check.checkedAt) < s.cfg.CacheTTL {
+		return check.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, check.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.checker.HealthCheck(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil && check.err == nil {
+		s.Log(ctx).Warn().Err(err).Str("check", check.name).Msg("health check failed")
+	}
+	check.checkedAt = time.Now()
+	check.err = err
+	return err
+}
+
+func downHealth(name string, err error) dto.Health {
+	return dto.Health{
+		Status: dto.HealthStatusDown,
+		Checks: map[string]dto.HealthCheck{name: {Status: dto.HealthStatusDown, Error: err.Error()}},
+	}
+}
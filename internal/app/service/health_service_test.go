@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/dto"
+)
+
+func TestHealthService_Ready(t *testing.T) {
+	errDown := errors.New("connection refused")
+	up := HealthCheckFunc(func(ctx context.Context) error { return nil })
+	down := HealthCheckFunc(func(ctx context.Context) error { return errDown })
+	slow := HealthCheckFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	tests := []struct {
+		name         string
+		checks       map[string]HealthChecker
+		started      bool
+		shuttingDown bool
+		want         dto.Health
+	}{
+		{
+			name:    "HealthService.Ready Case#1 All checks succeed",
+			checks:  map[string]HealthChecker{"postgres": up, "kafka-producer": up},
+			started: true,
+			want: dto.Health{Status: dto.HealthStatusUp, Checks: map[string]dto.HealthCheck{
+				"postgres":       {Status: dto.HealthStatusUp},
+				"kafka-producer": {Status: dto.HealthStatusUp},
+			}},
+		},
+		{
+			name:    "HealthService.Ready Case#2 Check failed",
+			checks:  map[string]HealthChecker{"postgres": down, "kafka-producer": up},
+			started: true,
+			want: dto.Health{Status: dto.HealthStatusDown, Checks: map[string]dto.HealthCheck{
+				"postgres":       {Status: dto.HealthStatusDown, Error: errDown.Error()},
+				"kafka-producer": {Status: dto.HealthStatusUp},
+			}},
+		},
+		{
+			name:    "HealthService.Ready Case#3 Check timeout",
+			checks:  map[string]HealthChecker{"postgres": slow},
+			started: true,
+			want: dto.Health{Status: dto.HealthStatusDown, Checks: map[string]dto.HealthCheck{
+				"postgres": {Status: dto.HealthStatusDown, Error: context.DeadlineExceeded.Error()},
+			}},
+		},
+		{
+			name:   "HealthService.Ready Case#4 Not started",
+			checks: map[string]HealthChecker{"postgres": up},
+			want: dto.Health{Status: dto.HealthStatusDown, Checks: map[string]dto.HealthCheck{
+				"startup": {Status: dto.HealthStatusDown, Error: ErrNotStarted.Error()},
+			}},
+		},
+		{
+			name:         "HealthService.Ready Case#5 Shutting down",
+			checks:       map[string]HealthChecker{"postgres": up},
+			started:      true,
+			shuttingDown: true,
+			want: dto.Health{Status: dto.HealthStatusDown, Checks: map[string]dto.HealthCheck{
+				"shutdown": {Status: dto.HealthStatusDown, Error: ErrShuttingDown.Error()},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := NewHealthService(HealthConfig{CheckTimeout: time.Millisecond * 50})
+			for name, checker := range tt.checks {
+				target.Register(name, checker, 0)
+			}
+			if tt.started {
+				target.MarkStarted()
+			}
+			if tt.shuttingDown {
+				target.MarkShuttingDown()
+			}
+			assert.Equal(t, tt.want, target.Ready(context.Background()))
+		})
+	}
+}
+
+func TestHealthService_ReadyCache(t *testing.T) {
+	var calls int32
+	target := NewHealthService(HealthConfig{CacheTTL: time.Hour})
+	target.Register("postgres", HealthCheckFunc(func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}), 0)
+	target.MarkStarted()
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, dto.HealthStatusUp, target.Ready(context.Background()).Status)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestHealthService_Startup(t *testing.T) {
+	target := NewHealthService(HealthConfig{})
+	assert.Equal(t, dto.HealthStatusUp, target.Live(context.Background()).Status)
+	assert.Equal(t, dto.HealthStatusDown, target.Startup(context.Background()).Status)
+	target.MarkStarted()
+	assert.Equal(t, dto.HealthStatusUp, target.Startup(context.Background()).Status)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/infrastructure"
+)
+
+const (
+	defaultJobListLimit = 100
+	maxJobListLimit     = 1000
+)
+
+// JobRepository interface for background jobs repository
+//
+//go:generate mockgen -destination=mocks/mock_job_repository.go -package=mocks . JobRepository
+type JobRepository interface {
+	List(ctx context.Context, filter dto.JobFilter) ([]dto.Job, error)
+	ListDead(ctx context.Context, filter dto.JobFilter) ([]dto.Job, error)
+	Retry(ctx context.Context, id int64) error
+	RetryDead(ctx context.Context, id int64) error
+}
+
+// JobService service for background jobs administration
+type JobService struct {
+	infrastructure.SugarLogger
+	jobRepository JobRepository
+	txHelper      TxHelper
+}
+
+// NewJobService returns new JobService
+func NewJobService(repo JobRepository, txHelper TxHelper) *JobService {
+	var target JobService
+	target.jobRepository = repo
+	target.txHelper = txHelper
+	return &target
+}
+
+// List - returns jobs waiting in the queue or running
+func (s *JobService) List(ctx context.Context, filter dto.JobFilter) ([]dto.Job, error) {
+	return s.jobRepository.List(ctx, limitJobFilter(filter))
+}
+
+// ListDead - returns jobs failed after all attempts
+func (s *JobService) ListDead(ctx context.Context, filter dto.JobFilter) ([]dto.Job, error) {
+	return s.jobRepository.ListDead(ctx, limitJobFilter(filter))
+}
+
+// Retry - runs pending job immediately
+func (s *JobService) Retry(ctx context.Context, id int64) error {
+	return s.txHelper.WithTx(ctx)(ctx, func(ctx context.Context) error {
+		return s.jobRepository.Retry(ctx, id)
+	})
+}
+
+// RetryDead - returns dead job into the queue
+func (s *JobService) RetryDead(ctx context.Context, id int64) error {
+	return s.txHelper.WithTx(ctx)(ctx, func(ctx context.Context) error {
+		return s.jobRepository.RetryDead(ctx, id)
+	})
+}
+
+// limitJobFilter - applies default and max limit of the list
+func limitJobFilter(filter dto.JobFilter) dto.JobFilter {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultJobListLimit
+	}
+	if filter.Limit > maxJobListLimit {
+		filter.Limit = maxJobListLimit
+	}
+	return filter
+}
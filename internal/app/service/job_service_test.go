@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go-service-template/internal/app/dto"
+	"go-service-template/internal/app/service/mocks"
+)
+
+func TestJobService_List(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter dto.JobFilter
+		want   dto.JobFilter
+	}{
+		{
+			name:   "JobService.List Case#1 Default limit",
+			filter: dto.JobFilter{Queue: "default"},
+			want:   dto.JobFilter{Queue: "default", Limit: defaultJobListLimit},
+		},
+		{
+			name:   "JobService.List Case#2 Limit is kept",
+			filter: dto.JobFilter{Limit: 10},
+			want:   dto.JobFilter{Limit: 10},
+		},
+		{
+			name:   "JobService.List Case#3 Max limit",
+			filter: dto.JobFilter{Limit: maxJobListLimit + 1},
+			want:   dto.JobFilter{Limit: maxJobListLimit},
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	jobRepository := mocks.NewMockJobRepository(mockCtrl)
+	target := NewJobService(jobRepository, mocks.NewMockTxHelper(mockCtrl))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobRepository.EXPECT().List(gomock.Any(), tt.want).Return([]dto.Job{}, nil)
+			res, err := target.List(context.Background(), tt.filter)
+			assert.NoError(t, err)
+			assert.Empty(t, res)
+		})
+	}
+}
+
+func TestJobService_RetryDead(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	jobRepository := mocks.NewMockJobRepository(mockCtrl)
+	txHelper := mocks.NewMockTxHelper(mockCtrl)
+	target := NewJobService(jobRepository, txHelper)
+
+	repoErr := errors.New("some db error")
+	txHelper.EXPECT().WithTx(gomock.Any()).Return(TxFunc(func(ctx context.Context, f InFunc) error {
+		return f(ctx)
+	}))
+	jobRepository.EXPECT().RetryDead(gomock.Any(), int64(7)).Return(repoErr)
+	assert.ErrorIs(t, target.RetryDead(context.Background(), 7), repoErr)
+}
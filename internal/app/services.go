@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"go-service-template/internal/app/handler"
+	httpClient "go-service-template/internal/app/infrastructure/http"
+	"go-service-template/internal/app/repository"
+	"go-service-template/internal/app/rest"
+	"go-service-template/internal/app/service"
+)
+
+func httpClientModule() Module {
+	return Module{Name: "http-client", Init: initHTTPClient}
+}
+
+// initHTTPClient - prepares clients of downstream services
+func initHTTPClient(_ context.Context, a *App) error {
+	httpClient.InitBaseHTTPClient(a.Config.HTTPClient)
+	a.PingClient = rest.NewPingClient("http://localhost:8080/api/v1/ping")
+	return nil
+}
+
+func servicesModule() Module {
+	return Module{
+		Name:      "services",
+		DependsOn: []string{"postgres", "kafka-producer", "http-client", "dependency-monitor"},
+		Init:      initServices,
+	}
+}
+
+// initServices - builds repositories, services and handlers
+func initServices(_ context.Context, a *App) error {
+	// 1. Repositories
+	pingDBRepository := repository.NewPingRepository(a.DB)
+	pingKafkaRepository, err := repository.NewPingKafkaRepository(a.Producer)
+	if err != nil {
+		return fmt.Errorf("can't create PingKafkaRepository: %w", err)
+	}
+
+	// 2. Services
+	a.PingService = service.NewPingService(pingDBRepository, pingKafkaRepository, a.DB)
+	jobService := service.NewJobService(repository.NewJobRepository(a.DB), a.DB)
+
+	// 3. Handlers
+	a.PingHandler = handler.NewPingHandler(a.PingService, a.PingClient)
+	a.JobHandler = handler.NewJobHandler(jobService)
+	a.HealthHandler = handler.NewHealthHandler(a.Health)
+	a.DependencyHandler = handler.NewDependencyHandler(a.DependencyMonitor)
+	return nil
+}
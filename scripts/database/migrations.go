@@ -0,0 +1,11 @@
+package database
+
+import "embed"
+
+// MigrationsPath - path to the migrations inside Migrations
+const MigrationsPath = "migrations"
+
+// Migrations - database migrations embedded into the binary
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
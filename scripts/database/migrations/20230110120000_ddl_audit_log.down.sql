@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP SEQUENCE IF EXISTS audit_log_sq CASCADE;
+DROP TABLE IF EXISTS audit_log CASCADE;
+
+COMMIT;
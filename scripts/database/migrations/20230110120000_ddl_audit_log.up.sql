@@ -0,0 +1,31 @@
+BEGIN;
+CREATE SEQUENCE if not exists audit_log_sq
+    START WITH 1 INCREMENT BY 1;
+
+create table if not exists audit_log
+(
+    id          bigint          PRIMARY KEY,
+    change_time timestamp       NOT NULL,
+    table_name  varchar(128)    NOT NULL,
+    entity_id   varchar(256),
+    operation   varchar(10)     NOT NULL,
+    old_data    jsonb,
+    new_data    jsonb,
+    app_user    varchar(256),
+    request_id  varchar(128)
+);
+
+create index if not exists audit_log_entity_idx on audit_log (table_name, entity_id, change_time);
+
+comment on table audit_log is 'История изменений сущностей';
+comment on column audit_log.id is 'Идентификатор записи';
+comment on column audit_log.change_time is 'Время изменения';
+comment on column audit_log.table_name is 'Таблица';
+comment on column audit_log.entity_id is 'Идентификатор сущности';
+comment on column audit_log.operation is 'Тип операции (INSERT, UPDATE, DELETE)';
+comment on column audit_log.old_data is 'Строка до изменения';
+comment on column audit_log.new_data is 'Строка после изменения';
+comment on column audit_log.app_user is 'Пользователь приложения (app.user)';
+comment on column audit_log.request_id is 'Идентификатор запроса (app.request_id)';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP FUNCTION IF EXISTS audit_log_enable(regclass, text) CASCADE;
+DROP FUNCTION IF EXISTS audit_log_disable(regclass) CASCADE;
+DROP FUNCTION IF EXISTS audit_log_add_fnc() CASCADE;
+
+COMMIT;
@@ -0,0 +1,51 @@
+BEGIN;
+
+-- Generic audit trigger function. The only optional argument is the name of the id column (default - id).
+-- Application user and request id are taken from app.user and app.request_id settings,
+-- which are set by PostgresqlHandlerTX for each transaction (SET LOCAL)
+CREATE OR REPLACE FUNCTION audit_log_add_fnc()
+    RETURNS trigger
+    LANGUAGE 'plpgsql' AS
+$$
+DECLARE
+    _id_column text := coalesce(TG_ARGV[0], 'id');
+    _old_data  jsonb;
+    _new_data  jsonb;
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        _old_data := to_jsonb(OLD);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        _new_data := to_jsonb(NEW);
+    END IF;
+
+    INSERT INTO audit_log (id, change_time, table_name, entity_id, operation, old_data, new_data, app_user, request_id)
+    VALUES (nextval('audit_log_sq'), now(), TG_TABLE_NAME,
+            coalesce(_new_data, _old_data) ->> _id_column, TG_OP, _old_data, _new_data,
+            nullif(current_setting('app.user', true), ''), nullif(current_setting('app.request_id', true), ''));
+    RETURN NULL;
+END
+$$;
+
+-- Enables audit for the table: audit_log_enable('orders') or audit_log_enable('orders', 'order_id')
+CREATE OR REPLACE FUNCTION audit_log_enable(_table regclass, _id_column text DEFAULT 'id')
+    RETURNS void
+    LANGUAGE 'plpgsql' AS
+$$
+BEGIN
+    EXECUTE format('CREATE OR REPLACE TRIGGER audit_log_trigger AFTER INSERT OR UPDATE OR DELETE ON %s '
+                       'FOR EACH ROW EXECUTE PROCEDURE audit_log_add_fnc(%L)', _table, _id_column);
+END
+$$;
+
+-- Disables audit for the table
+CREATE OR REPLACE FUNCTION audit_log_disable(_table regclass)
+    RETURNS void
+    LANGUAGE 'plpgsql' AS
+$$
+BEGIN
+    EXECUTE format('DROP TRIGGER IF EXISTS audit_log_trigger ON %s', _table);
+END
+$$;
+
+COMMIT;
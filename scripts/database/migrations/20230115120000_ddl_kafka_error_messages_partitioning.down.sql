@@ -0,0 +1,60 @@
+BEGIN;
+
+-- 1. kafka_in_error_messages
+ALTER TABLE kafka_in_error_messages RENAME TO kafka_in_error_messages_partitioned;
+
+create table kafka_in_error_messages
+(
+    id                 int8        not null,
+    headers_cs         jsonb,
+    timestamp_cs       timestamptz,
+    block_timestamp_cs timestamptz,
+    key_cs             bytea,
+    value_cs           bytea       not null,
+    topic_cs           varchar     not null,
+    partition_cs       int4,
+    offset_cs          int8,
+    error_text         varchar     not null,
+    receive_time       timestamptz not null,
+    headers_txt        bytea
+);
+
+INSERT INTO kafka_in_error_messages (id, headers_cs, timestamp_cs, block_timestamp_cs, key_cs, value_cs, topic_cs,
+                                     partition_cs, offset_cs, error_text, receive_time, headers_txt)
+SELECT id, headers_cs, timestamp_cs, block_timestamp_cs, key_cs, value_cs, topic_cs,
+       partition_cs, offset_cs, error_text, receive_time, headers_txt
+FROM kafka_in_error_messages_partitioned;
+
+DROP TABLE kafka_in_error_messages_partitioned CASCADE;
+
+-- 2. kafka_out_error_messages
+ALTER TABLE kafka_out_error_messages RENAME TO kafka_out_error_messages_partitioned;
+
+create table kafka_out_error_messages
+(
+    id           int8        not null,
+    topic_pc     varchar     not null,
+    key_pc       varchar,
+    value_pc     bytea       not null,
+    headers_pc   jsonb,
+    metadata_pc  jsonb,
+    offset_pc    int8,
+    partition_pc int4,
+    timestamp_pc timestamptz,
+    error_text   varchar     not null,
+    send_time    timestamptz not null,
+    headers_txt  bytea
+);
+
+INSERT INTO kafka_out_error_messages (id, topic_pc, key_pc, value_pc, headers_pc, metadata_pc, offset_pc, partition_pc,
+                                      timestamp_pc, error_text, send_time, headers_txt)
+SELECT id, topic_pc, key_pc, value_pc, headers_pc, metadata_pc, offset_pc, partition_pc,
+       timestamp_pc, error_text, send_time, headers_txt
+FROM kafka_out_error_messages_partitioned;
+
+DROP TABLE kafka_out_error_messages_partitioned CASCADE;
+
+DROP FUNCTION IF EXISTS partition_create_months(text, date, date) CASCADE;
+DROP FUNCTION IF EXISTS partition_create_month(text, date) CASCADE;
+
+COMMIT;
@@ -0,0 +1,140 @@
+BEGIN;
+
+-- Creates monthly partition of the table for the month of _month. Partition name is <table>_pYYYYMM
+CREATE OR REPLACE FUNCTION partition_create_month(_table text, _month date)
+    RETURNS text
+    LANGUAGE 'plpgsql' AS
+$$
+DECLARE
+    _from      date := date_trunc('month', _month);
+    _partition text := _table || '_p' || to_char(_from, 'YYYYMM');
+BEGIN
+    EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+                   _partition, _table, _from, _from + interval '1 month');
+    RETURN _partition;
+END
+$$;
+
+-- Creates monthly partitions of the table from _from till _to (inclusive)
+CREATE OR REPLACE FUNCTION partition_create_months(_table text, _from date, _to date)
+    RETURNS void
+    LANGUAGE 'plpgsql' AS
+$$
+DECLARE
+    _month date := date_trunc('month', _from);
+BEGIN
+    WHILE _month <= _to
+        LOOP
+            PERFORM partition_create_month(_table, _month);
+            _month := _month + interval '1 month';
+        END LOOP;
+END
+$$;
+
+-- 1. kafka_in_error_messages
+ALTER TABLE kafka_in_error_messages RENAME TO kafka_in_error_messages_old;
+
+create table kafka_in_error_messages
+(
+    id                 int8        not null,
+
+    headers_cs         jsonb,
+    headers_txt        bytea,
+    timestamp_cs       timestamptz,
+    block_timestamp_cs timestamptz,
+
+    key_cs             bytea,
+    value_cs           bytea       not null,
+    topic_cs           varchar     not null,
+    partition_cs       int4,
+    offset_cs          int8,
+
+    error_text         varchar     not null,
+    receive_time       timestamptz not null,
+    PRIMARY KEY (id, receive_time)
+) PARTITION BY RANGE (receive_time);
+
+CREATE TABLE kafka_in_error_messages_default PARTITION OF kafka_in_error_messages DEFAULT;
+SELECT partition_create_months('kafka_in_error_messages',
+                               coalesce((SELECT min(receive_time) FROM kafka_in_error_messages_old), now())::date,
+                               (now() + interval '3 month')::date);
+
+create index kafka_in_error_messages_topic_idx on kafka_in_error_messages (topic_cs, receive_time);
+create index kafka_in_error_messages_time_idx on kafka_in_error_messages (receive_time);
+
+INSERT INTO kafka_in_error_messages (id, headers_cs, headers_txt, timestamp_cs, block_timestamp_cs, key_cs, value_cs,
+                                     topic_cs, partition_cs, offset_cs, error_text, receive_time)
+SELECT id, headers_cs, headers_txt, timestamp_cs, block_timestamp_cs, key_cs, value_cs,
+       topic_cs, partition_cs, offset_cs, error_text, receive_time
+FROM kafka_in_error_messages_old;
+
+DROP TABLE kafka_in_error_messages_old;
+
+comment on table kafka_in_error_messages is 'Incoming message processing errors history. Partitioned by month';
+comment on column kafka_in_error_messages.id is 'ID';
+comment on column kafka_in_error_messages.headers_cs is 'Message headers';
+comment on column kafka_in_error_messages.headers_txt is 'Message headers in text form';
+comment on column kafka_in_error_messages.timestamp_cs is 'message timestamp';
+comment on column kafka_in_error_messages.block_timestamp_cs is 'block timestamp';
+comment on column kafka_in_error_messages.key_cs is 'message key';
+comment on column kafka_in_error_messages.value_cs is 'message';
+comment on column kafka_in_error_messages.topic_cs is 'topic name';
+comment on column kafka_in_error_messages.partition_cs is 'partition';
+comment on column kafka_in_error_messages.offset_cs is 'message offset';
+comment on column kafka_in_error_messages.error_text is 'error text';
+comment on column kafka_in_error_messages.receive_time is 'event timestamp';
+
+-- 2. kafka_out_error_messages
+ALTER TABLE kafka_out_error_messages RENAME TO kafka_out_error_messages_old;
+
+create table kafka_out_error_messages
+(
+    id           int8        not null,
+
+    topic_pc     varchar     not null,
+    key_pc       varchar,
+    value_pc     bytea       not null,
+    headers_pc   jsonb,
+    headers_txt  bytea,
+
+    metadata_pc  jsonb,
+    offset_pc    int8,
+    partition_pc int4,
+    timestamp_pc timestamptz,
+
+    error_text   varchar     not null,
+    send_time    timestamptz not null,
+    PRIMARY KEY (id, send_time)
+) PARTITION BY RANGE (send_time);
+
+CREATE TABLE kafka_out_error_messages_default PARTITION OF kafka_out_error_messages DEFAULT;
+SELECT partition_create_months('kafka_out_error_messages',
+                               coalesce((SELECT min(send_time) FROM kafka_out_error_messages_old), now())::date,
+                               (now() + interval '3 month')::date);
+
+create index kafka_out_error_messages_topic_idx on kafka_out_error_messages (topic_pc, send_time);
+create index kafka_out_error_messages_time_idx on kafka_out_error_messages (send_time);
+
+INSERT INTO kafka_out_error_messages (id, topic_pc, key_pc, value_pc, headers_pc, headers_txt, metadata_pc, offset_pc,
+                                      partition_pc, timestamp_pc, error_text, send_time)
+SELECT id, topic_pc, key_pc, value_pc, headers_pc, headers_txt, metadata_pc, offset_pc,
+       partition_pc, timestamp_pc, error_text, send_time
+FROM kafka_out_error_messages_old;
+
+DROP TABLE kafka_out_error_messages_old;
+
+comment on table kafka_out_error_messages is 'Outgoing message processing errors history. Partitioned by month';
+comment on column kafka_out_error_messages.id is 'ID';
+comment on column kafka_out_error_messages.topic_pc is 'Topic';
+comment on column kafka_out_error_messages.key_pc is 'Message key';
+comment on column kafka_out_error_messages.value_pc is 'Message';
+comment on column kafka_out_error_messages.headers_pc is 'Message headers';
+comment on column kafka_out_error_messages.headers_txt is 'Message headers in text form';
+comment on column kafka_out_error_messages.metadata_pc is 'Metadata';
+comment on column kafka_out_error_messages.offset_pc is 'Message offset';
+comment on column kafka_out_error_messages.partition_pc is 'partition';
+comment on column kafka_out_error_messages.timestamp_pc is 'message timestamp';
+comment on column kafka_out_error_messages.error_text is 'error text';
+comment on column kafka_out_error_messages.send_time is 'event timestamp';
+
+COMMIT;
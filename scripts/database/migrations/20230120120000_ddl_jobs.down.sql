@@ -0,0 +1,5 @@
+BEGIN;
+DROP TABLE IF EXISTS jobs_dead;
+DROP TABLE IF EXISTS jobs;
+DROP SEQUENCE IF EXISTS jobs_sq;
+COMMIT;
@@ -0,0 +1,68 @@
+BEGIN;
+CREATE SEQUENCE if not exists jobs_sq
+    START WITH 1 INCREMENT BY 1;
+
+create table if not exists jobs
+(
+    id           int8         PRIMARY KEY DEFAULT nextval('jobs_sq'),
+    queue        varchar(128) NOT NULL,
+    kind         varchar(128) NOT NULL,
+    payload      jsonb        NOT NULL,
+    unique_key   varchar(256),
+    status       varchar(16)  NOT NULL DEFAULT 'pending',
+    attempt      int4         NOT NULL DEFAULT 0,
+    max_attempts int4         NOT NULL,
+    run_at       timestamptz  NOT NULL DEFAULT now(),
+    locked_at    timestamptz,
+    locked_by    varchar(256),
+    last_error   varchar,
+    create_time  timestamptz  NOT NULL DEFAULT now()
+);
+
+create unique index if not exists jobs_unique_key_uidx on jobs (kind, unique_key) where unique_key is not null;
+create index if not exists jobs_fetch_idx on jobs (queue, run_at) where status = 'pending';
+create index if not exists jobs_locked_idx on jobs (locked_at) where status = 'running';
+
+comment on table jobs is 'Background jobs queue';
+comment on column jobs.id is 'ID';
+comment on column jobs.queue is 'Queue name';
+comment on column jobs.kind is 'Job kind. Defines job handler';
+comment on column jobs.payload is 'Job arguments';
+comment on column jobs.unique_key is 'Uniqueness key. Only one job of the kind with the key can be in the queue';
+comment on column jobs.status is 'pending - waiting for a worker, running - taken by a worker';
+comment on column jobs.attempt is 'Count of started attempts';
+comment on column jobs.max_attempts is 'Max count of attempts. Job is moved into jobs_dead after the last failed attempt';
+comment on column jobs.run_at is 'Job is not started before this time';
+comment on column jobs.locked_at is 'Time when job was taken by a worker';
+comment on column jobs.locked_by is 'Worker name';
+comment on column jobs.last_error is 'Error of the last attempt';
+comment on column jobs.create_time is 'Enqueue time';
+
+create table if not exists jobs_dead
+(
+    id           int8         PRIMARY KEY,
+    queue        varchar(128) NOT NULL,
+    kind         varchar(128) NOT NULL,
+    payload      jsonb        NOT NULL,
+    unique_key   varchar(256),
+    attempt      int4         NOT NULL,
+    max_attempts int4         NOT NULL,
+    last_error   varchar,
+    create_time  timestamptz  NOT NULL,
+    failed_time  timestamptz  NOT NULL DEFAULT now()
+);
+
+create index if not exists jobs_dead_failed_idx on jobs_dead (failed_time);
+
+comment on table jobs_dead is 'Jobs failed after all attempts';
+comment on column jobs_dead.id is 'ID of the job';
+comment on column jobs_dead.queue is 'Queue name';
+comment on column jobs_dead.kind is 'Job kind';
+comment on column jobs_dead.payload is 'Job arguments';
+comment on column jobs_dead.unique_key is 'Uniqueness key';
+comment on column jobs_dead.attempt is 'Count of attempts';
+comment on column jobs_dead.max_attempts is 'Max count of attempts';
+comment on column jobs_dead.last_error is 'Error of the last attempt';
+comment on column jobs_dead.create_time is 'Enqueue time';
+comment on column jobs_dead.failed_time is 'Time of the last failed attempt';
+COMMIT;
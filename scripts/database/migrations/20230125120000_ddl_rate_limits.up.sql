@@ -0,0 +1,17 @@
+BEGIN;
+create table if not exists rate_limits
+(
+    key         varchar(512)     PRIMARY KEY,
+    tokens      double precision NOT NULL,
+    allowed     boolean          NOT NULL,
+    update_time timestamptz      NOT NULL DEFAULT now()
+);
+
+create index if not exists rate_limits_update_time_idx on rate_limits (update_time);
+
+comment on table rate_limits is 'Token buckets of rate limiter shared by service instances';
+comment on column rate_limits.key is 'Client key with route';
+comment on column rate_limits.tokens is 'Tokens left in the bucket at update_time';
+comment on column rate_limits.allowed is 'Result of the last request';
+comment on column rate_limits.update_time is 'Time of the last request';
+COMMIT;
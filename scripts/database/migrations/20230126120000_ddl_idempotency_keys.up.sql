@@ -0,0 +1,23 @@
+BEGIN;
+create table if not exists idempotency_keys
+(
+    key         varchar(512) PRIMARY KEY,
+    fingerprint varchar(64)  NOT NULL,
+    status      integer,
+    headers     jsonb,
+    body        bytea,
+    create_time timestamptz  NOT NULL DEFAULT now(),
+    expire_time timestamptz  NOT NULL
+);
+
+create index if not exists idempotency_keys_expire_time_idx on idempotency_keys (expire_time);
+
+comment on table idempotency_keys is 'Responses of requests with Idempotency-Key header';
+comment on column idempotency_keys.key is 'Client scope with idempotency key';
+comment on column idempotency_keys.fingerprint is 'Hash of method, URI and body of the request';
+comment on column idempotency_keys.status is 'Response status. It is null while the request is in progress';
+comment on column idempotency_keys.headers is 'Response headers';
+comment on column idempotency_keys.body is 'Response body';
+comment on column idempotency_keys.create_time is 'Time of the first request';
+comment on column idempotency_keys.expire_time is 'Key may be reused after expire_time';
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP FUNCTION IF EXISTS partition_remove(text, text, text);
+
+ALTER FUNCTION partition_create_month(text, date) SECURITY INVOKER;
+ALTER FUNCTION partition_create_month(text, date) RESET search_path;
+GRANT EXECUTE ON FUNCTION partition_create_month(text, date) TO PUBLIC;
+
+COMMIT;
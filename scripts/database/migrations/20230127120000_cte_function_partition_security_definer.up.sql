@@ -0,0 +1,45 @@
+BEGIN;
+
+-- Drops expired monthly partition of the table or moves it into _archive_schema.
+-- Only partitions named <table>_pYYYYMM (see partition_create_month) are removed
+CREATE OR REPLACE FUNCTION partition_remove(_table text, _partition text, _archive_schema text DEFAULT NULL)
+    RETURNS void
+    LANGUAGE 'plpgsql'
+    SECURITY DEFINER AS
+$$
+BEGIN
+    IF left(_partition, length(_table) + 2) <> _table || '_p'
+        OR substr(_partition, length(_table) + 3) !~ '^[0-9]{6}$' THEN
+        RAISE EXCEPTION '% is not a monthly partition of %', _partition, _table;
+    END IF;
+    IF NOT EXISTS(SELECT 1
+                  FROM pg_inherits i
+                  WHERE i.inhparent = to_regclass(quote_ident(_table))
+                    AND i.inhrelid = to_regclass(quote_ident(_partition))) THEN
+        RAISE EXCEPTION 'partition % of % not found', _partition, _table;
+    END IF;
+
+    IF coalesce(_archive_schema, '') = '' THEN
+        EXECUTE format('DROP TABLE %I', _partition);
+    ELSE
+        EXECUTE format('ALTER TABLE %I DETACH PARTITION %I', _table, _partition);
+        EXECUTE format('ALTER TABLE %I SET SCHEMA %I', _partition, _archive_schema);
+    END IF;
+END
+$$;
+
+-- Partition maintenance runs with the service user, which has no DDL privileges.
+-- Functions are executed with privileges of the schema owner, search_path is fixed to the owner schema
+ALTER FUNCTION partition_create_month(text, date) SECURITY DEFINER;
+DO
+$$
+BEGIN
+    EXECUTE format('ALTER FUNCTION partition_create_month(text, date) SET search_path = %I, pg_temp', current_schema());
+    EXECUTE format('ALTER FUNCTION partition_remove(text, text, text) SET search_path = %I, pg_temp', current_schema());
+END
+$$;
+
+REVOKE ALL ON FUNCTION partition_create_month(text, date) FROM PUBLIC;
+REVOKE ALL ON FUNCTION partition_remove(text, text, text) FROM PUBLIC;
+
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+alter table jobs_dead drop column if exists tenant;
+alter table jobs drop column if exists tenant;
+COMMIT;
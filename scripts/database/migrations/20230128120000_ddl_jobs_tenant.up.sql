@@ -0,0 +1,7 @@
+BEGIN;
+alter table jobs add column if not exists tenant varchar(48);
+alter table jobs_dead add column if not exists tenant varchar(48);
+
+comment on column jobs.tenant is 'Tenant of the request which enqueued the job. Handler is called with this tenant';
+comment on column jobs_dead.tenant is 'Tenant of the job';
+COMMIT;
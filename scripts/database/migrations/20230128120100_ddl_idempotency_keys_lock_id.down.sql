@@ -0,0 +1,3 @@
+BEGIN;
+alter table idempotency_keys drop column if exists lock_id;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+alter table idempotency_keys add column if not exists lock_id varchar(64);
+
+comment on column idempotency_keys.lock_id is 'ID of the request holding the key. Only this request stores its response';
+COMMIT;